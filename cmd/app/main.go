@@ -1,44 +1,200 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"os"
 	"parse_server/internal/delivery/http/payload"
 	"parse_server/internal/delivery/http/request"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	domainRepo "parse_server/internal/domain/repository"
 	domainUC "parse_server/internal/domain/usecase"
 	"parse_server/internal/repository"
 	"parse_server/internal/usecase"
+	"path/filepath"
+	"time"
 )
 
 var P domainUC.Parser
 
 func main() {
+	enableTracing := flag.Bool("tracing", false, "enable internal transaction tracing")
+	watchMempool := flag.Bool("mempool", false, "watch pending transactions in the mempool")
+	trackBalances := flag.Bool("balances", false, "track and reconcile balances of subscribed addresses")
+	bloomFilter := flag.Bool("bloom-filter", false, "only download blocks whose logs bloom may contain token transfers of subscribed addresses")
+	abiDir := flag.String("abi-dir", "", "directory of additional JSON ABI files used to decode contract calls")
+	leaderElection := flag.String("leader-election", "", "elect a single polling leader among replicas: file (single host) or lease (requires a shared storage)")
+	leaderLockFile := flag.String("leader-lock-file", filepath.Join(os.TempDir(), "parse_server.lock"), "lock file used by file leader election")
+	leaderLeaseTTL := flag.Duration("leader-lease-ttl", 30*time.Second, "lease duration used by lease leader election")
+	pollMinInterval := flag.Duration("poll-min-interval", 0, "shortest wait between polls, used while catching up (default 200ms)")
+	pollMaxInterval := flag.Duration("poll-max-interval", 0, "longest wait between polls when no new block arrives (default 30s)")
+	blockTime := flag.Duration("block-time", 0, "expected block time; learned from block timestamps when not set")
+	fetchParallelism := flag.Int("fetch-parallelism", 0, "number of blocks fetched at the same time while catching up (default 4)")
+	fetchWindow := flag.Int("fetch-window", 0, "maximum number of fetched blocks waiting to be committed (default twice the parallelism)")
+	labelsFile := flag.String("labels", "", "JSON file mapping addresses to labels added to matched transactions")
+	tokensFile := flag.String("tokens", "", "JSON file of token metadata overrides keyed by contract address")
+	rpcURL := flag.String("rpc-url", domain.DefaultURL, "Ethereum JSON-RPC endpoint")
+	logsMaxRange := flag.Int("logs-max-range", 0, "largest block range of a single eth_getLogs request (default 2000)")
+	flag.Parse()
+
 	// 初始化 Storage 和 Notification
 	storage := repository.NewMemoryStorage()
 	notification := usecase.MustNotification()
-	ethClient := repository.MustETHClient(repository.ClientParam{})
+	ethClient := repository.MustETHClient(repository.ClientParam{URL: *rpcURL})
+	abiRegistry := repository.MustABIRegistry(repository.ABIRegistryParam{Dir: *abiDir})
+
+	// 執行多個實例時只有 leader 輪詢區塊，follower 只提供查詢 API
+	var leadership domainUC.Leadership
+	if elector := newLeaderElector(*leaderElection, *leaderLockFile, *leaderLeaseTTL, storage); elector != nil {
+		election := usecase.NewLeaderElection(usecase.LeaderElectionParam{Elector: elector, Interval: *leaderLeaseTTL / 3})
+		go election.Run()
+		leadership = election
+	}
 
 	// 初始化 Parser
 	P = usecase.NewEthereumParser(usecase.EthereumParserParam{
-		Storage:      storage,
-		Notification: notification,
-		EthClient:    ethClient,
+		Storage:               storage,
+		Notification:          notification,
+		EthClient:             ethClient,
+		EnableTracing:         *enableTracing,
+		ABIRegistry:           abiRegistry,
+		EnableBalanceTracking: *trackBalances,
+		EnableBloomFilter:     *bloomFilter,
+		Leadership:            leadership,
+		PollSchedule: usecase.PollScheduleParam{
+			MinInterval: *pollMinInterval,
+			MaxInterval: *pollMaxInterval,
+			BlockTime:   *blockTime,
+		},
+		FetchParallelism: *fetchParallelism,
+		FetchWindow:      *fetchWindow,
+		Pipeline:         newPipeline(abiRegistry, *labelsFile),
+		TokenOverrides:   mustTokenOverrides(*tokensFile),
+		MaxLogRange:      *logsMaxRange,
 	})
 
 	// 開始檢查區塊變化
 	go P.PollForChanges()
+	if *watchMempool {
+		go P.WatchPendingTransactions()
+	}
 
 	// 使用 gin.New() 創建 Gin 引擎
 	r := gin.New()
 
-	// 設定路由，只允許 POST 請求
+	// 設定路由
 	r.POST("/subscribe", SubscribeHandler)
+	r.GET("/subscriptions", SubscriptionsHandler)
+	r.GET("/subscriptions/:address", SubscriptionHandler)
+	r.DELETE("/subscriptions/:address", UnsubscribeHandler)
+	r.POST("/subscriptions/:address/pause", PauseSubscriptionHandler)
+	r.POST("/subscriptions/:address/resume", ResumeSubscriptionHandler)
+	r.GET("/transactions/:address", TransactionsHandler)
+	r.GET("/token-transfers/:address", TokenTransfersHandler)
+	r.GET("/tokens/:contract", TokenHandler)
+	r.POST("/event-subscriptions", SubscribeEventHandler)
+	r.GET("/event-subscriptions", EventSubscriptionsHandler)
+	r.DELETE("/event-subscriptions/:id", UnsubscribeEventHandler)
+	r.POST("/event-subscriptions/:id/backfill", EventBackfillHandler)
+	r.GET("/events/:id", ContractEventsHandler)
+	r.GET("/nft-transfers/:address", NFTTransfersHandler)
+	r.GET("/internal-transactions/:address", InternalTransactionsHandler)
+	r.GET("/pending-transactions/:address", PendingTransactionsHandler)
+	r.GET("/withdrawals/:address", WithdrawalsHandler)
+	r.GET("/block-rewards/:address", BlockRewardsHandler)
+	r.GET("/balances/:address", BalancesHandler)
+	r.GET("/metrics", MetricsHandler)
+	r.GET("/status", StatusHandler)
+	r.POST("/admin/reprocess", ReprocessHandler)
 
 	// 啟動伺服器
 	r.Run(":8080") // 預設監聽在 8080 埠
 
 }
 
+// newLeaderElector 依設定建立 leader 選舉的方式，未設定時返回 nil
+func newLeaderElector(kind, lockFile string, leaseTTL time.Duration, storage domainRepo.Storage) domainRepo.LeaderElector {
+	switch kind {
+	case "":
+		return nil
+	case "file":
+		return repository.MustFileLockElector(repository.FileLockParam{Path: lockFile})
+	case "lease":
+		hostname, _ := os.Hostname()
+		return repository.MustLeaseElector(repository.LeaseParam{
+			Storage: storage,
+			Holder:  fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+			TTL:     leaseTTL,
+		})
+	}
+	panic(fmt.Sprintf("unknown leader election %q", kind))
+}
+
+// newPipeline 註冊保存紀錄前執行的處理階段，依 Order 由小到大執行，內建的訂閱過濾與 ABI 解碼階段最先執行
+// 新增 enricher、filter 或 sink 時在此註冊，不需修改 Parser
+func newPipeline(abiRegistry domainRepo.ABIRegistry, labelsFile string) domainUC.Pipeline {
+	stages := usecase.DefaultPipelineStages(abiRegistry)
+	if labelsFile != "" {
+		stages = append(stages, domainUC.PipelineStage{
+			Name:      "address-labels",
+			Processor: mustAddressLabelProcessor(labelsFile),
+			Order:     100,
+			OnError:   domain.PipelineOnErrorContinue,
+		})
+	}
+	return usecase.MustTransactionPipeline(stages...)
+}
+
+// mustAddressLabelProcessor 從 JSON 檔案載入地址對應的標籤，格式為 {"0x...": "label"}
+func mustAddressLabelProcessor(path string) *usecase.AddressLabelProcessor {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	var labels map[string]string
+	if err := json.Unmarshal(data, &labels); err != nil {
+		panic(fmt.Errorf("address labels %s: %w", path, err))
+	}
+	processor, err := usecase.NewAddressLabelProcessor(usecase.AddressLabelParam{Labels: labels})
+	if err != nil {
+		panic(err)
+	}
+	return processor
+}
+
+// mustTokenOverrides 從 JSON 檔案載入本地設定的代幣資訊，格式為 {"0x...": {"name": "...", "symbol": "...", "decimals": 18}}
+// 只需設定要覆蓋的欄位，未設定檔案時返回 nil
+func mustTokenOverrides(path string) []domainUC.TokenMetadata {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	var tokens map[string]domainUC.TokenMetadata
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		panic(fmt.Errorf("token overrides %s: %w", path, err))
+	}
+
+	overrides := make([]domainUC.TokenMetadata, 0, len(tokens))
+	for contract, token := range tokens {
+		if token.Contract, err = domain.NormalizeAddress(contract); err != nil {
+			panic(fmt.Errorf("token override %q: %w", contract, err))
+		}
+		if token.Decimals != nil && (*token.Decimals < 0 || *token.Decimals > domain.MaxTokenDecimals) {
+			panic(fmt.Errorf("token override %q: decimals must be between 0 and %d", contract, domain.MaxTokenDecimals))
+		}
+		overrides = append(overrides, token)
+	}
+	return overrides
+}
+
 // SubscribeHandler 處理訂閱請求
 func SubscribeHandler(c *gin.Context) {
 	// 定義 request 結構
@@ -51,12 +207,334 @@ func SubscribeHandler(c *gin.Context) {
 	}
 
 	// 執行訂閱操作
-	status := P.Subscribe(req.Address)
-	if !status {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Failed to subscribe"})
+	if err := P.Subscribe(payload.NewSubscription(req)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Failed to subscribe", "error": err.Error()})
 		return
 	}
 
 	// 返還成功訊息
 	c.JSON(http.StatusOK, gin.H{"message": "success"})
 }
+
+// SubscribeEventHandler 訂閱合約事件，返回訂閱的 ID
+func SubscribeEventHandler(c *gin.Context) {
+	var req payload.EventSubscribeReq
+	if err := request.ShouldBindJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscription, err := payload.NewEventSubscription(req)
+	if err == nil {
+		subscription, err = P.SubscribeEvent(subscription)
+	}
+	if err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"message": "Failed to subscribe", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": subscription})
+}
+
+// EventSubscriptionsHandler 查詢所有事件訂閱
+func EventSubscriptionsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": P.GetEventSubscriptions()})
+}
+
+// UnsubscribeEventHandler 取消事件訂閱
+func UnsubscribeEventHandler(c *gin.Context) {
+	if err := P.UnsubscribeEvent(c.Param("id")); err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"message": "Failed to unsubscribe", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// EventBackfillHandler 在背景查詢事件訂閱在歷史區塊範圍內的事件，立即返回建立的工作，進度可由 /status 查詢
+func EventBackfillHandler(c *gin.Context) {
+	var req payload.EventBackfillReq
+	if err := request.ShouldBindJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := P.StartEventBackfill(payload.NewEventBackfillRequest(c.Param("id"), req))
+	if err != nil {
+		c.JSON(reprocessErrorStatus(err), gin.H{"message": "Failed to backfill events", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": job})
+}
+
+// ContractEventsHandler 查詢事件訂閱記錄的事件
+func ContractEventsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": P.GetContractEvents(c.Param("id"))})
+}
+
+// SubscriptionsHandler 查詢所有訂閱，可用 owner 過濾
+func SubscriptionsHandler(c *gin.Context) {
+	var req payload.SubscriptionsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	data := make([]domainUC.Subscription, 0)
+	for _, subscription := range P.GetSubscriptions() {
+		if req.Owner == "" || subscription.Owner == req.Owner {
+			data = append(data, subscription)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// SubscriptionHandler 查詢指定地址的訂閱
+func SubscriptionHandler(c *gin.Context) {
+	subscription, err := P.GetSubscription(c.Param("address"))
+	if err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": subscription})
+}
+
+// UnsubscribeHandler 取消指定地址的訂閱
+func UnsubscribeHandler(c *gin.Context) {
+	if err := P.Unsubscribe(c.Param("address")); err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"message": "Failed to unsubscribe", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// PauseSubscriptionHandler 暫停指定地址的訂閱
+func PauseSubscriptionHandler(c *gin.Context) {
+	if err := P.PauseSubscription(c.Param("address")); err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"message": "Failed to pause subscription", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// ResumeSubscriptionHandler 恢復指定地址的訂閱
+func ResumeSubscriptionHandler(c *gin.Context) {
+	if err := P.ResumeSubscription(c.Param("address")); err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"message": "Failed to resume subscription", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
+
+// addressErrorStatus 訂閱不存在或 ENS 名稱無法解析時返回 404，格式錯誤返回 400，其餘為節點錯誤
+func addressErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrSubscriptionNotFound), errors.Is(err, domain.ErrEventSubscriptionNotFound), errors.Is(err, domain.ErrENSNameNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidAddress), errors.Is(err, domain.ErrInvalidChecksum), errors.Is(err, domain.ErrInvalidENSName),
+		errors.Is(err, abi.ErrInvalidABI), errors.Is(err, domain.ErrInvalidTopic), errors.Is(err, domain.ErrTooManyTopics), errors.Is(err, domain.ErrEventTopicMismatch):
+		return http.StatusBadRequest
+	}
+	return http.StatusBadGateway
+}
+
+// resolveAddressParam 將路徑中的地址或 ENS 名稱轉為地址，失敗時直接回應錯誤
+func resolveAddressParam(c *gin.Context) (string, bool) {
+	address, _, err := P.ResolveAddress(c.Param("address"))
+	if err != nil {
+		c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+		return "", false
+	}
+	return address, true
+}
+
+// TransactionsHandler 查詢指定地址的交易紀錄，金額以 wei、gwei、ether 表示
+func TransactionsHandler(c *gin.Context) {
+	address, ok := resolveAddressParam(c)
+	if !ok {
+		return
+	}
+	transactions := P.GetTransactions(address)
+	data := make([]payload.TransactionResp, 0, len(transactions))
+	for _, tx := range transactions {
+		data = append(data, payload.NewTransactionResp(tx))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// TokenTransfersHandler 查詢指定地址的代幣轉帳紀錄
+func TokenTransfersHandler(c *gin.Context) {
+	var req payload.TokenTransfersReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	address, ok := resolveAddressParam(c)
+	if !ok {
+		return
+	}
+	var contract string
+	if req.Contract != "" {
+		var err error
+		if contract, _, err = P.ResolveAddress(req.Contract); err != nil {
+			c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	transfers := P.GetTokenTransfers(address)
+	tokens := make(map[string]domainUC.TokenMetadata)
+	data := make([]payload.TokenTransferResp, 0, len(transfers))
+	for _, transfer := range transfers {
+		if contract != "" && transfer.Contract != contract {
+			continue
+		}
+		token, ok := tokens[transfer.Contract]
+		if !ok {
+			// 查不到代幣資訊時只提供原始數量，不影響查詢轉帳紀錄
+			token, _ = P.GetTokenMetadata(transfer.Contract)
+			tokens[transfer.Contract] = token
+		}
+		if req.Decimals != nil {
+			token.Decimals = req.Decimals
+		}
+		data = append(data, payload.NewTokenTransferResp(transfer, token))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// TokenHandler 查詢代幣的名稱、代號與小數位數
+func TokenHandler(c *gin.Context) {
+	metadata, err := P.GetTokenMetadata(c.Param("contract"))
+	if err != nil {
+		c.JSON(tokenErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": metadata})
+}
+
+func tokenErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrNotTokenContract) {
+		return http.StatusNotFound
+	}
+	return addressErrorStatus(err)
+}
+
+// NFTTransfersHandler 查詢指定地址的 NFT 轉移紀錄
+func NFTTransfersHandler(c *gin.Context) {
+	address, ok := resolveAddressParam(c)
+	if !ok {
+		return
+	}
+	transfers := P.GetNFTTransfers(address)
+	c.JSON(http.StatusOK, gin.H{"data": transfers})
+}
+
+// InternalTransactionsHandler 查詢指定地址的內部交易紀錄
+func InternalTransactionsHandler(c *gin.Context) {
+	address, ok := resolveAddressParam(c)
+	if !ok {
+		return
+	}
+	transactions := P.GetInternalTransactions(address)
+	c.JSON(http.StatusOK, gin.H{"data": transactions})
+}
+
+// WithdrawalsHandler 查詢指定地址的驗證者提款紀錄，金額以 wei、gwei、ether 表示
+func WithdrawalsHandler(c *gin.Context) {
+	address, ok := resolveAddressParam(c)
+	if !ok {
+		return
+	}
+	withdrawals := P.GetWithdrawals(address)
+	data := make([]payload.WithdrawalResp, 0, len(withdrawals))
+	for _, withdrawal := range withdrawals {
+		data = append(data, payload.NewWithdrawalResp(withdrawal))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// BlockRewardsHandler 查詢指定地址作為 fee recipient 的手續費收入，金額以 wei、gwei、ether 表示
+func BlockRewardsHandler(c *gin.Context) {
+	address, ok := resolveAddressParam(c)
+	if !ok {
+		return
+	}
+	rewards := P.GetBlockRewards(address)
+	data := make([]payload.BlockRewardResp, 0, len(rewards))
+	for _, reward := range rewards {
+		data = append(data, payload.NewBlockRewardResp(reward))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// BalancesHandler 查詢指定地址的餘額快照，mismatch 代表實際餘額與觀察到的活動不符
+func BalancesHandler(c *gin.Context) {
+	address, ok := resolveAddressParam(c)
+	if !ok {
+		return
+	}
+	snapshots := P.GetBalanceHistory(address)
+	data := make([]payload.BalanceSnapshotResp, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		data = append(data, payload.NewBalanceSnapshotResp(snapshot))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// MetricsHandler 查詢 Parser 的計數
+func MetricsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": P.GetMetrics()})
+}
+
+// StatusHandler 查詢 Parser 的處理進度與落後程度
+func StatusHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": P.Status()})
+}
+
+// ReprocessHandler 在背景重新處理區塊範圍，立即返回建立的工作，進度可由 /status 查詢
+func ReprocessHandler(c *gin.Context) {
+	var req payload.ReprocessReq
+	if err := request.ShouldBindJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 地址可以是 ENS 名稱，先解析為地址
+	addresses := make([]string, 0, len(req.Addresses))
+	for _, item := range req.Addresses {
+		address, _, err := P.ResolveAddress(item)
+		if err != nil {
+			c.JSON(addressErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		addresses = append(addresses, address)
+	}
+
+	job, err := P.StartReprocess(payload.NewReprocessRequest(req, addresses))
+	if err != nil {
+		c.JSON(reprocessErrorStatus(err), gin.H{"message": "Failed to reprocess", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"data": job})
+}
+
+// reprocessErrorStatus 區塊範圍錯誤或沒有可處理的地址時返回 400，非 leader 的實例返回 503，其餘為節點錯誤
+func reprocessErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidBlockRange), errors.Is(err, domain.ErrBlockBeyondHead), errors.Is(err, domain.ErrNothingToReprocess):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotLeader):
+		return http.StatusServiceUnavailable
+	}
+	return addressErrorStatus(err)
+}
+
+// PendingTransactionsHandler 查詢指定地址的待處理交易
+func PendingTransactionsHandler(c *gin.Context) {
+	address, ok := resolveAddressParam(c)
+	if !ok {
+		return
+	}
+	transactions := P.GetPendingTransactions(address)
+	c.JSON(http.StatusOK, gin.H{"data": transactions})
+}
@@ -0,0 +1,46 @@
+package payload
+
+import (
+	"encoding/json"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/usecase"
+)
+
+type EventSubscribeReq struct {
+	// Contract 發出事件的合約地址或 ENS 名稱
+	Contract string `json:"contract" binding:"required"`
+	// Topics 依位置過濾 topics，同一位置的多個值為 OR，null 或空陣列不過濾，indexed address 可直接填地址
+	Topics [][]string `json:"topics" binding:"max=4"`
+	// ABI 事件的 JSON ABI fragment，也可以是只包含一個事件的 ABI 陣列
+	ABI   json.RawMessage `json:"abi" binding:"required"`
+	Label string          `json:"label" binding:"max=100"`
+}
+
+func NewEventSubscription(req EventSubscribeReq) (usecase.EventSubscription, error) {
+	event, err := abi.ParseEvent(req.ABI)
+	if err != nil {
+		return usecase.EventSubscription{}, err
+	}
+	return usecase.EventSubscription{
+		Contract: req.Contract,
+		Topics:   req.Topics,
+		Event:    event,
+		Label:    req.Label,
+	}, nil
+}
+
+type EventBackfillReq struct {
+	FromBlock *int `json:"fromBlock" binding:"required,min=0"`
+	ToBlock   *int `json:"toBlock" binding:"required,min=0"`
+	// Notify 是否發送通知，預設只保存紀錄
+	Notify bool `json:"notify"`
+}
+
+func NewEventBackfillRequest(subscriptionID string, req EventBackfillReq) usecase.EventBackfillRequest {
+	return usecase.EventBackfillRequest{
+		SubscriptionID: subscriptionID,
+		FromBlock:      *req.FromBlock,
+		ToBlock:        *req.ToBlock,
+		Notify:         req.Notify,
+	}
+}
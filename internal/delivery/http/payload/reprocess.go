@@ -0,0 +1,26 @@
+package payload
+
+import (
+	"parse_server/internal/domain/usecase"
+)
+
+type ReprocessReq struct {
+	FromBlock *int `json:"fromBlock" binding:"required,min=0"`
+	ToBlock   *int `json:"toBlock" binding:"required,min=0"`
+	// Addresses 選填的地址或 ENS 名稱，未提供時處理所有訂閱
+	Addresses []string `json:"addresses"`
+	// Notify 是否發送通知，預設只保存紀錄
+	Notify bool `json:"notify"`
+	// Concurrency 同時處理的區塊數，未提供時使用預設值
+	Concurrency int `json:"concurrency" binding:"min=0,max=16"`
+}
+
+func NewReprocessRequest(req ReprocessReq, addresses []string) usecase.ReprocessRequest {
+	return usecase.ReprocessRequest{
+		FromBlock:   *req.FromBlock,
+		ToBlock:     *req.ToBlock,
+		Addresses:   addresses,
+		Notify:      req.Notify,
+		Concurrency: req.Concurrency,
+	}
+}
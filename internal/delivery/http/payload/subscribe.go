@@ -1,5 +1,60 @@
 package payload
 
+import (
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/usecase"
+	"time"
+)
+
 type SubscribeReq struct {
 	Address string `json:"address" binding:"required"`
+	// Filter 選填的過濾條件，未提供時訂閱地址的所有交易
+	Filter *SubscriptionFilterReq `json:"filter"`
+	Label  string                 `json:"label" binding:"max=100"`
+	Owner  string                 `json:"owner" binding:"max=100"`
+	// ExpiresAt RFC 3339 格式的過期時間，未提供時永不過期
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func NewSubscription(req SubscribeReq) usecase.Subscription {
+	return usecase.Subscription{
+		Address:   req.Address,
+		Filter:    NewSubscriptionFilter(req.Filter),
+		Label:     req.Label,
+		Owner:     req.Owner,
+		ExpiresAt: req.ExpiresAt,
+	}
+}
+
+type SubscriptionsReq struct {
+	Owner string `form:"owner"`
+}
+
+type SubscriptionFilterReq struct {
+	// Direction incoming、outgoing 或 both，預設為 both
+	Direction string `json:"direction" binding:"omitempty,oneof=incoming outgoing both"`
+	// MinValue 最低金額（wei），接受十進位或 0x 開頭的十六進位字串
+	MinValue domain.BigInt `json:"minValue"`
+	// TokenContracts 代幣與 NFT 轉移的合約允許清單
+	TokenContracts []string `json:"tokenContracts"`
+	// MethodSelectors 交易呼叫的函式 selector，例如 0x095ea7b3
+	MethodSelectors []string `json:"methodSelectors"`
+	// IncludeFailed 是否包含執行失敗的交易，預設為 true
+	IncludeFailed *bool `json:"includeFailed"`
+	// TokensOnly 只追蹤代幣與 NFT 轉移、提款與手續費收入，不記錄 ETH 交易
+	TokensOnly bool `json:"tokensOnly"`
+}
+
+func NewSubscriptionFilter(req *SubscriptionFilterReq) usecase.SubscriptionFilter {
+	if req == nil {
+		return usecase.SubscriptionFilter{}
+	}
+	return usecase.SubscriptionFilter{
+		Direction:       req.Direction,
+		MinValue:        req.MinValue,
+		TokenContracts:  req.TokenContracts,
+		MethodSelectors: req.MethodSelectors,
+		ExcludeFailed:   req.IncludeFailed != nil && !*req.IncludeFailed,
+		TokensOnly:      req.TokensOnly,
+	}
 }
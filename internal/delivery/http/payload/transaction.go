@@ -0,0 +1,253 @@
+package payload
+
+import (
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/usecase"
+	"time"
+)
+
+// AmountResp 以 wei、gwei、ether 三種單位表示的 ETH 數量，皆為十進位字串
+type AmountResp struct {
+	Wei   string `json:"wei"`
+	Gwei  string `json:"gwei"`
+	Ether string `json:"ether"`
+}
+
+func NewAmountResp(value domain.BigInt) AmountResp {
+	return AmountResp{
+		Wei:   value.FormatUnits(domain.WeiDecimals),
+		Gwei:  value.FormatUnits(domain.GweiDecimals),
+		Ether: value.FormatUnits(domain.EtherDecimals),
+	}
+}
+
+// TokenAmountResp 代幣數量，提供小數位數時 Formatted 為依小數位數換算後的值
+type TokenAmountResp struct {
+	Raw       string `json:"raw"`
+	Decimals  *int   `json:"decimals,omitempty"`
+	Formatted string `json:"formatted,omitempty"`
+}
+
+func NewTokenAmountResp(value domain.BigInt, decimals *int) TokenAmountResp {
+	resp := TokenAmountResp{Raw: value.String()}
+	if decimals != nil {
+		resp.Decimals = decimals
+		resp.Formatted = value.FormatUnits(*decimals)
+	}
+	return resp
+}
+
+// newOptionalAmountResp 只有特定交易類型才有的金額，為 0 時省略
+func newOptionalAmountResp(value domain.BigInt) *AmountResp {
+	if value.IsZero() {
+		return nil
+	}
+	resp := NewAmountResp(value)
+	return &resp
+}
+
+type TransactionResp struct {
+	Hash                 string              `json:"hash"`
+	Type                 domain.TxType       `json:"type"`
+	BlockHash            string              `json:"blockHash"`
+	BlockNumber          string              `json:"blockNumber"`
+	From                 string              `json:"from"`
+	To                   string              `json:"to"`
+	Value                AmountResp          `json:"value"`
+	GasPrice             AmountResp          `json:"gasPrice"`
+	MaxFeePerGas         *AmountResp         `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *AmountResp         `json:"maxPriorityFeePerGas,omitempty"`
+	GasUsed              string              `json:"gasUsed"`
+	Fee                  AmountResp          `json:"fee"`
+	MaxFeePerBlobGas     *AmountResp         `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes  []string            `json:"blobVersionedHashes,omitempty"`
+	BlobGasUsed          string              `json:"blobGasUsed,omitempty"`
+	BlobFee              *AmountResp         `json:"blobFee,omitempty"`
+	L1Fee                *AmountResp         `json:"l1Fee,omitempty"`
+	AccessList           []AccessListResp    `json:"accessList,omitempty"`
+	YParity              string              `json:"yParity,omitempty"`
+	AuthorizationList    []AuthorizationResp `json:"authorizationList,omitempty"`
+	SourceHash           string              `json:"sourceHash,omitempty"`
+	Mint                 *AmountResp         `json:"mint,omitempty"`
+	Nonce                string              `json:"nonce"`
+	Input                string              `json:"input"`
+	Failed               bool                `json:"failed"`
+	Method               *abi.Call           `json:"method,omitempty"`
+	Events               []abi.Call          `json:"events,omitempty"`
+	Labels               map[string]string   `json:"labels,omitempty"`
+}
+
+// AccessListResp EIP-2930 access list 的項目
+type AccessListResp struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+// AuthorizationResp EIP-7702 交易中授權 EOA 委派執行的合約與授權的簽名
+type AuthorizationResp struct {
+	ChainID string `json:"chainId"`
+	Address string `json:"address"`
+	Nonce   string `json:"nonce"`
+	YParity string `json:"yParity"`
+	R       string `json:"r"`
+	S       string `json:"s"`
+}
+
+func NewTransactionResp(tx usecase.Transaction) TransactionResp {
+	resp := TransactionResp{
+		Hash:                 tx.Hash,
+		Type:                 tx.Type,
+		BlockHash:            tx.BlockHash,
+		BlockNumber:          tx.BlockNumber,
+		From:                 tx.From,
+		To:                   tx.To,
+		Value:                NewAmountResp(tx.Value),
+		GasPrice:             NewAmountResp(tx.GasPrice),
+		MaxFeePerGas:         newOptionalAmountResp(tx.MaxFeePerGas),
+		MaxPriorityFeePerGas: newOptionalAmountResp(tx.MaxPriorityFeePerGas),
+		GasUsed:              tx.GasUsed.String(),
+		Fee:                  NewAmountResp(tx.Fee),
+		MaxFeePerBlobGas:     newOptionalAmountResp(tx.MaxFeePerBlobGas),
+		BlobVersionedHashes:  tx.BlobVersionedHashes,
+		BlobFee:              newOptionalAmountResp(tx.BlobFee),
+		L1Fee:                newOptionalAmountResp(tx.L1Fee),
+		YParity:              tx.YParity,
+		SourceHash:           tx.SourceHash,
+		Mint:                 newOptionalAmountResp(tx.Mint),
+		Nonce:                tx.Nonce,
+		Input:                tx.Input.String(),
+		Failed:               tx.Failed,
+		Method:               tx.Method,
+		Events:               tx.Events,
+		Labels:               tx.Labels,
+	}
+	if !tx.BlobGasUsed.IsZero() {
+		resp.BlobGasUsed = tx.BlobGasUsed.String()
+	}
+	for _, entry := range tx.AccessList {
+		resp.AccessList = append(resp.AccessList, AccessListResp{Address: entry.Address, StorageKeys: entry.StorageKeys})
+	}
+	for _, authorization := range tx.AuthorizationList {
+		resp.AuthorizationList = append(resp.AuthorizationList, AuthorizationResp{
+			ChainID: authorization.ChainID,
+			Address: authorization.Address,
+			Nonce:   authorization.Nonce,
+			YParity: authorization.YParity,
+			R:       authorization.R,
+			S:       authorization.S,
+		})
+	}
+	return resp
+}
+
+type TokenTransferResp struct {
+	TxHash      string          `json:"txHash"`
+	BlockHash   string          `json:"blockHash"`
+	BlockNumber string          `json:"blockNumber"`
+	LogIndex    string          `json:"logIndex"`
+	Contract    string          `json:"contract"`
+	Token       *TokenResp      `json:"token,omitempty"`
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Amount      TokenAmountResp `json:"amount"`
+}
+
+// TokenResp 代幣的名稱與代號，兩者皆未知時省略
+type TokenResp struct {
+	Name   string `json:"name,omitempty"`
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// NewTokenTransferResp 以代幣資訊的小數位數換算金額，代幣資訊未知時只提供原始數量
+func NewTokenTransferResp(transfer usecase.TokenTransfer, token usecase.TokenMetadata) TokenTransferResp {
+	resp := TokenTransferResp{
+		TxHash:      transfer.TxHash,
+		BlockHash:   transfer.BlockHash,
+		BlockNumber: transfer.BlockNumber,
+		LogIndex:    transfer.LogIndex,
+		Contract:    transfer.Contract,
+		From:        transfer.From,
+		To:          transfer.To,
+		Amount:      NewTokenAmountResp(transfer.Amount, token.Decimals),
+	}
+	if token.Name != "" || token.Symbol != "" {
+		resp.Token = &TokenResp{Name: token.Name, Symbol: token.Symbol}
+	}
+	return resp
+}
+
+// TokenTransfersReq 查詢代幣轉帳時可選擇只返回指定合約的轉帳，未指定時返回所有合約的轉帳
+// Decimals 以指定的小數位數換算金額，只適用於單一代幣，因此必須同時指定 Contract，未指定時使用代幣資訊的小數位數
+type TokenTransfersReq struct {
+	// Contract 代幣合約的地址或 ENS 名稱
+	Contract string `form:"contract" binding:"required_with=Decimals"`
+	Decimals *int   `form:"decimals" binding:"omitempty,min=0,max=77"`
+}
+
+type WithdrawalResp struct {
+	BlockHash      string     `json:"blockHash"`
+	BlockNumber    string     `json:"blockNumber"`
+	Index          string     `json:"index"`
+	ValidatorIndex string     `json:"validatorIndex"`
+	Address        string     `json:"address"`
+	Amount         AmountResp `json:"amount"`
+}
+
+func NewWithdrawalResp(withdrawal usecase.Withdrawal) WithdrawalResp {
+	return WithdrawalResp{
+		BlockHash:      withdrawal.BlockHash,
+		BlockNumber:    withdrawal.BlockNumber,
+		Index:          withdrawal.Index,
+		ValidatorIndex: withdrawal.ValidatorIndex,
+		Address:        withdrawal.Address,
+		Amount:         NewAmountResp(withdrawal.Amount),
+	}
+}
+
+type BlockRewardResp struct {
+	BlockHash        string     `json:"blockHash"`
+	BlockNumber      string     `json:"blockNumber"`
+	FeeRecipient     string     `json:"feeRecipient"`
+	BaseFeePerGas    AmountResp `json:"baseFeePerGas"`
+	PriorityFees     AmountResp `json:"priorityFees"`
+	BurntFees        AmountResp `json:"burntFees"`
+	TransactionCount int        `json:"transactionCount"`
+}
+
+func NewBlockRewardResp(reward usecase.BlockReward) BlockRewardResp {
+	return BlockRewardResp{
+		BlockHash:        reward.BlockHash,
+		BlockNumber:      reward.BlockNumber,
+		FeeRecipient:     reward.FeeRecipient,
+		BaseFeePerGas:    NewAmountResp(reward.BaseFeePerGas),
+		PriorityFees:     NewAmountResp(reward.PriorityFees),
+		BurntFees:        NewAmountResp(reward.BurntFees),
+		TransactionCount: reward.TransactionCount,
+	}
+}
+
+// BalanceSnapshotResp 餘額快照，Change 與 Discrepancy 可能為負數
+type BalanceSnapshotResp struct {
+	BlockNumber string     `json:"blockNumber"`
+	Balance     AmountResp `json:"balance"`
+	Expected    AmountResp `json:"expected"`
+	Change      AmountResp `json:"change"`
+	Discrepancy AmountResp `json:"discrepancy"`
+	Mismatch    bool       `json:"mismatch"`
+	Baseline    bool       `json:"baseline"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+func NewBalanceSnapshotResp(snapshot usecase.BalanceSnapshot) BalanceSnapshotResp {
+	return BalanceSnapshotResp{
+		BlockNumber: snapshot.BlockNumber,
+		Balance:     NewAmountResp(snapshot.Balance),
+		Expected:    NewAmountResp(snapshot.Expected),
+		Change:      NewAmountResp(snapshot.Change),
+		Discrepancy: NewAmountResp(snapshot.Discrepancy),
+		Mismatch:    !snapshot.Discrepancy.IsZero(),
+		Baseline:    snapshot.Baseline,
+		CreatedAt:   snapshot.CreatedAt,
+	}
+}
@@ -0,0 +1,210 @@
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/sha3"
+	"parse_server/internal/domain"
+	"strings"
+)
+
+var (
+	ErrInvalidABI  = errors.New("invalid abi")
+	ErrInvalidData = errors.New("invalid abi encoded data")
+)
+
+// Argument 函式或事件的參數定義
+type Argument struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Indexed    bool       `json:"indexed"`
+	Components []Argument `json:"components"`
+}
+
+// Entry JSON ABI 中的一個項目，僅 function 與 event 會被使用
+type Entry struct {
+	Type      string     `json:"type"`
+	Name      string     `json:"name"`
+	Inputs    []Argument `json:"inputs"`
+	Anonymous bool       `json:"anonymous"`
+}
+
+// Parse 解析 JSON ABI，同時支援純 ABI 陣列與 Hardhat / Truffle 編譯產物中的 "abi" 欄位
+func Parse(data []byte) ([]Entry, error) {
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err == nil {
+		return entries, validate(entries)
+	}
+
+	var artifact struct {
+		ABI []Entry `json:"abi"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidABI, err)
+	}
+	if artifact.ABI == nil {
+		return nil, fmt.Errorf("%w: no abi entries found", ErrInvalidABI)
+	}
+	return artifact.ABI, validate(artifact.ABI)
+}
+
+// ParseEvent 解析單一事件的 ABI fragment，可以是事件物件，或只包含一個事件的 ABI 陣列或編譯產物
+func ParseEvent(data []byte) (Entry, error) {
+	var entries []Entry
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err == nil && entry.Type != "" {
+		entries = []Entry{entry}
+		if err := validate(entries); err != nil {
+			return Entry{}, err
+		}
+	} else if entries, err = Parse(data); err != nil {
+		return Entry{}, err
+	}
+
+	var events []Entry
+	for _, entry := range entries {
+		if entry.Type == "event" {
+			events = append(events, entry)
+		}
+	}
+	if len(events) != 1 || events[0].Name == "" {
+		return Entry{}, fmt.Errorf("%w: expected a single named event, got %d events", ErrInvalidABI, len(events))
+	}
+	return events[0], nil
+}
+
+// validate 確認所有參數型別都能被解析，避免在解碼時才發現錯誤
+func validate(entries []Entry) error {
+	for _, entry := range entries {
+		for _, arg := range entry.Inputs {
+			if _, err := parseType(arg.Type, arg.Components); err != nil {
+				return fmt.Errorf("%w: %s.%s: %v", ErrInvalidABI, entry.Name, arg.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Signature 返回標準簽名，例如 "approve(address,uint256)"
+func (e Entry) Signature() string {
+	types := make([]string, 0, len(e.Inputs))
+	for _, arg := range e.Inputs {
+		types = append(types, arg.canonicalType())
+	}
+	return e.Name + "(" + strings.Join(types, ",") + ")"
+}
+
+// Selector 函式的 4 bytes selector
+func (e Entry) Selector() [4]byte {
+	var selector [4]byte
+	copy(selector[:], keccak256([]byte(e.Signature())))
+	return selector
+}
+
+// Topic 事件的 topic0
+func (e Entry) Topic() domain.Hash {
+	return domain.Hash("0x" + hex.EncodeToString(keccak256([]byte(e.Signature()))))
+}
+
+// IndexedCount 事件中 indexed 參數的數量
+func (e Entry) IndexedCount() int {
+	count := 0
+	for _, arg := range e.Inputs {
+		if arg.Indexed {
+			count++
+		}
+	}
+	return count
+}
+
+// DecodeInput 解析交易的 input，input 需包含 4 bytes selector
+func (e Entry) DecodeInput(input []byte) (*Call, error) {
+	if len(input) < 4 {
+		return nil, fmt.Errorf("%w: input shorter than selector", ErrInvalidData)
+	}
+	params, err := decodeArguments(e.Inputs, input[4:])
+	if err != nil {
+		return nil, err
+	}
+	return &Call{Name: e.Name, Signature: e.Signature(), Params: params}, nil
+}
+
+// DecodeLog 解析事件，indexed 參數取自 topics，其餘取自 data
+// 非匿名事件的 topics[0] 為事件簽名；indexed 的動態型別只會留下其 keccak256 哈希值
+func (e Entry) DecodeLog(topics []domain.Hash, data []byte) (*Call, error) {
+	if !e.Anonymous {
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("%w: missing event signature topic", ErrInvalidData)
+		}
+		topics = topics[1:]
+	}
+	if len(topics) != e.IndexedCount() {
+		return nil, fmt.Errorf("%w: expected %d indexed topics, got %d", ErrInvalidData, e.IndexedCount(), len(topics))
+	}
+
+	var nonIndexed []Argument
+	for _, arg := range e.Inputs {
+		if !arg.Indexed {
+			nonIndexed = append(nonIndexed, arg)
+		}
+	}
+	dataParams, err := decodeArguments(nonIndexed, data)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]Param, 0, len(e.Inputs))
+	for _, arg := range e.Inputs {
+		if !arg.Indexed {
+			params = append(params, dataParams[0])
+			dataParams = dataParams[1:]
+			continue
+		}
+
+		word, err := hex.DecodeString(strings.TrimPrefix(topics[0].String(), "0x"))
+		if err != nil || len(word) != wordSize {
+			return nil, fmt.Errorf("%w: invalid topic %q", ErrInvalidData, topics[0])
+		}
+		topics = topics[1:]
+
+		t, _ := parseType(arg.Type, arg.Components)
+		param := Param{Name: arg.Name, Type: arg.canonicalType()}
+		if t.isDynamic() || t.kind == kindArray || t.kind == kindTuple {
+			param.Value = domain.Bytes(word)
+		} else if param.Value, err = decodeAt(t, word); err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+
+	return &Call{Name: e.Name, Signature: e.Signature(), Params: params}, nil
+}
+
+// canonicalType 返回用於簽名的型別，tuple 會展開為 (type1,type2)，uint / int 會補上位數
+func (a Argument) canonicalType() string {
+	if strings.HasPrefix(a.Type, "tuple") {
+		types := make([]string, 0, len(a.Components))
+		for _, component := range a.Components {
+			types = append(types, component.canonicalType())
+		}
+		return "(" + strings.Join(types, ",") + ")" + strings.TrimPrefix(a.Type, "tuple")
+	}
+
+	base, suffix := a.Type, ""
+	if i := strings.Index(a.Type, "["); i >= 0 {
+		base, suffix = a.Type[:i], a.Type[i:]
+	}
+	switch base {
+	case "uint", "int":
+		base += "256"
+	}
+	return base + suffix
+}
+
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
@@ -0,0 +1,226 @@
+package abi
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"parse_server/internal/domain"
+	"strings"
+	"testing"
+)
+
+// encodeWords 將多個 32 bytes 的十六進位字串組合為 ABI 編碼
+func encodeWords(words ...string) []byte {
+	var data []byte
+	for _, word := range words {
+		b, _ := hex.DecodeString(strings.Repeat("0", 64-len(word)) + word)
+		data = append(data, b...)
+	}
+	return data
+}
+
+func TestEntry_Signature(t *testing.T) {
+	tests := []struct {
+		name      string
+		entry     Entry
+		signature string
+		selector  string
+	}{
+		{
+			name: "ERC-20 approve",
+			entry: Entry{Type: "function", Name: "approve", Inputs: []Argument{
+				{Name: "spender", Type: "address"},
+				{Name: "amount", Type: "uint256"},
+			}},
+			signature: "approve(address,uint256)",
+			selector:  "095ea7b3",
+		},
+		{
+			name: "Shorthand uint is expanded",
+			entry: Entry{Type: "function", Name: "transfer", Inputs: []Argument{
+				{Name: "to", Type: "address"},
+				{Name: "amount", Type: "uint"},
+			}},
+			signature: "transfer(address,uint256)",
+			selector:  "a9059cbb",
+		},
+		{
+			name: "Tuple is expanded",
+			entry: Entry{Type: "function", Name: "exactInputSingle", Inputs: []Argument{
+				{Name: "params", Type: "tuple", Components: []Argument{
+					{Type: "address"}, {Type: "address"}, {Type: "uint24"}, {Type: "address"},
+					{Type: "uint256"}, {Type: "uint256"}, {Type: "uint256"}, {Type: "uint160"},
+				}},
+			}},
+			signature: "exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))",
+			selector:  "414bf389",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector := tt.entry.Selector()
+			assert.Equal(t, tt.signature, tt.entry.Signature())
+			assert.Equal(t, tt.selector, hex.EncodeToString(selector[:]))
+		})
+	}
+}
+
+func TestEntry_DecodeInput(t *testing.T) {
+	approve := Entry{Type: "function", Name: "approve", Inputs: []Argument{
+		{Name: "spender", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	}}
+	swap := Entry{Type: "function", Name: "swapExactETHForTokens", Inputs: []Argument{
+		{Name: "amountOutMin", Type: "uint256"},
+		{Name: "path", Type: "address[]"},
+		{Name: "to", Type: "address"},
+		{Name: "deadline", Type: "uint256"},
+	}}
+	setName := Entry{Type: "function", Name: "setName", Inputs: []Argument{
+		{Name: "name", Type: "string"},
+		{Name: "delta", Type: "int256"},
+	}}
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		input    []byte
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "Static arguments",
+			entry:    approve,
+			input:    append([]byte{0x09, 0x5e, 0xa7, 0xb3}, encodeWords("0123", "64")...),
+			expected: "approve(spender: 0x0000000000000000000000000000000000000123, amount: 100)",
+		},
+		{
+			name:  "Dynamic address array",
+			entry: swap,
+			input: append([]byte{0x7f, 0xf3, 0x6a, 0xb5}, encodeWords(
+				"1", "80", "0456", "ff", // amountOutMin, path offset, to, deadline
+				"2", "0aaa", "0bbb", // path
+			)...),
+			expected: "swapExactETHForTokens(amountOutMin: 1, path: [0x0000000000000000000000000000000000000aaa, 0x0000000000000000000000000000000000000bbb], to: 0x0000000000000000000000000000000000000456, deadline: 255)",
+		},
+		{
+			name:  "String and negative int",
+			entry: setName,
+			input: append([]byte{0, 0, 0, 0}, encodeWords(
+				"40", strings.Repeat("f", 64),
+				"3", "616263"+strings.Repeat("0", 58),
+			)...),
+			expected: "setName(name: abc, delta: -1)",
+		},
+		{
+			name:    "Truncated data",
+			entry:   approve,
+			input:   append([]byte{0x09, 0x5e, 0xa7, 0xb3}, encodeWords("0123")...),
+			wantErr: ErrInvalidData,
+		},
+		{
+			name:    "Offset out of range",
+			entry:   swap,
+			input:   append([]byte{0x7f, 0xf3, 0x6a, 0xb5}, encodeWords("1", "ffff", "0456", "ff")...),
+			wantErr: ErrInvalidData,
+		},
+		{
+			name:    "Fixed array longer than data",
+			entry:   Entry{Type: "function", Name: "f", Inputs: []Argument{{Name: "x", Type: "uint256[60000]"}}},
+			input:   append([]byte{0, 0, 0, 0}, encodeWords("1", "2")...),
+			wantErr: ErrInvalidData,
+		},
+		{
+			name:    "Slice of arrays longer than data",
+			entry:   Entry{Type: "function", Name: "f", Inputs: []Argument{{Name: "x", Type: "uint256[1000][]"}}},
+			input:   append([]byte{0, 0, 0, 0}, encodeWords("20", "1", "2")...),
+			wantErr: ErrInvalidData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, err := tt.entry.DecodeInput(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, call.String())
+		})
+	}
+}
+
+func TestEntry_DecodeLog(t *testing.T) {
+	transfer := Entry{Type: "event", Name: "Transfer", Inputs: []Argument{
+		{Name: "from", Type: "address", Indexed: true},
+		{Name: "to", Type: "address", Indexed: true},
+		{Name: "amount", Type: "uint256"},
+	}}
+	topics := []domain.Hash{
+		transfer.Topic(),
+		"0x0000000000000000000000000000000000000000000000000000000000000123",
+		"0x0000000000000000000000000000000000000000000000000000000000000456",
+	}
+
+	call, err := transfer.DecodeLog(topics, encodeWords("3e8"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Hash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"), transfer.Topic())
+	assert.Equal(t, "Transfer(from: 0x0000000000000000000000000000000000000123, to: 0x0000000000000000000000000000000000000456, amount: 1000)", call.String())
+
+	// indexed 數量不符時視為不同的事件定義
+	_, err = transfer.DecodeLog(topics[:2], encodeWords("3e8"))
+	assert.ErrorIs(t, err, ErrInvalidData)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		count   int
+		wantErr bool
+	}{
+		{name: "ABI array", data: `[{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"}]}]`, count: 1},
+		{name: "Compiler artifact", data: `{"contractName":"Token","abi":[{"type":"event","name":"Ping","inputs":[]}]}`, count: 1},
+		{name: "Unsupported type", data: `[{"type":"function","name":"f","inputs":[{"name":"x","type":"fixed128x18"}]}]`, wantErr: true},
+		{name: "Huge fixed array", data: `[{"type":"event","name":"E","inputs":[{"name":"x","type":"uint256[1000000000000]"}]}]`, wantErr: true},
+		{name: "Huge nested array", data: `[{"type":"event","name":"E","inputs":[{"name":"x","type":"uint8[4294967296][4294967296]"}]}]`, wantErr: true},
+		{name: "Nested array too large", data: `[{"type":"event","name":"E","inputs":[{"name":"x","type":"uint8[65536][65536]"}]}]`, wantErr: true},
+		{name: "Tuple too large", data: `[{"type":"event","name":"E","inputs":[{"name":"x","type":"tuple","components":[{"name":"a","type":"uint256[500][1000]"},{"name":"b","type":"uint256[500][1000]"}]}]}]`, wantErr: true},
+		{name: "Not an ABI", data: `{"name":"x"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Parse([]byte(tt.data))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidABI)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, entries, tt.count)
+		})
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	swap := `{"type":"event","name":"Swap","inputs":[{"name":"sender","type":"address","indexed":true},{"name":"amount0In","type":"uint256"}]}`
+
+	for _, data := range []string{swap, "[" + swap + `,{"type":"function","name":"swap","inputs":[]}]`, `{"abi":[` + swap + "]}"} {
+		entry, err := ParseEvent([]byte(data))
+		assert.NoError(t, err)
+		assert.Equal(t, "Swap(address,uint256)", entry.Signature())
+		assert.Equal(t, 1, entry.IndexedCount())
+	}
+
+	for _, data := range []string{
+		`{"type":"function","name":"swap","inputs":[]}`,
+		"[" + swap + "," + swap + "]",
+		`{"type":"event","inputs":[]}`,
+		`{"type":"event","name":"Bad","inputs":[{"name":"x","type":"fixed128x18"}]}`,
+		`"Swap(address,uint256)"`,
+	} {
+		_, err := ParseEvent([]byte(data))
+		assert.ErrorIs(t, err, ErrInvalidABI, data)
+	}
+}
@@ -0,0 +1,60 @@
+package abi
+
+import (
+	"fmt"
+	"parse_server/internal/domain"
+	"strings"
+)
+
+// Call 解碼後的函式呼叫或事件
+type Call struct {
+	Name      string  `json:"name"`
+	Signature string  `json:"signature"`
+	Params    []Param `json:"params"`
+}
+
+// Param 解碼後的參數
+// Value 依型別為 domain.BigInt（uint / int）、string（address / string）、bool、domain.Bytes、[]any（陣列）或 []Param（tuple）
+type Param struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// String 返回易讀的格式，例如 "approve(spender: 0x..., amount: 100)"
+func (c Call) String() string {
+	return c.Name + "(" + formatParams(c.Params) + ")"
+}
+
+func formatParams(params []Param) string {
+	parts := make([]string, 0, len(params))
+	for i, param := range params {
+		name := param.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		parts = append(parts, name+": "+formatValue(param.Value))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case domain.BigInt:
+		return v.String()
+	case domain.Bytes:
+		return v.String()
+	case string:
+		return v
+	case []Param:
+		return "(" + formatParams(v) + ")"
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,284 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"parse_server/internal/domain"
+	"strconv"
+	"strings"
+)
+
+const (
+	wordSize = 32
+	// maxArrayLength 固定長度陣列的長度上限
+	maxArrayLength = 1 << 16
+	// maxHeadSize 單一型別在 head 中佔用的 bytes 上限，避免 ABI 宣告過大的陣列導致解碼時配置大量記憶體
+	maxHeadSize = 1 << 24
+)
+
+type kind int
+
+const (
+	kindUint kind = iota
+	kindInt
+	kindAddress
+	kindBool
+	kindFixedBytes
+	kindBytes
+	kindString
+	kindSlice
+	kindArray
+	kindTuple
+)
+
+// abiType 解析後的 ABI 型別
+type abiType struct {
+	kind       kind
+	size       int // uint / int 的位數，或 bytesN 的 N
+	length     int // 固定長度陣列的長度
+	elem       *abiType
+	components []abiType
+	names      []string
+	canonical  []string
+}
+
+// parseType 解析型別字串，例如 "uint256"、"address[]"、"tuple[2]"
+func parseType(t string, components []Argument) (abiType, error) {
+	if strings.HasSuffix(t, "]") {
+		i := strings.LastIndex(t, "[")
+		if i < 0 {
+			return abiType{}, fmt.Errorf("invalid type %q", t)
+		}
+		elem, err := parseType(t[:i], components)
+		if err != nil {
+			return abiType{}, err
+		}
+		dims := t[i+1 : len(t)-1]
+		if dims == "" {
+			return abiType{kind: kindSlice, elem: &elem}, nil
+		}
+		length, err := strconv.Atoi(dims)
+		if err != nil || length <= 0 || length > maxArrayLength {
+			return abiType{}, fmt.Errorf("invalid array length in %q", t)
+		}
+		if elem.headSize() > maxHeadSize/length {
+			return abiType{}, fmt.Errorf("array %q is too large", t)
+		}
+		return abiType{kind: kindArray, elem: &elem, length: length}, nil
+	}
+
+	switch {
+	case t == "address":
+		return abiType{kind: kindAddress}, nil
+	case t == "bool":
+		return abiType{kind: kindBool}, nil
+	case t == "string":
+		return abiType{kind: kindString}, nil
+	case t == "bytes":
+		return abiType{kind: kindBytes}, nil
+	case t == "tuple":
+		tuple := abiType{kind: kindTuple}
+		for _, component := range components {
+			ct, err := parseType(component.Type, component.Components)
+			if err != nil {
+				return abiType{}, err
+			}
+			tuple.components = append(tuple.components, ct)
+			tuple.names = append(tuple.names, component.Name)
+			tuple.canonical = append(tuple.canonical, component.canonicalType())
+			if tuple.headSize() > maxHeadSize {
+				return abiType{}, fmt.Errorf("tuple is too large at component %q", component.Name)
+			}
+		}
+		return tuple, nil
+	case strings.HasPrefix(t, "bytes"):
+		size, err := strconv.Atoi(t[len("bytes"):])
+		if err != nil || size < 1 || size > 32 {
+			return abiType{}, fmt.Errorf("invalid type %q", t)
+		}
+		return abiType{kind: kindFixedBytes, size: size}, nil
+	case strings.HasPrefix(t, "uint"), strings.HasPrefix(t, "int"):
+		k, bits := kindUint, strings.TrimPrefix(t, "uint")
+		if !strings.HasPrefix(t, "uint") {
+			k, bits = kindInt, strings.TrimPrefix(t, "int")
+		}
+		size := 256
+		if bits != "" {
+			var err error
+			size, err = strconv.Atoi(bits)
+			if err != nil || size < 8 || size > 256 || size%8 != 0 {
+				return abiType{}, fmt.Errorf("invalid type %q", t)
+			}
+		}
+		return abiType{kind: k, size: size}, nil
+	}
+
+	return abiType{}, fmt.Errorf("unsupported type %q", t)
+}
+
+// isDynamic 動態型別在 head 中只存放 offset
+func (t abiType) isDynamic() bool {
+	switch t.kind {
+	case kindBytes, kindString, kindSlice:
+		return true
+	case kindArray:
+		return t.elem.isDynamic()
+	case kindTuple:
+		for _, component := range t.components {
+			if component.isDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize 型別在 head 中佔用的 bytes 數
+func (t abiType) headSize() int {
+	if t.isDynamic() {
+		return wordSize
+	}
+	switch t.kind {
+	case kindArray:
+		return t.length * t.elem.headSize()
+	case kindTuple:
+		size := 0
+		for _, component := range t.components {
+			size += component.headSize()
+		}
+		return size
+	}
+	return wordSize
+}
+
+// decodeArguments 解析 ABI 編碼的參數列表
+func decodeArguments(args []Argument, data []byte) ([]Param, error) {
+	types := make([]abiType, 0, len(args))
+	for _, arg := range args {
+		t, err := parseType(arg.Type, arg.Components)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+
+	values, err := decodeSequence(types, data)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]Param, 0, len(args))
+	for i, arg := range args {
+		params = append(params, Param{Name: arg.Name, Type: arg.canonicalType(), Value: values[i]})
+	}
+	return params, nil
+}
+
+// decodeSequence 依 head / tail 規則解析連續的值，data 從該序列的起點開始
+func decodeSequence(types []abiType, data []byte) ([]any, error) {
+	values := make([]any, 0, len(types))
+	head := 0
+	for _, t := range types {
+		if head+wordSize > len(data) && (t.isDynamic() || t.headSize() > 0) {
+			return nil, fmt.Errorf("%w: data too short", ErrInvalidData)
+		}
+
+		region := data[head:]
+		if t.isDynamic() {
+			offset, err := readLength(data[head:], len(data))
+			if err != nil {
+				return nil, err
+			}
+			region = data[offset:]
+		}
+		value, err := decodeAt(t, region)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		head += t.headSize()
+	}
+	return values, nil
+}
+
+// decodeAt 解析從 data 起點開始的單一值
+func decodeAt(t abiType, data []byte) (any, error) {
+	switch t.kind {
+	case kindSlice:
+		length, err := readLength(data, (len(data)-wordSize)/wordSize)
+		if err != nil {
+			return nil, err
+		}
+		return decodeList(*t.elem, length, data[wordSize:])
+	case kindArray:
+		return decodeList(*t.elem, t.length, data)
+	case kindTuple:
+		values, err := decodeSequence(t.components, data)
+		if err != nil {
+			return nil, err
+		}
+		params := make([]Param, 0, len(values))
+		for i, value := range values {
+			params = append(params, Param{Name: t.names[i], Type: t.canonical[i], Value: value})
+		}
+		return params, nil
+	case kindBytes, kindString:
+		length, err := readLength(data, len(data)-wordSize)
+		if err != nil {
+			return nil, err
+		}
+		content := data[wordSize : wordSize+length]
+		if t.kind == kindString {
+			return string(content), nil
+		}
+		return domain.Bytes(append([]byte{}, content...)), nil
+	}
+
+	if len(data) < wordSize {
+		return nil, fmt.Errorf("%w: data too short", ErrInvalidData)
+	}
+	word := data[:wordSize]
+	switch t.kind {
+	case kindUint:
+		return domain.NewBigInt(new(big.Int).SetBytes(word)), nil
+	case kindInt:
+		n := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return domain.NewBigInt(n), nil
+	case kindAddress:
+		return domain.Bytes(word[12:]).String(), nil
+	case kindBool:
+		return word[wordSize-1] != 0, nil
+	case kindFixedBytes:
+		return domain.Bytes(append([]byte{}, word[:t.size]...)), nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported type", ErrInvalidData)
+}
+
+// decodeList 解析陣列元素，元素的編碼方式與 tuple 相同
+func decodeList(elem abiType, length int, data []byte) ([]any, error) {
+	// 先確認資料足夠放下所有元素的 head 才配置記憶體
+	if size := elem.headSize(); size > 0 && length > len(data)/size {
+		return nil, fmt.Errorf("%w: data too short", ErrInvalidData)
+	}
+	types := make([]abiType, length)
+	for i := range types {
+		types[i] = elem
+	}
+	return decodeSequence(types, data)
+}
+
+// readLength 讀取 32 bytes 的 offset 或長度，超過 limit 時返回錯誤以避免配置過大的記憶體
+func readLength(data []byte, limit int) (int, error) {
+	if len(data) < wordSize {
+		return 0, fmt.Errorf("%w: data too short", ErrInvalidData)
+	}
+	n := new(big.Int).SetBytes(data[:wordSize])
+	if !n.IsInt64() || n.Int64() > int64(limit) {
+		return 0, fmt.Errorf("%w: length or offset out of range", ErrInvalidData)
+	}
+	return int(n.Int64()), nil
+}
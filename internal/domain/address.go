@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrInvalidAddress  = errors.New("invalid address: must be 0x-prefixed 20-byte hex")
+	ErrInvalidChecksum = errors.New("invalid address: EIP-55 checksum mismatch")
+)
+
+// NormalizeAddress 驗證地址為 20 bytes 十六進位並轉為小寫的標準格式
+// 大小寫混合的地址視為 EIP-55 checksum 地址，checksum 不符時返回錯誤
+func NormalizeAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if len(address) != 42 || (address[:2] != "0x" && address[:2] != "0X") {
+		return "", ErrInvalidAddress
+	}
+	body := address[2:]
+	if _, err := hex.DecodeString(body); err != nil {
+		return "", ErrInvalidAddress
+	}
+
+	lower := strings.ToLower(body)
+	upper := strings.ToUpper(body)
+	if body != lower && body != upper && ChecksumAddress("0x"+lower) != "0x"+body {
+		return "", ErrInvalidChecksum
+	}
+
+	return "0x" + lower, nil
+}
+
+// ChecksumAddress 將標準格式的地址轉為 EIP-55 checksum 格式
+func ChecksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	digest := hash.Sum(nil)
+
+	result := []byte(lower)
+	for i, c := range result {
+		// 每個字母對應 hash 的一個 nibble，>= 8 時轉為大寫
+		nibble := digest[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && c <= 'f' && nibble&0xf >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(result)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Lowercase address",
+			address:  "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			expected: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			name:     "Uppercase address",
+			address:  "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+			expected: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			name:     "Valid EIP-55 checksum",
+			address:  "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			expected: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			name:        "Invalid EIP-55 checksum",
+			address:     "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+			expectedErr: ErrInvalidChecksum,
+		},
+		{
+			name:        "Too short",
+			address:     "0x123",
+			expectedErr: ErrInvalidAddress,
+		},
+		{
+			name:        "Missing prefix",
+			address:     "005aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			expectedErr: ErrInvalidAddress,
+		},
+		{
+			name:        "Non-hex characters",
+			address:     "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beazz",
+			expectedErr: ErrInvalidAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizeAddress(tt.address)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestChecksumAddress(t *testing.T) {
+	// EIP-55 規格中的測試向量
+	for _, expected := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		assert.Equal(t, expected, ChecksumAddress(expected))
+	}
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// 常用單位的小數位數
+const (
+	WeiDecimals   = 0
+	GweiDecimals  = 9
+	EtherDecimals = 18
+)
+
+var ErrInvalidBigInt = errors.New("invalid integer")
+
+// BigInt 任意精度整數，用於金額、gas 價格與手續費
+// 零值代表 0，JSON 以十進位字串編碼以避免精度遺失，解碼時也接受 0x 開頭的十六進位字串與數字
+type BigInt struct {
+	v *big.Int
+}
+
+// NewBigInt 複製 n 建立 BigInt，內部表示會被正規化以便直接比較
+func NewBigInt(n *big.Int) BigInt {
+	if n == nil || n.Sign() == 0 {
+		return BigInt{}
+	}
+	v := new(big.Int).SetBytes(n.Bytes())
+	if n.Sign() < 0 {
+		v.Neg(v)
+	}
+	return BigInt{v: v}
+}
+
+// BigIntFromUint64 由 uint64 建立 BigInt
+func BigIntFromUint64(n uint64) BigInt {
+	return NewBigInt(new(big.Int).SetUint64(n))
+}
+
+// ParseHexBigInt 解析 0x 開頭的十六進位數量，例如 "0x10"
+func ParseHexBigInt(s string) (BigInt, error) {
+	if len(s) < 3 || (s[:2] != "0x" && s[:2] != "0X") {
+		return BigInt{}, fmt.Errorf("%w: %q", ErrInvalidBigInt, s)
+	}
+	n, ok := new(big.Int).SetString(s[2:], 16)
+	if !ok || n.Sign() < 0 {
+		return BigInt{}, fmt.Errorf("%w: %q", ErrInvalidBigInt, s)
+	}
+	return NewBigInt(n), nil
+}
+
+// ParseBigInt 解析十進位整數，0x 開頭時視為十六進位
+func ParseBigInt(s string) (BigInt, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return ParseHexBigInt(s)
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return BigInt{}, fmt.Errorf("%w: %q", ErrInvalidBigInt, s)
+	}
+	return NewBigInt(n), nil
+}
+
+// Int 返回數值的副本
+func (b BigInt) Int() *big.Int {
+	if b.v == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(b.v)
+}
+
+func (b BigInt) IsZero() bool {
+	return b.v == nil
+}
+
+func (b BigInt) Sign() int {
+	if b.v == nil {
+		return 0
+	}
+	return b.v.Sign()
+}
+
+func (b BigInt) Cmp(other BigInt) int {
+	return b.Int().Cmp(other.Int())
+}
+
+func (b BigInt) Add(other BigInt) BigInt {
+	return NewBigInt(new(big.Int).Add(b.Int(), other.Int()))
+}
+
+func (b BigInt) Sub(other BigInt) BigInt {
+	return NewBigInt(new(big.Int).Sub(b.Int(), other.Int()))
+}
+
+func (b BigInt) Mul(other BigInt) BigInt {
+	return NewBigInt(new(big.Int).Mul(b.Int(), other.Int()))
+}
+
+// String 返回十進位表示
+func (b BigInt) String() string {
+	return b.Int().String()
+}
+
+// Hex 返回 0x 開頭、不含前導零的十六進位表示
+func (b BigInt) Hex() string {
+	return fmt.Sprintf("%#x", b.Int())
+}
+
+// FormatUnits 將數值除以 10^decimals 並以十進位小數表示，移除多餘的尾數零，例如 1500000000 (9) => "1.5"
+func (b BigInt) FormatUnits(decimals int) string {
+	n := b.Int()
+	negative := n.Sign() < 0
+	digits := n.Abs(n).String()
+	if decimals > 0 {
+		if len(digits) <= decimals {
+			digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+		}
+		integer, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+		digits = integer
+		if fraction != "" {
+			digits += "." + fraction
+		}
+	}
+	if negative {
+		digits = "-" + digits
+	}
+	return digits
+}
+
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*b = BigInt{}
+		return nil
+	}
+
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	n, err := ParseBigInt(s)
+	if err != nil {
+		return err
+	}
+	*b = n
+	return nil
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestParseHexBigInt(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectedErr bool
+	}{
+		{name: "Zero", input: "0x0", expected: "0"},
+		{name: "Small value", input: "0x10", expected: "16"},
+		{name: "Exceeds uint64", input: "0x1000000000000000000000000", expected: "79228162514264337593543950336"},
+		{name: "Missing prefix", input: "10", expectedErr: true},
+		{name: "Empty", input: "0x", expectedErr: true},
+		{name: "Invalid digits", input: "0xzz", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseHexBigInt(tt.input)
+			if tt.expectedErr {
+				assert.ErrorIs(t, err, ErrInvalidBigInt)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result.String())
+			}
+		})
+	}
+}
+
+func TestBigInt_FormatUnits(t *testing.T) {
+	oneEther, _ := new(big.Int).SetString("1000000000000000000", 10)
+
+	tests := []struct {
+		name     string
+		value    BigInt
+		decimals int
+		expected string
+	}{
+		{name: "Zero ether", value: BigInt{}, decimals: EtherDecimals, expected: "0"},
+		{name: "One ether", value: NewBigInt(oneEther), decimals: EtherDecimals, expected: "1"},
+		{name: "One wei in ether", value: BigIntFromUint64(1), decimals: EtherDecimals, expected: "0.000000000000000001"},
+		{name: "Gwei", value: BigIntFromUint64(1500000000), decimals: GweiDecimals, expected: "1.5"},
+		{name: "Wei", value: BigIntFromUint64(1500000000), decimals: WeiDecimals, expected: "1500000000"},
+		{name: "Token with 6 decimals", value: BigIntFromUint64(123456789), decimals: 6, expected: "123.456789"},
+		{name: "Negative", value: BigIntFromUint64(5).Sub(BigIntFromUint64(1500000005)), decimals: GweiDecimals, expected: "-1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.value.FormatUnits(tt.decimals))
+		})
+	}
+}
+
+func TestBigInt_JSONRoundTrip(t *testing.T) {
+	value, _ := ParseHexBigInt("0xffffffffffffffffffffffffffffffff")
+
+	data, err := json.Marshal(value)
+	assert.NoError(t, err)
+	assert.Equal(t, `"340282366920938463463374607431768211455"`, string(data))
+
+	var decoded BigInt
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, value, decoded)
+
+	// 也接受十六進位字串與 JSON 數字
+	assert.NoError(t, json.Unmarshal([]byte(`"0x10"`), &decoded))
+	assert.Equal(t, BigIntFromUint64(16), decoded)
+	assert.NoError(t, json.Unmarshal([]byte(`16`), &decoded))
+	assert.Equal(t, BigIntFromUint64(16), decoded)
+	assert.Error(t, json.Unmarshal([]byte(`"abc"`), &decoded))
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"golang.org/x/crypto/sha3"
+)
+
+// BloomLength logs bloom 的長度，共 2048 bits
+const BloomLength = 256
+
+// bloomBits 依黃皮書的 M3:2048 規則，取 Keccak-256 前三組 2 bytes 的低 11 bits 作為 bloom 中的位元位置
+func bloomBits(data []byte) [3]uint {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	digest := hash.Sum(nil)
+
+	var bits [3]uint
+	for i := range bits {
+		bits[i] = (uint(digest[2*i])<<8 | uint(digest[2*i+1])) & (BloomLength*8 - 1)
+	}
+	return bits
+}
+
+// BloomMayContain 檢查 logs bloom 是否可能包含 data，data 為事件的合約地址或 topic 的原始 bytes
+// 返回 false 代表一定不包含；bloom 長度不正確時無法判斷，返回 true
+func BloomMayContain(bloom []byte, data []byte) bool {
+	if len(bloom) != BloomLength {
+		return true
+	}
+	for _, bit := range bloomBits(data) {
+		// bloom 以 big-endian 表示，第 0 個位元位於最後一個 byte
+		if bloom[BloomLength-1-bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BloomAdd 將 data 加入長度為 BloomLength 的 logs bloom
+func BloomAdd(bloom []byte, data []byte) {
+	for _, bit := range bloomBits(data) {
+		bloom[BloomLength-1-bit/8] |= 1 << (bit % 8)
+	}
+}
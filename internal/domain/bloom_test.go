@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBloomAdd(t *testing.T) {
+	// keccak256("Transfer(address,address,uint256)") 的前 6 bytes 為 ddf252ad1be2，
+	// 對應位元 0x5f2、0x2ad、0x3e2
+	bloom := make([]byte, BloomLength)
+	BloomAdd(bloom, []byte("Transfer(address,address,uint256)"))
+
+	expected := make([]byte, BloomLength)
+	expected[BloomLength-1-0x5f2/8] = 1 << (0x5f2 % 8)
+	expected[BloomLength-1-0x2ad/8] = 1 << (0x2ad % 8)
+	expected[BloomLength-1-0x3e2/8] = 1 << (0x3e2 % 8)
+	assert.Equal(t, expected, bloom)
+}
+
+func TestBloomMayContain(t *testing.T) {
+	bloom := make([]byte, BloomLength)
+	assert.False(t, BloomMayContain(bloom, []byte("test")))
+
+	BloomAdd(bloom, []byte("test"))
+	BloomAdd(bloom, []byte("hallo"))
+	assert.True(t, BloomMayContain(bloom, []byte("test")))
+	assert.True(t, BloomMayContain(bloom, []byte("hallo")))
+	assert.False(t, BloomMayContain(bloom, []byte("other")))
+
+	// 長度不正確時無法判斷
+	assert.True(t, BloomMayContain(nil, []byte("other")))
+	assert.True(t, BloomMayContain(make([]byte, 32), []byte("other")))
+}
@@ -1,3 +1,66 @@
 package domain
 
 const DefaultURL = "https://cloudflare-eth.com"
+
+const (
+	// TransferEventTopic ERC-20 Transfer(address,address,uint256) 事件的 topic0，ERC-721 亦共用此簽名
+	TransferEventTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	// TransferSingleEventTopic ERC-1155 TransferSingle(address,address,address,uint256,uint256) 事件的 topic0
+	TransferSingleEventTopic = "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62"
+	// TransferBatchEventTopic ERC-1155 TransferBatch(address,address,address,uint256[],uint256[]) 事件的 topic0
+	TransferBatchEventTopic = "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb"
+)
+
+const (
+	StandardERC721  = "ERC-721"
+	StandardERC1155 = "ERC-1155"
+)
+
+// 待處理交易的狀態
+const (
+	PendingStatusPending  = "pending"
+	PendingStatusMined    = "mined"
+	PendingStatusReplaced = "replaced"
+	PendingStatusDropped  = "dropped"
+)
+
+// 訂閱過濾條件的交易方向，以訂閱地址的角度區分
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+	DirectionBoth     = "both"
+)
+
+// 訂閱的狀態
+const (
+	SubscriptionStatusActive  = "active"
+	SubscriptionStatusPaused  = "paused"
+	SubscriptionStatusExpired = "expired"
+)
+
+// 重新處理歷史區塊的工作狀態
+const (
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusFailed    = "failed"
+)
+
+// pipeline 階段處理失敗時的方式
+const (
+	PipelineOnErrorContinue = "continue"
+	PipelineOnErrorDrop     = "drop"
+)
+
+// 流經 pipeline 的紀錄種類
+const (
+	PipelineKindTransaction         = "transaction"
+	PipelineKindTokenTransfer       = "token_transfer"
+	PipelineKindNFTTransfer         = "nft_transfer"
+	PipelineKindInternalTransaction = "internal_transaction"
+)
+
+// 代幣資訊的來源
+const (
+	TokenMetadataSourceChain    = "chain"
+	TokenMetadataSourceOverride = "override"
+)
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ENSRegistryAddress ENS registry 合約地址，主網與各測試網相同
+const ENSRegistryAddress = "0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e"
+
+var (
+	ErrInvalidENSName  = errors.New("invalid ens name")
+	ErrENSNameNotFound = errors.New("ens name does not resolve to an address")
+)
+
+// IsENSName 判斷輸入是否為 ENS 名稱而非 0x 地址，例如 vitalik.eth
+func IsENSName(name string) bool {
+	name = strings.TrimSpace(name)
+	return strings.Contains(name, ".") && !strings.HasPrefix(strings.ToLower(name), "0x")
+}
+
+// NormalizeENSName 將名稱轉為小寫並檢查各段落不為空
+// 僅處理 ASCII 名稱的大小寫，完整的 UTS-46 正規化不在此範圍
+func NormalizeENSName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !IsENSName(name) {
+		return "", ErrInvalidENSName
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return "", ErrInvalidENSName
+		}
+	}
+	return name, nil
+}
+
+// NameHash 依 EIP-137 計算名稱的 namehash，由最後一段往前遞迴計算
+func NameHash(name string) []byte {
+	node := make([]byte, 32)
+	if name == "" {
+		return node
+	}
+
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := keccak256([]byte(labels[i]))
+		node = keccak256(append(node, labelHash...))
+	}
+	return node
+}
+
+// NameHashHex 以 0x 開頭的十六進位表示 namehash
+func NameHashHex(name string) string {
+	return "0x" + hex.EncodeToString(NameHash(name))
+}
+
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNameHash(t *testing.T) {
+	// EIP-137 的測試向量
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "", expected: "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{name: "eth", expected: "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"},
+		{name: "foo.eth", expected: "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NameHashHex(tt.name))
+		})
+	}
+}
+
+func TestNormalizeENSName(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectedErr error
+	}{
+		{name: "Lowercase", input: " Vitalik.ETH ", expected: "vitalik.eth"},
+		{name: "Subdomain", input: "pay.vitalik.eth", expected: "pay.vitalik.eth"},
+		{name: "Empty label", input: "vitalik..eth", expectedErr: ErrInvalidENSName},
+		{name: "Address is not a name", input: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", expectedErr: ErrInvalidENSName},
+		{name: "No dot", input: "vitalik", expectedErr: ErrInvalidENSName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizeENSName(tt.input)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
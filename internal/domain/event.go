@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidTopic              = errors.New("invalid topic: must be 0x-prefixed 32-byte hex or a 20-byte address")
+	ErrTooManyTopics             = errors.New("invalid topics: at most 4 topic positions")
+	ErrEventTopicMismatch        = errors.New("invalid topics: first topic must be the event signature")
+	ErrEventSubscriptionNotFound = errors.New("event subscription not found")
+)
+
+// MaxEventTopics 事件最多的 topic 數，非匿名事件的第一個 topic 為事件簽名
+const MaxEventTopics = 4
+
+// NormalizeTopic 驗證 topic 為 32 bytes 十六進位並轉為小寫，20 bytes 的地址會補零為 indexed address 的 topic
+func NormalizeTopic(topic string) (string, error) {
+	topic = strings.TrimSpace(topic)
+	if len(topic) < 2 || (topic[:2] != "0x" && topic[:2] != "0X") {
+		return "", ErrInvalidTopic
+	}
+	body := strings.ToLower(topic[2:])
+	if _, err := hex.DecodeString(body); err != nil {
+		return "", ErrInvalidTopic
+	}
+	switch len(body) {
+	case 64:
+		return "0x" + body, nil
+	case 40:
+		return "0x" + strings.Repeat("0", 24) + body, nil
+	}
+	return "", ErrInvalidTopic
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeTopic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{input: "0xDDF252AD1BE2C89B69C2B068FC378DAA952BA7F163C4A11628F55A4DF523B3EF", expected: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{input: " 0x00000000000000000000000000000000000000000000000000000000000003e8 ", expected: "0x00000000000000000000000000000000000000000000000000000000000003e8"},
+		{input: "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045", expected: "0x000000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045"},
+		{input: "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", err: ErrInvalidTopic},
+		{input: "0x1234", err: ErrInvalidTopic},
+		{input: "0xzzf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", err: ErrInvalidTopic},
+		{input: "", err: ErrInvalidTopic},
+	}
+
+	for _, tt := range tests {
+		topic, err := NormalizeTopic(tt.input)
+		if tt.err != nil {
+			assert.ErrorIs(t, err, tt.err, tt.input)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, topic)
+	}
+}
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// 以下型別用於 JSON RPC 返回的結構，解碼時即嚴格檢查格式，避免錯誤的資料以字串形式往下傳遞
+// QUANTITY 與 DATA 的編碼規則見 https://ethereum.org/en/developers/docs/apis/json-rpc/#hex-encoding
+
+var (
+	ErrMissingPrefix = errors.New("hex string without 0x prefix")
+	ErrEmptyNumber   = errors.New("hex string \"0x\"")
+	ErrLeadingZero   = errors.New("hex number with leading zero digits")
+	ErrSyntax        = errors.New("invalid hex string")
+	ErrOddLength     = errors.New("hex string of odd length")
+	ErrUint64Range   = errors.New("hex number > 64 bits")
+	ErrBig256Range   = errors.New("hex number > 256 bits")
+	ErrNonString     = errors.New("hex value must be a JSON string")
+	ErrInvalidLength = errors.New("hex data has invalid length")
+)
+
+// unquoteHex 取出 JSON 字串並檢查 0x 前綴，null 時 isNull 為 true
+func unquoteHex(data []byte) (raw string, isNull bool, err error) {
+	if bytes.Equal(data, []byte("null")) {
+		return "", true, nil
+	}
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return "", false, ErrNonString
+	}
+	raw = string(data[1 : len(data)-1])
+	if len(raw) < 2 || (raw[:2] != "0x" && raw[:2] != "0X") {
+		return "", false, ErrMissingPrefix
+	}
+	return raw[2:], false, nil
+}
+
+// checkQuantity 檢查 QUANTITY 編碼：不可為空且不可有前導零
+func checkQuantity(digits string) error {
+	if digits == "" {
+		return ErrEmptyNumber
+	}
+	if len(digits) > 1 && digits[0] == '0' {
+		return ErrLeadingZero
+	}
+	return nil
+}
+
+// HexUint64 以十六進位 QUANTITY 編碼的 uint64，例如區塊號、nonce、gas
+type HexUint64 uint64
+
+func (h HexUint64) String() string {
+	return "0x" + strconv.FormatUint(uint64(h), 16)
+}
+
+func (h HexUint64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.String())
+}
+
+func (h *HexUint64) UnmarshalJSON(data []byte) error {
+	digits, isNull, err := unquoteHex(data)
+	if err != nil || isNull {
+		return err
+	}
+	if err := checkQuantity(digits); err != nil {
+		return err
+	}
+	if len(digits) > 16 {
+		return ErrUint64Range
+	}
+	n, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil {
+		return ErrSyntax
+	}
+	*h = HexUint64(n)
+	return nil
+}
+
+// HexBig 以十六進位 QUANTITY 編碼、最大 256 bits 的整數，例如金額與 gas 價格
+type HexBig struct {
+	BigInt
+}
+
+func (h HexBig) String() string {
+	return h.BigInt.Hex()
+}
+
+func (h HexBig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.BigInt.Hex())
+}
+
+func (h *HexBig) UnmarshalJSON(data []byte) error {
+	digits, isNull, err := unquoteHex(data)
+	if err != nil || isNull {
+		return err
+	}
+	if err := checkQuantity(digits); err != nil {
+		return err
+	}
+	if len(digits) > 64 {
+		return ErrBig256Range
+	}
+	n, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return ErrSyntax
+	}
+	h.BigInt = NewBigInt(n)
+	return nil
+}
+
+// Bytes 以十六進位 DATA 編碼的任意長度資料，例如交易的 input 與事件的 data
+type Bytes []byte
+
+func (b Bytes) String() string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	digits, isNull, err := unquoteHex(data)
+	if err != nil || isNull {
+		return err
+	}
+	decoded, err := decodeData(digits)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+func decodeData(digits string) ([]byte, error) {
+	if len(digits)%2 != 0 {
+		return nil, ErrOddLength
+	}
+	decoded, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, ErrSyntax
+	}
+	return decoded, nil
+}
+
+// Hash 32 bytes 的哈希值，以小寫十六進位字串保存以便直接比較
+type Hash string
+
+func (h Hash) String() string {
+	return string(h)
+}
+
+func (h *Hash) UnmarshalJSON(data []byte) error {
+	digits, isNull, err := unquoteHex(data)
+	if err != nil || isNull {
+		return err
+	}
+	decoded, err := decodeData(digits)
+	if err != nil {
+		return err
+	}
+	if len(decoded) != 32 {
+		return fmt.Errorf("%w: hash must be 32 bytes, got %d", ErrInvalidLength, len(decoded))
+	}
+	*h = Hash("0x" + strings.ToLower(digits))
+	return nil
+}
+
+// Address 20 bytes 的地址，解碼時驗證 EIP-55 checksum 並以小寫標準格式保存
+type Address string
+
+func (a Address) String() string {
+	return string(a)
+}
+
+func (a *Address) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ErrNonString
+	}
+	address, err := NormalizeAddress(raw)
+	if err != nil {
+		return err
+	}
+	*a = Address(address)
+	return nil
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHexUint64_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    HexUint64
+		expectedErr error
+	}{
+		{name: "Zero", input: `"0x0"`, expected: 0},
+		{name: "Block number", input: `"0x10d4f"`, expected: 68943},
+		{name: "Max uint64", input: `"0xffffffffffffffff"`, expected: 1<<64 - 1},
+		{name: "Leading zero", input: `"0x01"`, expectedErr: ErrLeadingZero},
+		{name: "Empty number", input: `"0x"`, expectedErr: ErrEmptyNumber},
+		{name: "Missing prefix", input: `"10"`, expectedErr: ErrMissingPrefix},
+		{name: "Overflow", input: `"0x10000000000000000"`, expectedErr: ErrUint64Range},
+		{name: "Invalid digits", input: `"0xzz"`, expectedErr: ErrSyntax},
+		{name: "JSON number", input: `16`, expectedErr: ErrNonString},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result HexUint64
+			err := json.Unmarshal([]byte(tt.input), &result)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestHexBig_UnmarshalJSON(t *testing.T) {
+	var value HexBig
+	assert.NoError(t, json.Unmarshal([]byte(`"0xde0b6b3a7640000"`), &value))
+	assert.Equal(t, "1000000000000000000", value.BigInt.String())
+	assert.Equal(t, "0xde0b6b3a7640000", value.String())
+
+	max := `"0x` + "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" + `"`
+	assert.NoError(t, json.Unmarshal([]byte(max), &value))
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"0x1`+max[3:]), &value), ErrBig256Range)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"0x00"`), &value), ErrLeadingZero)
+
+	data, err := json.Marshal(HexBig{BigIntFromUint64(16)})
+	assert.NoError(t, err)
+	assert.Equal(t, `"0x10"`, string(data))
+}
+
+func TestBytes_UnmarshalJSON(t *testing.T) {
+	var data Bytes
+	assert.NoError(t, json.Unmarshal([]byte(`"0x"`), &data))
+	assert.Empty(t, data)
+	assert.NoError(t, json.Unmarshal([]byte(`"0x00ff"`), &data))
+	assert.Equal(t, Bytes{0x00, 0xff}, data)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"0x0"`), &data), ErrOddLength)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"0xzz"`), &data), ErrSyntax)
+}
+
+func TestHash_UnmarshalJSON(t *testing.T) {
+	var hash Hash
+	assert.NoError(t, json.Unmarshal([]byte(`"0xDDF252AD1BE2C89B69C2B068FC378DAA952BA7F163C4A11628F55A4DF523B3EF"`), &hash))
+	assert.Equal(t, Hash(TransferEventTopic), hash)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"0xabc123"`), &hash), ErrInvalidLength)
+}
+
+func TestAddress_UnmarshalJSON(t *testing.T) {
+	var address Address
+	assert.NoError(t, json.Unmarshal([]byte(`"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"`), &address))
+	assert.Equal(t, Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"), address)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"0x123"`), &address), ErrInvalidAddress)
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrStorageNotShared = errors.New("lease leader election requires a storage shared by all replicas")
+	ErrNotLeader        = errors.New("this replica is not the leader, retry on the leader")
+)
@@ -0,0 +1,5 @@
+package domain
+
+import "errors"
+
+var ErrInvalidPipelineStage = errors.New("invalid pipeline stage: must have a name, a processor and an on-error policy of continue or drop")
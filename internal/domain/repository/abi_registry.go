@@ -0,0 +1,11 @@
+package repository
+
+import "parse_server/internal/domain/abi"
+
+// ABIRegistry 依函式 selector 與事件 topic 查找已登錄的 ABI，將合約呼叫與事件解碼為易讀的格式
+type ABIRegistry interface {
+	// DecodeInput 解碼交易 input，找不到對應的 ABI 或解碼失敗時返回 false
+	DecodeInput(input []byte) (*abi.Call, bool)
+	// DecodeLog 解碼事件紀錄，找不到對應的 ABI 或解碼失敗時返回 false
+	DecodeLog(log Log) (*abi.Call, bool)
+}
@@ -1,65 +1,312 @@
 package repository
 
+import (
+	"fmt"
+	"parse_server/internal/domain"
+)
+
+// RPCError JSON-RPC 返回的錯誤物件
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
 // EthereumRPCResponse JSON-RPC 與 Ethereum 節點通訊
 type EthereumRPCResponse struct {
-	ID      int    `json:"id"`
-	JsonRPC string `json:"jsonrpc"`
-	Result  string `json:"result"`
+	ID      int       `json:"id"`
+	JsonRPC string    `json:"jsonrpc"`
+	Result  string    `json:"result"`
+	Error   *RPCError `json:"error"`
 }
 
 // Block 定義 JSON RPC 區塊返回的結構
 
 type BlockResult struct {
-	JsonRPC string `json:"jsonrpc"`
-	ID      int    `json:"id"`
-	Result  Block  `json:"result"`
+	JsonRPC string    `json:"jsonrpc"`
+	ID      int       `json:"id"`
+	Result  Block     `json:"result"`
+	Error   *RPCError `json:"error"`
 }
 
 type Block struct {
-	Difficulty       string            `json:"difficulty"`
-	ExtraData        string            `json:"extraData"`
-	GasLimit         string            `json:"gasLimit"`
-	GasUsed          string            `json:"gasUsed"`
-	Hash             string            `json:"hash"`
-	LogsBloom        string            `json:"logsBloom"`
-	Miner            string            `json:"miner"`
-	MixHash          string            `json:"mixHash"`
-	Nonce            string            `json:"nonce"`
-	Number           string            `json:"number"`
-	ParentHash       string            `json:"parentHash"`
-	ReceiptsRoot     string            `json:"receiptsRoot"`
-	Sha3Uncles       string            `json:"sha3Uncles"`
-	Size             string            `json:"size"`
-	StateRoot        string            `json:"stateRoot"`
-	Timestamp        string            `json:"timestamp"`
-	TotalDifficulty  string            `json:"totalDifficulty"`
+	// BaseFeePerGas 倫敦升級（EIP-1559）後的基本費用，之前的區塊沒有此欄位
+	BaseFeePerGas    *domain.HexBig    `json:"baseFeePerGas"`
+	Difficulty       domain.HexBig     `json:"difficulty"`
+	ExtraData        domain.Bytes      `json:"extraData"`
+	GasLimit         domain.HexUint64  `json:"gasLimit"`
+	GasUsed          domain.HexUint64  `json:"gasUsed"`
+	Hash             domain.Hash       `json:"hash"`
+	LogsBloom        domain.Bytes      `json:"logsBloom"`
+	Miner            domain.Address    `json:"miner"`
+	MixHash          domain.Hash       `json:"mixHash"`
+	Nonce            domain.Bytes      `json:"nonce"`
+	Number           domain.HexUint64  `json:"number"`
+	ParentHash       domain.Hash       `json:"parentHash"`
+	ReceiptsRoot     domain.Hash       `json:"receiptsRoot"`
+	Sha3Uncles       domain.Hash       `json:"sha3Uncles"`
+	Size             domain.HexUint64  `json:"size"`
+	StateRoot        domain.Hash       `json:"stateRoot"`
+	Timestamp        domain.HexUint64  `json:"timestamp"`
+	TotalDifficulty  *domain.HexBig    `json:"totalDifficulty"`
 	Transactions     []TransactionItem `json:"transactions"`
-	TransactionsRoot string            `json:"transactionsRoot"`
-	Uncles           []string          `json:"uncles"`
+	TransactionsRoot domain.Hash       `json:"transactionsRoot"`
+	Uncles           []domain.Hash     `json:"uncles"`
+	// Withdrawals 上海升級（EIP-4895）後的驗證者提款，之前的區塊沒有此欄位
+	Withdrawals     []WithdrawalItem `json:"withdrawals"`
+	WithdrawalsRoot *domain.Hash     `json:"withdrawalsRoot"`
+}
+
+// BlockHeaderResult 定義 eth_getBlockByNumber 不含完整交易時返回的結構
+type BlockHeaderResult struct {
+	JsonRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Result  BlockHeader `json:"result"`
+	Error   *RPCError   `json:"error"`
+}
+
+// BlockHeader 定義不含完整交易的區塊，Transactions 只有交易哈希值
+type BlockHeader struct {
+	Hash         domain.Hash      `json:"hash"`
+	Number       domain.HexUint64 `json:"number"`
+	ParentHash   domain.Hash      `json:"parentHash"`
+	LogsBloom    domain.Bytes     `json:"logsBloom"`
+	Miner        domain.Address   `json:"miner"`
+	Timestamp    domain.HexUint64 `json:"timestamp"`
+	Transactions []domain.Hash    `json:"transactions"`
+	Withdrawals  []WithdrawalItem `json:"withdrawals"`
+}
+
+// WithdrawalItem 定義區塊中的驗證者提款，Amount 單位為 gwei
+type WithdrawalItem struct {
+	Index          domain.HexUint64 `json:"index"`
+	ValidatorIndex domain.HexUint64 `json:"validatorIndex"`
+	Address        domain.Address   `json:"address"`
+	Amount         domain.HexUint64 `json:"amount"`
+}
+
+// BlockNumberResult 定義 JSON RPC eth_blockNumber 返回的結構
+type BlockNumberResult struct {
+	JsonRPC string           `json:"jsonrpc"`
+	ID      int              `json:"id"`
+	Result  domain.HexUint64 `json:"result"`
+	Error   *RPCError        `json:"error"`
+}
+
+// TransactionResult 定義 JSON RPC eth_getTransactionByHash 返回的結構，交易不存在時 Result 為null
+type TransactionResult struct {
+	JsonRPC string           `json:"jsonrpc"`
+	ID      int              `json:"id"`
+	Result  *TransactionItem `json:"result"`
+	Error   *RPCError        `json:"error"`
+}
+
+// FilterChangesResult 定義 JSON RPC eth_getFilterChanges 返回的結構
+type FilterChangesResult struct {
+	JsonRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Result  []domain.Hash `json:"result"`
+	Error   *RPCError     `json:"error"`
+}
+
+// ReceiptResult 定義 JSON RPC eth_getTransactionReceipt 返回的結構
+type ReceiptResult struct {
+	JsonRPC string    `json:"jsonrpc"`
+	ID      int       `json:"id"`
+	Result  *Receipt  `json:"result"`
+	Error   *RPCError `json:"error"`
+}
+
+// BlockReceiptsResult 定義 JSON RPC eth_getBlockReceipts 返回的結構
+type BlockReceiptsResult struct {
+	JsonRPC string    `json:"jsonrpc"`
+	ID      int       `json:"id"`
+	Result  []Receipt `json:"result"`
+	Error   *RPCError `json:"error"`
+}
+
+type Receipt struct {
+	TransactionHash   domain.Hash       `json:"transactionHash"`   // 交易哈希值
+	BlockHash         domain.Hash       `json:"blockHash"`         // 區塊的哈希值
+	BlockNumber       domain.HexUint64  `json:"blockNumber"`       // 區塊編號
+	From              domain.Address    `json:"from"`              // 發送者的地址
+	To                *domain.Address   `json:"to"`                // 接收者的地址，合約創建交易時為null
+	ContractAddress   *domain.Address   `json:"contractAddress"`   // 合約創建交易產生的合約地址
+	GasUsed           domain.HexUint64  `json:"gasUsed"`           // 此交易使用的gas
+	CumulativeGasUsed domain.HexUint64  `json:"cumulativeGasUsed"` // 區塊內累計使用的gas
+	EffectiveGasPrice *domain.HexBig    `json:"effectiveGasPrice"` // 實際支付的每單位 gas 價格，舊節點可能沒有此欄位
+	Status            *domain.HexUint64 `json:"status"`            // 0x1 成功，0x0 失敗，拜占庭分叉前為null
+	Type              domain.HexUint64  `json:"type"`              // 交易的類型
+	Logs              []Log             `json:"logs"`              // 交易產生的事件
+	BlobGasUsed       *domain.HexUint64 `json:"blobGasUsed"`       // EIP-4844 blob 交易使用的 blob gas
+	BlobGasPrice      *domain.HexBig    `json:"blobGasPrice"`      // EIP-4844 blob 交易實際支付的每單位 blob gas 價格
+	L1Fee             *domain.HexBig    `json:"l1Fee"`             // OP Stack 鏈上 L2 交易額外支付的 L1 資料費用
+	DepositNonce      *domain.HexUint64 `json:"depositNonce"`      // OP Stack deposit 交易的 nonce
 }
 
 type TransactionItem struct {
-	BlockHash            *string `json:"blockHash"`            // 區塊的哈希值，當交易在等待中時為null
-	BlockNumber          *string `json:"blockNumber"`          // 區塊編號，當交易在等待中時為null
-	From                 string  `json:"from"`                 // 發送者的地址
-	Gas                  string  `json:"gas"`                  // 發送者提供的gas（十六進位編碼）
-	GasPrice             string  `json:"gasPrice"`             // 發送者提供的gas價格（以wei為單位，十六進位編碼）
-	MaxFeePerGas         *string `json:"maxFeePerGas"`         // 設定的每單位 gas 的最大費用（可選，可能為nil）
-	MaxPriorityFeePerGas *string `json:"maxPriorityFeePerGas"` // 設定的優先級 gas 費用的最大值（可選，可能為nil）
-	Hash                 string  `json:"hash"`                 // 交易的哈希值
-	Input                string  `json:"input"`                // 與交易一起發送的數據
-	Nonce                string  `json:"nonce"`                // 發送者在此交易之前發送的交易數（十六進位編碼）
-	To                   *string `json:"to"`                   // 接收者的地址，當是合約創建交易時為null
-	TransactionIndex     *string `json:"transactionIndex"`     // 交易索引位置，當是等待中的交易時為null
-	Value                string  `json:"value"`                // 轉移的金額（以wei為單位，十六進位編碼）
-	Type                 string  `json:"type"`                 // 交易的類型
-	AccessList           []any   `json:"accessList"`           // 計劃訪問的地址和存儲鍵列表
-	ChainId              *string `json:"chainId"`              // 交易的鏈ID（若有）
-	V                    string  `json:"v"`                    // 簽名中的標準化V字段
-	R                    string  `json:"r"`                    // 簽名中的R字段
-	S                    string  `json:"s"`                    // 簽名中的S字段
+	BlockHash            *domain.Hash        `json:"blockHash"`            // 區塊的哈希值，當交易在等待中時為null
+	BlockNumber          *domain.HexUint64   `json:"blockNumber"`          // 區塊編號，當交易在等待中時為null
+	From                 domain.Address      `json:"from"`                 // 發送者的地址
+	Gas                  domain.HexUint64    `json:"gas"`                  // 發送者提供的gas（十六進位編碼）
+	GasPrice             domain.HexBig       `json:"gasPrice"`             // 發送者提供的gas價格（以wei為單位，十六進位編碼）
+	MaxFeePerGas         *domain.HexBig      `json:"maxFeePerGas"`         // 設定的每單位 gas 的最大費用（可選，可能為nil）
+	MaxPriorityFeePerGas *domain.HexBig      `json:"maxPriorityFeePerGas"` // 設定的優先級 gas 費用的最大值（可選，可能為nil）
+	Hash                 domain.Hash         `json:"hash"`                 // 交易的哈希值
+	Input                domain.Bytes        `json:"input"`                // 與交易一起發送的數據
+	Nonce                domain.HexUint64    `json:"nonce"`                // 發送者在此交易之前發送的交易數（十六進位編碼）
+	To                   *domain.Address     `json:"to"`                   // 接收者的地址，當是合約創建交易時為null
+	TransactionIndex     *domain.HexUint64   `json:"transactionIndex"`     // 交易索引位置，當是等待中的交易時為null
+	Value                domain.HexBig       `json:"value"`                // 轉移的金額（以wei為單位，十六進位編碼）
+	Type                 domain.HexUint64    `json:"type"`                 // 交易的類型
+	AccessList           []AccessTuple       `json:"accessList"`           // 計劃訪問的地址和存儲鍵列表
+	ChainId              *domain.HexBig      `json:"chainId"`              // 交易的鏈ID（若有）
+	V                    domain.HexBig       `json:"v"`                    // 簽名中的標準化V字段
+	R                    domain.HexBig       `json:"r"`                    // 簽名中的R字段
+	S                    domain.HexBig       `json:"s"`                    // 簽名中的S字段
+	YParity              *domain.HexUint64   `json:"yParity"`              // typed 交易簽名的 y parity
+	MaxFeePerBlobGas     *domain.HexBig      `json:"maxFeePerBlobGas"`     // EIP-4844 每單位 blob gas 的最大費用
+	BlobVersionedHashes  []domain.Hash       `json:"blobVersionedHashes"`  // EIP-4844 blob 的 versioned hash
+	AuthorizationList    []AuthorizationItem `json:"authorizationList"`    // EIP-7702 授權 EOA 委派合約程式碼的清單
+	SourceHash           *domain.Hash        `json:"sourceHash"`           // OP Stack deposit 交易的來源識別
+	Mint                 *domain.HexBig      `json:"mint"`                 // OP Stack deposit 交易在 L2 鑄造的 ETH
+	IsSystemTx           *bool               `json:"isSystemTx"`           // OP Stack deposit 是否為系統交易
+}
+
+// AccessTuple EIP-2930 access list 的項目
+type AccessTuple struct {
+	Address     domain.Address `json:"address"`
+	StorageKeys []domain.Hash  `json:"storageKeys"`
+}
+
+// AuthorizationItem 定義 EIP-7702 的授權項目，簽署者將程式碼委派給 Address
+type AuthorizationItem struct {
+	ChainId domain.HexBig    `json:"chainId"`
+	Address domain.Address   `json:"address"`
+	Nonce   domain.HexUint64 `json:"nonce"`
+	YParity domain.HexUint64 `json:"yParity"`
+	R       domain.HexBig    `json:"r"`
+	S       domain.HexBig    `json:"s"`
+}
+
+// CallMsg 定義 eth_call 的呼叫內容
+type CallMsg struct {
+	To   string       `json:"to"`
+	Data domain.Bytes `json:"data"`
+}
+
+// CallResult 定義 JSON RPC eth_call 返回的結構
+type CallResult struct {
+	JsonRPC string       `json:"jsonrpc"`
+	ID      int          `json:"id"`
+	Result  domain.Bytes `json:"result"`
+	Error   *RPCError    `json:"error"`
+}
+
+// BalanceResult 定義 JSON RPC eth_getBalance 返回的結構
+type BalanceResult struct {
+	JsonRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Result  domain.HexBig `json:"result"`
+	Error   *RPCError     `json:"error"`
+}
+
+// LogFilter 定義 eth_getLogs 的查詢條件
+type LogFilter struct {
+	FromBlock string   `json:"fromBlock,omitempty"`
+	ToBlock   string   `json:"toBlock,omitempty"`
+	Address   []string `json:"address,omitempty"`
+	Topics    []any    `json:"topics,omitempty"`
+}
+
+// LogResult 定義 JSON RPC eth_getLogs 返回的結構
+type LogResult struct {
+	JsonRPC string    `json:"jsonrpc"`
+	ID      int       `json:"id"`
+	Result  []Log     `json:"result"`
+	Error   *RPCError `json:"error"`
+}
+
+type Log struct {
+	Address          domain.Address   `json:"address"`          // 發出事件的合約地址
+	Topics           []domain.Hash    `json:"topics"`           // 事件的 topics，第一個為事件簽名
+	Data             domain.Bytes     `json:"data"`             // 未被 indexed 的事件參數
+	BlockNumber      domain.HexUint64 `json:"blockNumber"`      // 區塊編號
+	BlockHash        domain.Hash      `json:"blockHash"`        // 區塊的哈希值
+	TransactionHash  domain.Hash      `json:"transactionHash"`  // 產生此事件的交易哈希值
+	TransactionIndex domain.HexUint64 `json:"transactionIndex"` // 交易索引位置
+	LogIndex         domain.HexUint64 `json:"logIndex"`         // 事件在區塊中的索引位置
+	Removed          bool             `json:"removed"`          // 因鏈重組而被移除時為 true
+}
+
+// TraceBlockResult 定義 debug_traceBlockByNumber（callTracer）返回的結構
+type TraceBlockResult struct {
+	JsonRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Result  []TraceItem `json:"result"`
+	Error   *RPCError   `json:"error"`
+}
+
+type TraceItem struct {
+	TxHash domain.Hash `json:"txHash"` // 交易哈希值
+	Result CallFrame   `json:"result"` // 交易最外層的呼叫
+}
+
+// CallFrame callTracer 的呼叫節點，Calls 為其子呼叫
+type CallFrame struct {
+	Type    string           `json:"type"`    // CALL、DELEGATECALL、CREATE 等
+	From    domain.Address   `json:"from"`    // 呼叫者地址
+	To      domain.Address   `json:"to"`      // 被呼叫地址
+	Value   domain.HexBig    `json:"value"`   // 轉移的金額（以wei為單位，十六進位編碼），STATICCALL 時不存在
+	Gas     domain.HexUint64 `json:"gas"`     // 提供的gas
+	GasUsed domain.HexUint64 `json:"gasUsed"` // 使用的gas
+	Input   domain.Bytes     `json:"input"`   // 呼叫資料
+	Output  domain.Bytes     `json:"output"`  // 返回資料
+	Error   string           `json:"error"`   // 呼叫失敗時的錯誤訊息
+	Calls   []CallFrame      `json:"calls"`   // 子呼叫
+}
+
+// ParityTraceResult 定義 trace_block 返回的結構
+type ParityTraceResult struct {
+	JsonRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Result  []ParityTrace `json:"result"`
+	Error   *RPCError     `json:"error"`
+}
+
+type ParityTrace struct {
+	Action          ParityTraceAction `json:"action"`          // 呼叫內容
+	BlockHash       domain.Hash       `json:"blockHash"`       // 區塊的哈希值
+	BlockNumber     uint64            `json:"blockNumber"`     // 區塊編號
+	TraceAddress    []int             `json:"traceAddress"`    // 呼叫在樹中的路徑，長度即為呼叫深度
+	TransactionHash *domain.Hash      `json:"transactionHash"` // 交易哈希值，區塊獎勵時為null
+	Type            string            `json:"type"`            // call、create、suicide、reward
+	Result          ParityTraceOutput `json:"result"`          // 呼叫結果，失敗時為null
+	Error           string            `json:"error"`           // 呼叫失敗時的錯誤訊息
+}
+
+type ParityTraceOutput struct {
+	Address domain.Address `json:"address"` // create 時建立的合約地址
+}
+
+type ParityTraceAction struct {
+	CallType      string         `json:"callType"`      // call、delegatecall、staticcall 等
+	From          domain.Address `json:"from"`          // 呼叫者地址
+	To            domain.Address `json:"to"`            // 被呼叫地址
+	Value         domain.HexBig  `json:"value"`         // 轉移的金額
+	Address       domain.Address `json:"address"`       // suicide 時被銷毀的合約地址
+	RefundAddress domain.Address `json:"refundAddress"` // suicide 時接收餘額的地址
+	Balance       domain.HexBig  `json:"balance"`       // suicide 時轉移的餘額
 }
 
 type ETHClient interface {
 	CallEthereum(method string, params []any) ([]byte, error)
 }
+
+// EndpointNamer ETHClient 可選實作的介面，返回目前使用的節點，用於依節點記錄其限制，未實作時視為同一個節點
+type EndpointNamer interface {
+	Endpoint() string
+}
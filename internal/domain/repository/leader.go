@@ -0,0 +1,9 @@
+package repository
+
+// LeaderElector 在多個實例之間選出唯一的 leader，只有 leader 輪詢區塊並發送通知
+type LeaderElector interface {
+	// TryAcquire 嘗試取得或續約領導權，返回目前是否為 leader
+	TryAcquire() (bool, error)
+	// Release 主動釋放領導權，讓其他實例立即接手
+	Release() error
+}
@@ -1,17 +1,295 @@
 package repository
 
+import (
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"time"
+)
+
 // Storage interface
 type Storage interface {
+	// 區塊活動的 Save 方法以紀錄的唯一鍵 upsert，重新處理同一個區塊不會產生重複的紀錄
+	// SaveTransaction 以交易哈希值為鍵
 	SaveTransaction(address string, tx Transaction)
 	GetTransactions(address string) []Transaction
-	GetSubscribedAddresses() []string
-	SubscribeAddress(address string)
+	// SaveTokenTransfer 以交易哈希值與 LogIndex 為鍵
+	SaveTokenTransfer(address string, transfer TokenTransfer)
+	GetTokenTransfers(address string) []TokenTransfer
+	// SaveNFTTransfer 以交易哈希值、LogIndex 與 BatchIndex 為鍵
+	SaveNFTTransfer(address string, transfer NFTTransfer)
+	GetNFTTransfers(address string) []NFTTransfer
+	// SaveInternalTransaction 以外層交易哈希值與 Index 為鍵
+	SaveInternalTransaction(address string, tx InternalTransaction)
+	GetInternalTransactions(address string) []InternalTransaction
+	// SaveWithdrawal 以提款的 Index 為鍵
+	SaveWithdrawal(address string, withdrawal Withdrawal)
+	GetWithdrawals(address string) []Withdrawal
+	// SaveBlockReward 以區塊號為鍵
+	SaveBlockReward(address string, reward BlockReward)
+	GetBlockRewards(address string) []BlockReward
+	// SaveBalanceSnapshot 依序附加快照，不會覆蓋
+	SaveBalanceSnapshot(address string, snapshot BalanceSnapshot)
+	GetBalanceSnapshots(address string) []BalanceSnapshot
+	// GetLatestBalanceSnapshot 取得地址最新的餘額快照，沒有快照時返回 false
+	GetLatestBalanceSnapshot(address string) (BalanceSnapshot, bool)
+	// SavePendingTransaction 以交易哈希值為鍵
+	SavePendingTransaction(address string, tx PendingTransaction)
+	GetPendingTransactions(address string) []PendingTransaction
+	// SubscribeAddress 新增或更新訂閱，以 Address 為鍵
+	SubscribeAddress(subscription Subscription)
+	// UnsubscribeAddress 移除訂閱，已記錄的交易會保留，地址未訂閱時返回 false
+	UnsubscribeAddress(address string) bool
+	// SetSubscriptionPaused 暫停或恢復訂閱，地址未訂閱時返回 false
+	SetSubscriptionPaused(address string, paused bool) bool
+	// GetSubscription 取得地址的訂閱，地址未訂閱時返回 false
+	GetSubscription(address string) (Subscription, bool)
+	// GetSubscriptions 取得所有訂閱，包含已暫停與已過期者
+	GetSubscriptions() []Subscription
+	// MoveSubscription 將 from 的訂閱移至 moved.Address，原地址的訂閱必須仍與 from 相同，
+	// 新地址的訂閱必須仍與 target 相同（target 為 nil 表示新地址沒有訂閱），否則不做任何修改並返回 false
+	// kept 不為 nil 時以 kept 取代原地址的訂閱，否則移除原地址的訂閱
+	MoveSubscription(from Subscription, kept, target *Subscription, moved Subscription) bool
+	// AcquireLease 取得或續約名為 name 的租約，租約不存在、已過期或已由 holder 持有時成功，有效期限為 ttl
+	AcquireLease(name, holder string, ttl time.Duration) bool
+	// ReleaseLease 釋放 holder 持有的租約，租約不屬於 holder 時返回 false
+	ReleaseLease(name, holder string) bool
+	// SaveLastProcessedBlock 記錄輪詢最後處理完的區塊，新的 leader 由此繼續處理
+	SaveLastProcessedBlock(blockNumber int)
+	// GetLastProcessedBlock 取得輪詢最後處理完的區塊，尚未處理過任何區塊時返回 false
+	GetLastProcessedBlock() (int, bool)
+	// SaveTokenMetadata 以合約地址為鍵
+	SaveTokenMetadata(metadata TokenMetadata)
+	// GetTokenMetadata 取得快取的代幣資訊，尚未查詢過時返回 false
+	GetTokenMetadata(contract string) (TokenMetadata, bool)
+	// SaveEventSubscription 新增或更新事件訂閱，以 ID 為鍵
+	SaveEventSubscription(subscription EventSubscription)
+	// DeleteEventSubscription 移除事件訂閱，已記錄的事件會保留，訂閱不存在時返回 false
+	DeleteEventSubscription(id string) bool
+	// GetEventSubscription 取得事件訂閱，訂閱不存在時返回 false
+	GetEventSubscription(id string) (EventSubscription, bool)
+	// GetEventSubscriptions 依建立時間排序返回所有事件訂閱
+	GetEventSubscriptions() []EventSubscription
+	// SaveContractEvent 以交易哈希值與 LogIndex 為鍵
+	SaveContractEvent(subscriptionID string, event ContractEvent)
+	GetContractEvents(subscriptionID string) []ContractEvent
+}
+
+// SharedStorage Storage 可選實作的介面，Shared 返回 true 表示所有實例讀寫同一份資料
+// 以租約選舉 leader 時需要共用的 Storage，未實作時視為只存在於單一程序中
+type SharedStorage interface {
+	Shared() bool
+}
+
+// EventSubscription 合約事件訂閱，以 Contract 與 Topics 查詢 eth_getLogs 並以 Event 解碼
+// Topics 依位置對應 eth_getLogs 的 topics，同一位置的多個值為 OR，空的位置不過濾
+type EventSubscription struct {
+	ID        string     `json:"id"`
+	Contract  string     `json:"contract"`
+	Topics    [][]string `json:"topics"`
+	Event     abi.Entry  `json:"event"`
+	Label     string     `json:"label"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// ContractEvent 符合事件訂閱的事件，無法以訂閱的 ABI 解碼時 Event 為 nil，仍保留原始的 Topics 與 Data
+type ContractEvent struct {
+	TxHash      string       `json:"txHash"`
+	BlockHash   string       `json:"blockHash"`
+	BlockNumber string       `json:"blockNumber"`
+	LogIndex    string       `json:"logIndex"`
+	Contract    string       `json:"contract"`
+	Topics      []string     `json:"topics"`
+	Data        domain.Bytes `json:"data"`
+	Event       *abi.Call    `json:"event,omitempty"`
+}
+
+// TokenMetadata 以 eth_call 查詢的 ERC-20 代幣資訊，合約未實作或呼叫失敗的欄位為空值，Decimals 為 nil
+type TokenMetadata struct {
+	Contract  string    `json:"contract"`
+	Name      string    `json:"name"`
+	Symbol    string    `json:"symbol"`
+	Decimals  *int      `json:"decimals"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// Lease 租約，ExpiresAt 之前只有 Holder 可以續約
+type Lease struct {
+	Name      string    `json:"name"`
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Subscription 訂閱紀錄，以 ENS 名稱訂閱時記錄 ENSName 並定期重新解析，ExpiresAt 為 nil 時永不過期
+// Direct 表示地址也曾直接以地址訂閱，名稱改指向其他地址時保留此地址的訂閱
+type Subscription struct {
+	Address   string             `json:"address"`
+	ENSName   string             `json:"ensName"`
+	Direct    bool               `json:"direct"`
+	Filter    SubscriptionFilter `json:"filter"`
+	Label     string             `json:"label"`
+	Owner     string             `json:"owner"`
+	CreatedAt time.Time          `json:"createdAt"`
+	ExpiresAt *time.Time         `json:"expiresAt"`
+	Paused    bool               `json:"paused"`
+}
+
+// SubscriptionFilter 訂閱的過濾條件，零值代表不過濾，地址與 selector 皆為小寫的標準格式
+type SubscriptionFilter struct {
+	Direction       string        `json:"direction"`
+	MinValue        domain.BigInt `json:"minValue"`
+	TokenContracts  []string      `json:"tokenContracts"`
+	MethodSelectors []string      `json:"methodSelectors"`
+	ExcludeFailed   bool          `json:"excludeFailed"`
+	TokensOnly      bool          `json:"tokensOnly"`
 }
 
 type Transaction struct {
-	BlockHash   string `json:"blockHash"`
-	BlockNumber string `json:"blockNumber"`
-	From        string `json:"from"`
-	To          string `json:"to"`
-	Value       string `json:"value"`
+	Hash        string        `json:"hash"`
+	BlockHash   string        `json:"blockHash"`
+	BlockNumber string        `json:"blockNumber"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Value       domain.BigInt `json:"value"`
+	GasPrice    domain.BigInt `json:"gasPrice"`
+	GasUsed     domain.BigInt `json:"gasUsed"`
+	Fee         domain.BigInt `json:"fee"`
+	Nonce       string        `json:"nonce"`
+	Input       domain.Bytes  `json:"input"`
+	// Failed 交易收據的 status 為 0，即交易執行失敗
+	Failed bool `json:"failed"`
+	// Type 交易類型，決定手續費的計算方式；Fee 包含執行手續費、BlobFee 與 L1Fee，deposit 交易為 0
+	Type                 domain.TxType `json:"type"`
+	MaxFeePerGas         domain.BigInt `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas domain.BigInt `json:"maxPriorityFeePerGas"`
+	// EIP-4844 blob 交易的欄位，BlobFee 為 BlobGasUsed × BlobGasPrice
+	MaxFeePerBlobGas    domain.BigInt `json:"maxFeePerBlobGas"`
+	BlobVersionedHashes []string      `json:"blobVersionedHashes,omitempty"`
+	BlobGasUsed         domain.BigInt `json:"blobGasUsed"`
+	BlobGasPrice        domain.BigInt `json:"blobGasPrice"`
+	BlobFee             domain.BigInt `json:"blobFee"`
+	// AccessList EIP-2930 之後的 typed 交易計劃存取的地址與 storage key
+	AccessList []AccessListEntry `json:"accessList,omitempty"`
+	// YParity typed 交易簽名的 y parity，legacy 交易為空字串
+	YParity string `json:"yParity,omitempty"`
+	// AuthorizationList EIP-7702 交易的授權清單
+	AuthorizationList []Authorization `json:"authorizationList,omitempty"`
+	// OP Stack 的欄位，L1Fee 為 L2 交易額外支付的 L1 資料費用，SourceHash 與 Mint 僅 deposit 交易有值
+	L1Fee      domain.BigInt `json:"l1Fee"`
+	SourceHash string        `json:"sourceHash,omitempty"`
+	Mint       domain.BigInt `json:"mint"`
+	// Method 以 ABI 解碼的合約呼叫，無法解碼時為 nil
+	Method *abi.Call `json:"method,omitempty"`
+	// Events 以 ABI 解碼的交易收據事件
+	Events []abi.Call `json:"events,omitempty"`
+	// Labels 由交易 pipeline 的處理階段加上的標籤
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// AccessListEntry EIP-2930 access list 的項目
+type AccessListEntry struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+// Authorization EIP-7702 的授權項目，簽署者將程式碼委派給 Address，YParity、R、S 為授權的簽名
+type Authorization struct {
+	ChainID string `json:"chainId"`
+	Address string `json:"address"`
+	Nonce   string `json:"nonce"`
+	YParity string `json:"yParity"`
+	R       string `json:"r"`
+	S       string `json:"s"`
+}
+
+// TokenTransfer ERC-20 Transfer 事件紀錄，以 TxHash 關聯到所屬交易
+type TokenTransfer struct {
+	TxHash      string        `json:"txHash"`
+	BlockHash   string        `json:"blockHash"`
+	BlockNumber string        `json:"blockNumber"`
+	LogIndex    string        `json:"logIndex"`
+	Contract    string        `json:"contract"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Amount      domain.BigInt `json:"amount"`
+}
+
+// NFTTransfer ERC-721 / ERC-1155 轉移紀錄，TransferBatch 會拆成多筆相同 LogIndex 的紀錄
+// BatchIndex 為紀錄在 TransferBatch 中的位置，同一批次可能重複相同的 TokenID，其他事件為 0
+type NFTTransfer struct {
+	TxHash      string        `json:"txHash"`
+	BlockHash   string        `json:"blockHash"`
+	BlockNumber string        `json:"blockNumber"`
+	LogIndex    string        `json:"logIndex"`
+	BatchIndex  int           `json:"batchIndex"`
+	Standard    string        `json:"standard"`
+	Contract    string        `json:"contract"`
+	Operator    string        `json:"operator"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	TokenID     domain.BigInt `json:"tokenId"`
+	Amount      domain.BigInt `json:"amount"`
+}
+
+// InternalTransaction 由合約內部呼叫轉移的 ETH，以 ParentTxHash 關聯到外層交易
+// Index 為同一筆外層交易中依呼叫順序排列的轉移序號
+type InternalTransaction struct {
+	ParentTxHash string        `json:"parentTxHash"`
+	Index        int           `json:"index"`
+	BlockNumber  string        `json:"blockNumber"`
+	Type         string        `json:"type"`
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	Value        domain.BigInt `json:"value"`
+	Depth        int           `json:"depth"`
+}
+
+// Withdrawal 驗證者提款紀錄，Amount 已由區塊中的 gwei 換算為 wei
+type Withdrawal struct {
+	BlockHash      string        `json:"blockHash"`
+	BlockNumber    string        `json:"blockNumber"`
+	Index          string        `json:"index"`
+	ValidatorIndex string        `json:"validatorIndex"`
+	Address        string        `json:"address"`
+	Amount         domain.BigInt `json:"amount"`
+}
+
+// BlockReward 地址作為區塊 fee recipient 所獲得的手續費收入
+// PriorityFees 為各交易 gasUsed × (effectiveGasPrice - baseFeePerGas) 的總和，BurntFees 為被銷毀的基本費用，
+// 倫敦升級前的區塊沒有基本費用，全部手續費皆計入 PriorityFees；共識層的發行獎勵不在此紀錄中
+type BlockReward struct {
+	BlockHash        string        `json:"blockHash"`
+	BlockNumber      string        `json:"blockNumber"`
+	FeeRecipient     string        `json:"feeRecipient"`
+	BaseFeePerGas    domain.BigInt `json:"baseFeePerGas"`
+	PriorityFees     domain.BigInt `json:"priorityFees"`
+	BurntFees        domain.BigInt `json:"burntFees"`
+	TransactionCount int           `json:"transactionCount"`
+}
+
+// BalanceSnapshot 地址在指定區塊結束時的 ETH 餘額
+// Change 為區塊內觀察到的轉帳、手續費、提款與手續費收入造成的餘額變化，Expected 為前一次快照的餘額加上 Change，
+// Discrepancy 為實際餘額減去 Expected，不為 0 代表 Parser 漏掉了影響餘額的活動；
+// Baseline 的快照只作為後續比對的基準，例如訂閱與恢復訂閱時，或區塊活動無法完整取得時，此時 Expected 等於 Balance
+type BalanceSnapshot struct {
+	BlockNumber string        `json:"blockNumber"`
+	Balance     domain.BigInt `json:"balance"`
+	Expected    domain.BigInt `json:"expected"`
+	Change      domain.BigInt `json:"change"`
+	Discrepancy domain.BigInt `json:"discrepancy"`
+	Baseline    bool          `json:"baseline"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+// PendingTransaction 尚未上鏈的交易，上鏈後以 BlockHash 關聯，被同 nonce 交易取代時記錄 ReplacedBy
+type PendingTransaction struct {
+	Hash        string        `json:"hash"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Value       domain.BigInt `json:"value"`
+	Nonce       string        `json:"nonce"`
+	Status      string        `json:"status"`
+	BlockHash   string        `json:"blockHash"`
+	BlockNumber string        `json:"blockNumber"`
+	ReplacedBy  string        `json:"replacedBy"`
+	SeenAt      time.Time     `json:"seenAt"`
 }
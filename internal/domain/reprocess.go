@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidBlockRange  = errors.New("invalid block range: fromBlock must not be negative or greater than toBlock")
+	ErrBlockBeyondHead    = errors.New("invalid block range: toBlock is beyond the chain head")
+	ErrNothingToReprocess = errors.New("no subscribed or selected addresses to reprocess")
+)
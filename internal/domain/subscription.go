@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidDirection = errors.New("invalid direction: must be incoming, outgoing or both")
+	ErrInvalidSelector  = errors.New("invalid method selector: must be 0x-prefixed 4-byte hex")
+	ErrNegativeMinValue = errors.New("invalid min value: must not be negative")
+
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrExpiresInPast        = errors.New("invalid expiry: must be in the future")
+)
+
+// NormalizeDirection 驗證交易方向，空字串視為 both
+func NormalizeDirection(direction string) (string, error) {
+	switch direction {
+	case "", DirectionBoth:
+		return DirectionBoth, nil
+	case DirectionIncoming, DirectionOutgoing:
+		return direction, nil
+	}
+	return "", ErrInvalidDirection
+}
+
+// NormalizeSelector 驗證函式 selector 為 4 bytes 十六進位並轉為小寫，例如 0x095ea7b3
+func NormalizeSelector(selector string) (string, error) {
+	selector = strings.TrimSpace(selector)
+	if len(selector) != 10 || (selector[:2] != "0x" && selector[:2] != "0X") {
+		return "", ErrInvalidSelector
+	}
+	if _, err := hex.DecodeString(selector[2:]); err != nil {
+		return "", ErrInvalidSelector
+	}
+	return "0x" + strings.ToLower(selector[2:]), nil
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var ErrNotTokenContract = errors.New("contract does not implement name, symbol or decimals")
+
+// MaxTokenDecimals 代幣小數位數的上限，uint256 最多為 78 位十進位數
+const MaxTokenDecimals = 77
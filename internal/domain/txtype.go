@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownTxType = errors.New("unknown transaction type")
+
+// TxType 交易類型，對應 EIP-2718 的 type 欄位，以 uint64 保存節點返回的原始值，未知的類型不會被截斷
+type TxType uint64
+
+const (
+	// TxTypeLegacy 傳統交易
+	TxTypeLegacy TxType = 0x00
+	// TxTypeAccessList EIP-2930 access list 交易
+	TxTypeAccessList TxType = 0x01
+	// TxTypeDynamicFee EIP-1559 動態手續費交易
+	TxTypeDynamicFee TxType = 0x02
+	// TxTypeBlob EIP-4844 blob 交易
+	TxTypeBlob TxType = 0x03
+	// TxTypeSetCode EIP-7702 授權 EOA 設定合約程式碼的交易
+	TxTypeSetCode TxType = 0x04
+	// TxTypeDeposit Optimism 等 OP Stack 鏈由 L1 存入的交易，不支付 L2 gas
+	TxTypeDeposit TxType = 0x7e
+)
+
+var txTypeNames = map[TxType]string{
+	TxTypeLegacy:     "legacy",
+	TxTypeAccessList: "access-list",
+	TxTypeDynamicFee: "dynamic-fee",
+	TxTypeBlob:       "blob",
+	TxTypeSetCode:    "set-code",
+	TxTypeDeposit:    "deposit",
+}
+
+// String 返回類型名稱，未知類型以十六進位表示
+func (t TxType) String() string {
+	if name, ok := txTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%x", uint64(t))
+}
+
+// Known 是否為已知的交易類型
+func (t TxType) Known() bool {
+	_, ok := txTypeNames[t]
+	return ok
+}
+
+func (t TxType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText 接受類型名稱，或 String 為未知類型輸出的十六進位值
+func (t *TxType) UnmarshalText(text []byte) error {
+	for txType, name := range txTypeNames {
+		if name == string(text) {
+			*t = txType
+			return nil
+		}
+	}
+	if digits, ok := strings.CutPrefix(string(text), "0x"); ok {
+		if n, err := strconv.ParseUint(digits, 16, 64); err == nil {
+			*t = TxType(n)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownTxType, text)
+}
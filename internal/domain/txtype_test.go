@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTxType_String(t *testing.T) {
+	tests := []struct {
+		txType   TxType
+		expected string
+		known    bool
+	}{
+		{txType: TxTypeLegacy, expected: "legacy", known: true},
+		{txType: TxTypeAccessList, expected: "access-list", known: true},
+		{txType: TxTypeDynamicFee, expected: "dynamic-fee", known: true},
+		{txType: TxTypeBlob, expected: "blob", known: true},
+		{txType: TxTypeSetCode, expected: "set-code", known: true},
+		{txType: TxTypeDeposit, expected: "deposit", known: true},
+		{txType: 0x64, expected: "0x64"},
+		{txType: 0x1ff, expected: "0x1ff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.txType.String())
+			assert.Equal(t, tt.known, tt.txType.Known())
+		})
+	}
+}
+
+func TestTxType_JSON(t *testing.T) {
+	data, err := json.Marshal(TxTypeBlob)
+	assert.NoError(t, err)
+	assert.Equal(t, `"blob"`, string(data))
+
+	var txType TxType
+	assert.NoError(t, json.Unmarshal([]byte(`"deposit"`), &txType))
+	assert.Equal(t, TxTypeDeposit, txType)
+
+	// 未知的類型以十六進位保存，可以還原
+	data, err = json.Marshal(TxType(0x1ff))
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &txType))
+	assert.Equal(t, TxType(0x1ff), txType)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"unknown"`), &txType), ErrUnknownTxType)
+}
@@ -0,0 +1,6 @@
+package usecase
+
+// Leadership 判斷目前的實例是否為 leader
+type Leadership interface {
+	IsLeader() bool
+}
@@ -3,4 +3,15 @@ package usecase
 // Notification interface
 type Notification interface {
 	Notify(address string, tx Transaction)
+	NotifyTokenTransfer(address string, transfer TokenTransfer)
+	NotifyNFTTransfer(address string, transfer NFTTransfer)
+	NotifyInternalTransaction(address string, tx InternalTransaction)
+	NotifyPendingTransaction(address string, tx PendingTransaction)
+	NotifyWithdrawal(address string, withdrawal Withdrawal)
+	NotifyBlockReward(address string, reward BlockReward)
+	NotifyENSChange(address string, change ENSChange)
+	// NotifyContractEvent 符合事件訂閱的事件
+	NotifyContractEvent(subscriptionID string, event ContractEvent)
+	// NotifyBalanceDiscrepancy 實際餘額與觀察到的活動不符時通知
+	NotifyBalanceDiscrepancy(address string, snapshot BalanceSnapshot)
 }
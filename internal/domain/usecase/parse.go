@@ -1,17 +1,323 @@
 package usecase
 
+import (
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"time"
+)
+
 // Parser interface
 type Parser interface {
 	GetCurrentBlock() int
-	Subscribe(address string) bool
+	Subscribe(subscription Subscription) error
+	Unsubscribe(address string) error
+	PauseSubscription(address string) error
+	ResumeSubscription(address string) error
+	GetSubscription(address string) (Subscription, error)
+	GetSubscriptions() []Subscription
+	// ResolveAddress 將 ENS 名稱或地址轉為標準格式的地址，輸入為 ENS 名稱時一併返回正規化的名稱
+	ResolveAddress(nameOrAddress string) (address string, name string, err error)
 	GetTransactions(address string) []Transaction
+	GetTokenTransfers(address string) []TokenTransfer
+	GetNFTTransfers(address string) []NFTTransfer
+	GetInternalTransactions(address string) []InternalTransaction
+	GetPendingTransactions(address string) []PendingTransaction
+	GetWithdrawals(address string) []Withdrawal
+	GetBlockRewards(address string) []BlockReward
+	GetBalanceHistory(address string) []BalanceSnapshot
+	// SubscribeEvent 訂閱合約事件，相同的合約、事件與 topics 只會有一個訂閱
+	SubscribeEvent(subscription EventSubscription) (EventSubscription, error)
+	UnsubscribeEvent(id string) error
+	GetEventSubscriptions() []EventSubscription
+	GetContractEvents(id string) []ContractEvent
+	// GetTokenMetadata 取得代幣的名稱、代號與小數位數，優先使用本地設定，其次為快取，最後以 eth_call 查詢
+	GetTokenMetadata(contract string) (TokenMetadata, error)
+	GetMetrics() Metrics
+	// Status 取得輪詢的進度、落後程度與最後一次錯誤
+	Status() Status
+	// StartReprocess 在背景重新處理歷史區塊，返回剛建立的工作，進度可由 Status 查詢
+	StartReprocess(request ReprocessRequest) (BackfillJob, error)
+	// Reprocess 重新處理歷史區塊並等待完成
+	Reprocess(request ReprocessRequest) (BackfillJob, error)
+	// StartEventBackfill 在背景查詢事件訂閱在歷史區塊中的事件，返回剛建立的工作，進度可由 Status 查詢
+	StartEventBackfill(request EventBackfillRequest) (BackfillJob, error)
 	PollForChanges()
+	WatchPendingTransactions()
+}
+
+// Metrics Parser 啟動後累計的計數
+// Bloom 開頭的欄位為 logs bloom 預先過濾的結果，以地址與區塊的組合計算，
+// BloomFalsePositives 為 bloom 顯示可能符合、但下載事件後沒有該地址轉帳的次數
+type Metrics struct {
+	BloomChecks         uint64 `json:"bloomChecks"`
+	BloomSkips          uint64 `json:"bloomSkips"`
+	BloomMatches        uint64 `json:"bloomMatches"`
+	BloomFalsePositives uint64 `json:"bloomFalsePositives"`
+}
+
+// Status Parser 的處理進度，LagBlocks 為最新區塊與最後處理區塊的差距，LagSeconds 為最後處理區塊產生至今的秒數
+// BlockTimeSeconds 為輪詢使用的出塊間隔，未固定時由區塊的 timestamp 學習
+type Status struct {
+	Leader              bool          `json:"leader"`
+	ChainHead           int           `json:"chainHead"`
+	LastProcessedBlock  int           `json:"lastProcessedBlock"`
+	LagBlocks           int           `json:"lagBlocks"`
+	LagSeconds          int64         `json:"lagSeconds"`
+	LastError           *ErrorStatus  `json:"lastError"`
+	Subscriptions       int           `json:"subscriptions"`
+	ActiveSubscriptions int           `json:"activeSubscriptions"`
+	BlocksPerMinute     int           `json:"blocksPerMinute"`
+	BlockTimeSeconds    float64       `json:"blockTimeSeconds"`
+	BackfillJobs        []BackfillJob `json:"backfillJobs"`
+	CheckedAt           time.Time     `json:"checkedAt"`
+}
+
+// ErrorStatus Parser 最後一次發生的錯誤
+type ErrorStatus struct {
+	Message    string    `json:"message"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// BackfillJob 重新處理歷史區塊的工作，區塊依序處理，CurrentBlock 為最後處理完成的區塊
+// Addresses 為空時處理所有訂閱，FailedBlocks 為無法取得的區塊數，有失敗的區塊時工作狀態為 failed
+// 查詢事件訂閱歷史事件的工作記錄 EventSubscriptionID，不處理地址
+type BackfillJob struct {
+	ID                  string     `json:"id"`
+	FromBlock           int        `json:"fromBlock"`
+	ToBlock             int        `json:"toBlock"`
+	Addresses           []string   `json:"addresses"`
+	EventSubscriptionID string     `json:"eventSubscriptionId,omitempty"`
+	Notify              bool       `json:"notify"`
+	CurrentBlock        int        `json:"currentBlock"`
+	ProcessedBlocks     int        `json:"processedBlocks"`
+	FailedBlocks        int        `json:"failedBlocks"`
+	Status              string     `json:"status"`
+	Error               string     `json:"error,omitempty"`
+	StartedAt           time.Time  `json:"startedAt"`
+	FinishedAt          *time.Time `json:"finishedAt"`
+}
+
+// ReprocessRequest 重新處理 FromBlock 至 ToBlock（包含）的區塊
+// Addresses 為空時處理所有訂閱，未訂閱的地址不套用過濾條件；Notify 為 false 時只保存紀錄不發送通知；
+// Concurrency 為同時處理的區塊數，為 0 時使用預設值
+type ReprocessRequest struct {
+	FromBlock   int
+	ToBlock     int
+	Addresses   []string
+	Notify      bool
+	Concurrency int
+}
+
+// EventBackfillRequest 查詢事件訂閱在 FromBlock 至 ToBlock（包含）的事件，Notify 為 false 時只保存紀錄不發送通知
+type EventBackfillRequest struct {
+	SubscriptionID string
+	FromBlock      int
+	ToBlock        int
+	Notify         bool
+}
+
+// Subscription 訂閱紀錄，以 ENS 名稱訂閱時記錄 ENSName，Status 依暫停狀態與過期時間計算
+type Subscription struct {
+	Address   string             `json:"address"`
+	ENSName   string             `json:"ensName"`
+	Filter    SubscriptionFilter `json:"filter"`
+	Label     string             `json:"label"`
+	Owner     string             `json:"owner"`
+	CreatedAt time.Time          `json:"createdAt"`
+	ExpiresAt *time.Time         `json:"expiresAt"`
+	Paused    bool               `json:"paused"`
+	Status    string             `json:"status"`
+}
+
+// ENSChange ENS 名稱改指向新的地址
+type ENSChange struct {
+	Name       string    `json:"name"`
+	OldAddress string    `json:"oldAddress"`
+	NewAddress string    `json:"newAddress"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// SubscriptionFilter 訂閱的過濾條件，零值代表不過濾
+// Direction 與 MinValue 套用於交易、內部交易與待處理交易，TokenContracts 套用於代幣與 NFT 轉移，
+// MethodSelectors 與 ExcludeFailed 僅套用於交易；TokensOnly 不記錄交易、內部交易與待處理交易，
+// 只記錄代幣與 NFT 轉移、提款與手續費收入，bloom 預先過濾只會對這類訂閱跳過區塊
+type SubscriptionFilter struct {
+	Direction       string        `json:"direction"`
+	MinValue        domain.BigInt `json:"minValue"`
+	TokenContracts  []string      `json:"tokenContracts"`
+	MethodSelectors []string      `json:"methodSelectors"`
+	ExcludeFailed   bool          `json:"excludeFailed"`
+	TokensOnly      bool          `json:"tokensOnly"`
 }
 
 type Transaction struct {
-	BlockHash   string `json:"blockHash"`
-	BlockNumber string `json:"blockNumber"`
-	From        string `json:"from"`
-	To          string `json:"to"`
-	Value       string `json:"value"`
+	Hash        string        `json:"hash"`
+	BlockHash   string        `json:"blockHash"`
+	BlockNumber string        `json:"blockNumber"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Value       domain.BigInt `json:"value"`
+	GasPrice    domain.BigInt `json:"gasPrice"`
+	GasUsed     domain.BigInt `json:"gasUsed"`
+	Fee         domain.BigInt `json:"fee"`
+	Nonce       string        `json:"nonce"`
+	Input       domain.Bytes  `json:"input"`
+	// Failed 交易收據的 status 為 0，即交易執行失敗
+	Failed bool `json:"failed"`
+	// Type 交易類型，決定手續費的計算方式；Fee 包含執行手續費、BlobFee 與 L1Fee，deposit 交易為 0
+	Type                 domain.TxType `json:"type"`
+	MaxFeePerGas         domain.BigInt `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas domain.BigInt `json:"maxPriorityFeePerGas"`
+	// EIP-4844 blob 交易的欄位，BlobFee 為 BlobGasUsed × BlobGasPrice
+	MaxFeePerBlobGas    domain.BigInt `json:"maxFeePerBlobGas"`
+	BlobVersionedHashes []string      `json:"blobVersionedHashes,omitempty"`
+	BlobGasUsed         domain.BigInt `json:"blobGasUsed"`
+	BlobGasPrice        domain.BigInt `json:"blobGasPrice"`
+	BlobFee             domain.BigInt `json:"blobFee"`
+	// AccessList EIP-2930 之後的 typed 交易計劃存取的地址與 storage key
+	AccessList []AccessListEntry `json:"accessList,omitempty"`
+	// YParity typed 交易簽名的 y parity，legacy 交易為空字串
+	YParity string `json:"yParity,omitempty"`
+	// AuthorizationList EIP-7702 交易的授權清單
+	AuthorizationList []Authorization `json:"authorizationList,omitempty"`
+	// OP Stack 的欄位，L1Fee 為 L2 交易額外支付的 L1 資料費用，SourceHash 與 Mint 僅 deposit 交易有值
+	L1Fee      domain.BigInt `json:"l1Fee"`
+	SourceHash string        `json:"sourceHash,omitempty"`
+	Mint       domain.BigInt `json:"mint"`
+	// Method 以 ABI 解碼的合約呼叫，無法解碼時為 nil
+	Method *abi.Call `json:"method,omitempty"`
+	// Events 以 ABI 解碼的交易收據事件
+	Events []abi.Call `json:"events,omitempty"`
+	// Labels 由交易 pipeline 的處理階段加上的標籤
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// AccessListEntry EIP-2930 access list 的項目
+type AccessListEntry struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+// Authorization EIP-7702 的授權項目，簽署者將程式碼委派給 Address，YParity、R、S 為授權的簽名
+type Authorization struct {
+	ChainID string `json:"chainId"`
+	Address string `json:"address"`
+	Nonce   string `json:"nonce"`
+	YParity string `json:"yParity"`
+	R       string `json:"r"`
+	S       string `json:"s"`
+}
+
+type TokenTransfer struct {
+	TxHash      string        `json:"txHash"`
+	BlockHash   string        `json:"blockHash"`
+	BlockNumber string        `json:"blockNumber"`
+	LogIndex    string        `json:"logIndex"`
+	Contract    string        `json:"contract"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Amount      domain.BigInt `json:"amount"`
+}
+
+// EventSubscription 合約事件訂閱，Contract 可以是地址或 ENS 名稱，Event 為事件的 ABI fragment
+// Topics 依位置對應 eth_getLogs 的 topics，同一位置的多個值為 OR，空的位置不過濾；非匿名事件的第一個位置固定為事件簽名
+type EventSubscription struct {
+	ID        string     `json:"id"`
+	Contract  string     `json:"contract"`
+	Topics    [][]string `json:"topics"`
+	Event     abi.Entry  `json:"event"`
+	Signature string     `json:"signature"`
+	Label     string     `json:"label"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// ContractEvent 符合事件訂閱的事件，無法以訂閱的 ABI 解碼時 Event 為 nil
+type ContractEvent struct {
+	SubscriptionID string       `json:"subscriptionId"`
+	TxHash         string       `json:"txHash"`
+	BlockHash      string       `json:"blockHash"`
+	BlockNumber    string       `json:"blockNumber"`
+	LogIndex       string       `json:"logIndex"`
+	Contract       string       `json:"contract"`
+	Topics         []string     `json:"topics"`
+	Data           domain.Bytes `json:"data"`
+	Event          *abi.Call    `json:"event,omitempty"`
+}
+
+// TokenMetadata 代幣資訊，Source 為 override 時至少一個欄位來自本地設定，Decimals 為 nil 時無法換算金額
+type TokenMetadata struct {
+	Contract string `json:"contract"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals *int   `json:"decimals"`
+	Source   string `json:"source"`
+}
+
+type NFTTransfer struct {
+	TxHash      string        `json:"txHash"`
+	BlockHash   string        `json:"blockHash"`
+	BlockNumber string        `json:"blockNumber"`
+	LogIndex    string        `json:"logIndex"`
+	BatchIndex  int           `json:"batchIndex"`
+	Standard    string        `json:"standard"`
+	Contract    string        `json:"contract"`
+	Operator    string        `json:"operator"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	TokenID     domain.BigInt `json:"tokenId"`
+	Amount      domain.BigInt `json:"amount"`
+}
+
+type InternalTransaction struct {
+	ParentTxHash string        `json:"parentTxHash"`
+	Index        int           `json:"index"`
+	BlockNumber  string        `json:"blockNumber"`
+	Type         string        `json:"type"`
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	Value        domain.BigInt `json:"value"`
+	Depth        int           `json:"depth"`
+}
+
+type Withdrawal struct {
+	BlockHash      string        `json:"blockHash"`
+	BlockNumber    string        `json:"blockNumber"`
+	Index          string        `json:"index"`
+	ValidatorIndex string        `json:"validatorIndex"`
+	Address        string        `json:"address"`
+	Amount         domain.BigInt `json:"amount"`
+}
+
+type BlockReward struct {
+	BlockHash        string        `json:"blockHash"`
+	BlockNumber      string        `json:"blockNumber"`
+	FeeRecipient     string        `json:"feeRecipient"`
+	BaseFeePerGas    domain.BigInt `json:"baseFeePerGas"`
+	PriorityFees     domain.BigInt `json:"priorityFees"`
+	BurntFees        domain.BigInt `json:"burntFees"`
+	TransactionCount int           `json:"transactionCount"`
+}
+
+// BalanceSnapshot 地址在指定區塊結束時的 ETH 餘額，Discrepancy 不為 0 代表實際餘額與觀察到的活動不符
+type BalanceSnapshot struct {
+	BlockNumber string        `json:"blockNumber"`
+	Balance     domain.BigInt `json:"balance"`
+	Expected    domain.BigInt `json:"expected"`
+	Change      domain.BigInt `json:"change"`
+	Discrepancy domain.BigInt `json:"discrepancy"`
+	Baseline    bool          `json:"baseline"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+type PendingTransaction struct {
+	Hash        string        `json:"hash"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Value       domain.BigInt `json:"value"`
+	Nonce       string        `json:"nonce"`
+	Status      string        `json:"status"`
+	BlockHash   string        `json:"blockHash"`
+	BlockNumber string        `json:"blockNumber"`
+	ReplacedBy  string        `json:"replacedBy"`
+	SeenAt      time.Time     `json:"seenAt"`
 }
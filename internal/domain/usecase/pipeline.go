@@ -0,0 +1,40 @@
+package usecase
+
+// Processor pipeline 的一個處理階段：enricher 補充紀錄的資料，filter 決定是否保留紀錄，sink 將紀錄送往其他系統
+type Processor interface {
+	// Process 返回 false 時丟棄紀錄，後續的階段不再執行，紀錄也不會被保存與通知
+	Process(item *PipelineItem) (keep bool, err error)
+}
+
+// PipelineItem 流經 pipeline 的紀錄，依 Kind 只有對應的欄位不為 nil
+// 處理階段對紀錄的修改都會被保存與通知
+type PipelineItem struct {
+	// Address 紀錄相關的訂閱地址，Filter 為該訂閱的過濾條件
+	Address string
+	Filter  SubscriptionFilter
+	// Kind 紀錄的種類，例如 transaction、token_transfer
+	Kind                string
+	Transaction         *Transaction
+	TokenTransfer       *TokenTransfer
+	NFTTransfer         *NFTTransfer
+	InternalTransaction *InternalTransaction
+	// Replay 重新處理歷史區塊時為 true，不應重複送出的 sink 可以略過
+	Replay bool
+}
+
+// PipelineStage pipeline 中註冊的處理階段
+type PipelineStage struct {
+	Name      string
+	Processor Processor
+	// Order 執行順序，數字小的先執行，相同時依註冊順序
+	Order int
+	// OnError 處理失敗時的方式，continue 略過此階段繼續執行，drop 丟棄紀錄，預設為 continue
+	OnError string
+	// Retries 處理失敗時重試的次數
+	Retries int
+}
+
+// Pipeline 依序執行各階段，返回紀錄是否保留以及各階段發生的錯誤
+type Pipeline interface {
+	Run(item *PipelineItem) (keep bool, err error)
+}
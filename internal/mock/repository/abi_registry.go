@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/repository/abi_registry.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/domain/repository/abi_registry.go -destination=./internal/mock/repository/abi_registry.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	abi "parse_server/internal/domain/abi"
+	repository "parse_server/internal/domain/repository"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockABIRegistry is a mock of ABIRegistry interface.
+type MockABIRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockABIRegistryMockRecorder
+}
+
+// MockABIRegistryMockRecorder is the mock recorder for MockABIRegistry.
+type MockABIRegistryMockRecorder struct {
+	mock *MockABIRegistry
+}
+
+// NewMockABIRegistry creates a new mock instance.
+func NewMockABIRegistry(ctrl *gomock.Controller) *MockABIRegistry {
+	mock := &MockABIRegistry{ctrl: ctrl}
+	mock.recorder = &MockABIRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockABIRegistry) EXPECT() *MockABIRegistryMockRecorder {
+	return m.recorder
+}
+
+// DecodeInput mocks base method.
+func (m *MockABIRegistry) DecodeInput(input []byte) (*abi.Call, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecodeInput", input)
+	ret0, _ := ret[0].(*abi.Call)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// DecodeInput indicates an expected call of DecodeInput.
+func (mr *MockABIRegistryMockRecorder) DecodeInput(input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeInput", reflect.TypeOf((*MockABIRegistry)(nil).DecodeInput), input)
+}
+
+// DecodeLog mocks base method.
+func (m *MockABIRegistry) DecodeLog(log repository.Log) (*abi.Call, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecodeLog", log)
+	ret0, _ := ret[0].(*abi.Call)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// DecodeLog indicates an expected call of DecodeLog.
+func (mr *MockABIRegistryMockRecorder) DecodeLog(log any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeLog", reflect.TypeOf((*MockABIRegistry)(nil).DecodeLog), log)
+}
@@ -52,3 +52,40 @@ func (mr *MockETHClientMockRecorder) CallEthereum(method, params any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallEthereum", reflect.TypeOf((*MockETHClient)(nil).CallEthereum), method, params)
 }
+
+// MockEndpointNamer is a mock of EndpointNamer interface.
+type MockEndpointNamer struct {
+	ctrl     *gomock.Controller
+	recorder *MockEndpointNamerMockRecorder
+}
+
+// MockEndpointNamerMockRecorder is the mock recorder for MockEndpointNamer.
+type MockEndpointNamerMockRecorder struct {
+	mock *MockEndpointNamer
+}
+
+// NewMockEndpointNamer creates a new mock instance.
+func NewMockEndpointNamer(ctrl *gomock.Controller) *MockEndpointNamer {
+	mock := &MockEndpointNamer{ctrl: ctrl}
+	mock.recorder = &MockEndpointNamerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEndpointNamer) EXPECT() *MockEndpointNamerMockRecorder {
+	return m.recorder
+}
+
+// Endpoint mocks base method.
+func (m *MockEndpointNamer) Endpoint() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Endpoint")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Endpoint indicates an expected call of Endpoint.
+func (mr *MockEndpointNamerMockRecorder) Endpoint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Endpoint", reflect.TypeOf((*MockEndpointNamer)(nil).Endpoint))
+}
@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/repository/leader.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/domain/repository/leader.go -destination=./internal/mock/repository/leader.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLeaderElector is a mock of LeaderElector interface.
+type MockLeaderElector struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaderElectorMockRecorder
+}
+
+// MockLeaderElectorMockRecorder is the mock recorder for MockLeaderElector.
+type MockLeaderElectorMockRecorder struct {
+	mock *MockLeaderElector
+}
+
+// NewMockLeaderElector creates a new mock instance.
+func NewMockLeaderElector(ctrl *gomock.Controller) *MockLeaderElector {
+	mock := &MockLeaderElector{ctrl: ctrl}
+	mock.recorder = &MockLeaderElectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaderElector) EXPECT() *MockLeaderElectorMockRecorder {
+	return m.recorder
+}
+
+// Release mocks base method.
+func (m *MockLeaderElector) Release() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockLeaderElectorMockRecorder) Release() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLeaderElector)(nil).Release))
+}
+
+// TryAcquire mocks base method.
+func (m *MockLeaderElector) TryAcquire() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TryAcquire")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TryAcquire indicates an expected call of TryAcquire.
+func (mr *MockLeaderElectorMockRecorder) TryAcquire() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryAcquire", reflect.TypeOf((*MockLeaderElector)(nil).TryAcquire))
+}
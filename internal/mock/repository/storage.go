@@ -12,6 +12,7 @@ package mock
 import (
 	repository "parse_server/internal/domain/repository"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -39,18 +40,233 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
-// GetSubscribedAddresses mocks base method.
-func (m *MockStorage) GetSubscribedAddresses() []string {
+// AcquireLease mocks base method.
+func (m *MockStorage) AcquireLease(name, holder string, ttl time.Duration) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSubscribedAddresses")
-	ret0, _ := ret[0].([]string)
+	ret := m.ctrl.Call(m, "AcquireLease", name, holder, ttl)
+	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
-// GetSubscribedAddresses indicates an expected call of GetSubscribedAddresses.
-func (mr *MockStorageMockRecorder) GetSubscribedAddresses() *gomock.Call {
+// AcquireLease indicates an expected call of AcquireLease.
+func (mr *MockStorageMockRecorder) AcquireLease(name, holder, ttl any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscribedAddresses", reflect.TypeOf((*MockStorage)(nil).GetSubscribedAddresses))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLease", reflect.TypeOf((*MockStorage)(nil).AcquireLease), name, holder, ttl)
+}
+
+// DeleteEventSubscription mocks base method.
+func (m *MockStorage) DeleteEventSubscription(id string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventSubscription", id)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// DeleteEventSubscription indicates an expected call of DeleteEventSubscription.
+func (mr *MockStorageMockRecorder) DeleteEventSubscription(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventSubscription", reflect.TypeOf((*MockStorage)(nil).DeleteEventSubscription), id)
+}
+
+// GetBalanceSnapshots mocks base method.
+func (m *MockStorage) GetBalanceSnapshots(address string) []repository.BalanceSnapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceSnapshots", address)
+	ret0, _ := ret[0].([]repository.BalanceSnapshot)
+	return ret0
+}
+
+// GetBalanceSnapshots indicates an expected call of GetBalanceSnapshots.
+func (mr *MockStorageMockRecorder) GetBalanceSnapshots(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceSnapshots", reflect.TypeOf((*MockStorage)(nil).GetBalanceSnapshots), address)
+}
+
+// GetBlockRewards mocks base method.
+func (m *MockStorage) GetBlockRewards(address string) []repository.BlockReward {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockRewards", address)
+	ret0, _ := ret[0].([]repository.BlockReward)
+	return ret0
+}
+
+// GetBlockRewards indicates an expected call of GetBlockRewards.
+func (mr *MockStorageMockRecorder) GetBlockRewards(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockRewards", reflect.TypeOf((*MockStorage)(nil).GetBlockRewards), address)
+}
+
+// GetContractEvents mocks base method.
+func (m *MockStorage) GetContractEvents(subscriptionID string) []repository.ContractEvent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContractEvents", subscriptionID)
+	ret0, _ := ret[0].([]repository.ContractEvent)
+	return ret0
+}
+
+// GetContractEvents indicates an expected call of GetContractEvents.
+func (mr *MockStorageMockRecorder) GetContractEvents(subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContractEvents", reflect.TypeOf((*MockStorage)(nil).GetContractEvents), subscriptionID)
+}
+
+// GetEventSubscription mocks base method.
+func (m *MockStorage) GetEventSubscription(id string) (repository.EventSubscription, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventSubscription", id)
+	ret0, _ := ret[0].(repository.EventSubscription)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetEventSubscription indicates an expected call of GetEventSubscription.
+func (mr *MockStorageMockRecorder) GetEventSubscription(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventSubscription", reflect.TypeOf((*MockStorage)(nil).GetEventSubscription), id)
+}
+
+// GetEventSubscriptions mocks base method.
+func (m *MockStorage) GetEventSubscriptions() []repository.EventSubscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventSubscriptions")
+	ret0, _ := ret[0].([]repository.EventSubscription)
+	return ret0
+}
+
+// GetEventSubscriptions indicates an expected call of GetEventSubscriptions.
+func (mr *MockStorageMockRecorder) GetEventSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventSubscriptions", reflect.TypeOf((*MockStorage)(nil).GetEventSubscriptions))
+}
+
+// GetInternalTransactions mocks base method.
+func (m *MockStorage) GetInternalTransactions(address string) []repository.InternalTransaction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalTransactions", address)
+	ret0, _ := ret[0].([]repository.InternalTransaction)
+	return ret0
+}
+
+// GetInternalTransactions indicates an expected call of GetInternalTransactions.
+func (mr *MockStorageMockRecorder) GetInternalTransactions(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalTransactions", reflect.TypeOf((*MockStorage)(nil).GetInternalTransactions), address)
+}
+
+// GetLastProcessedBlock mocks base method.
+func (m *MockStorage) GetLastProcessedBlock() (int, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastProcessedBlock")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetLastProcessedBlock indicates an expected call of GetLastProcessedBlock.
+func (mr *MockStorageMockRecorder) GetLastProcessedBlock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastProcessedBlock", reflect.TypeOf((*MockStorage)(nil).GetLastProcessedBlock))
+}
+
+// GetLatestBalanceSnapshot mocks base method.
+func (m *MockStorage) GetLatestBalanceSnapshot(address string) (repository.BalanceSnapshot, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestBalanceSnapshot", address)
+	ret0, _ := ret[0].(repository.BalanceSnapshot)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetLatestBalanceSnapshot indicates an expected call of GetLatestBalanceSnapshot.
+func (mr *MockStorageMockRecorder) GetLatestBalanceSnapshot(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestBalanceSnapshot", reflect.TypeOf((*MockStorage)(nil).GetLatestBalanceSnapshot), address)
+}
+
+// GetNFTTransfers mocks base method.
+func (m *MockStorage) GetNFTTransfers(address string) []repository.NFTTransfer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNFTTransfers", address)
+	ret0, _ := ret[0].([]repository.NFTTransfer)
+	return ret0
+}
+
+// GetNFTTransfers indicates an expected call of GetNFTTransfers.
+func (mr *MockStorageMockRecorder) GetNFTTransfers(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNFTTransfers", reflect.TypeOf((*MockStorage)(nil).GetNFTTransfers), address)
+}
+
+// GetPendingTransactions mocks base method.
+func (m *MockStorage) GetPendingTransactions(address string) []repository.PendingTransaction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransactions", address)
+	ret0, _ := ret[0].([]repository.PendingTransaction)
+	return ret0
+}
+
+// GetPendingTransactions indicates an expected call of GetPendingTransactions.
+func (mr *MockStorageMockRecorder) GetPendingTransactions(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransactions", reflect.TypeOf((*MockStorage)(nil).GetPendingTransactions), address)
+}
+
+// GetSubscription mocks base method.
+func (m *MockStorage) GetSubscription(address string) (repository.Subscription, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", address)
+	ret0, _ := ret[0].(repository.Subscription)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockStorageMockRecorder) GetSubscription(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockStorage)(nil).GetSubscription), address)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockStorage) GetSubscriptions() []repository.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions")
+	ret0, _ := ret[0].([]repository.Subscription)
+	return ret0
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockStorageMockRecorder) GetSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockStorage)(nil).GetSubscriptions))
+}
+
+// GetTokenMetadata mocks base method.
+func (m *MockStorage) GetTokenMetadata(contract string) (repository.TokenMetadata, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenMetadata", contract)
+	ret0, _ := ret[0].(repository.TokenMetadata)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// GetTokenMetadata indicates an expected call of GetTokenMetadata.
+func (mr *MockStorageMockRecorder) GetTokenMetadata(contract any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenMetadata", reflect.TypeOf((*MockStorage)(nil).GetTokenMetadata), contract)
+}
+
+// GetTokenTransfers mocks base method.
+func (m *MockStorage) GetTokenTransfers(address string) []repository.TokenTransfer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenTransfers", address)
+	ret0, _ := ret[0].([]repository.TokenTransfer)
+	return ret0
+}
+
+// GetTokenTransfers indicates an expected call of GetTokenTransfers.
+func (mr *MockStorageMockRecorder) GetTokenTransfers(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenTransfers", reflect.TypeOf((*MockStorage)(nil).GetTokenTransfers), address)
 }
 
 // GetTransactions mocks base method.
@@ -67,6 +283,168 @@ func (mr *MockStorageMockRecorder) GetTransactions(address any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockStorage)(nil).GetTransactions), address)
 }
 
+// GetWithdrawals mocks base method.
+func (m *MockStorage) GetWithdrawals(address string) []repository.Withdrawal {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithdrawals", address)
+	ret0, _ := ret[0].([]repository.Withdrawal)
+	return ret0
+}
+
+// GetWithdrawals indicates an expected call of GetWithdrawals.
+func (mr *MockStorageMockRecorder) GetWithdrawals(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawals", reflect.TypeOf((*MockStorage)(nil).GetWithdrawals), address)
+}
+
+// MoveSubscription mocks base method.
+func (m *MockStorage) MoveSubscription(from repository.Subscription, kept, target *repository.Subscription, moved repository.Subscription) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveSubscription", from, kept, target, moved)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// MoveSubscription indicates an expected call of MoveSubscription.
+func (mr *MockStorageMockRecorder) MoveSubscription(from, kept, target, moved any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveSubscription", reflect.TypeOf((*MockStorage)(nil).MoveSubscription), from, kept, target, moved)
+}
+
+// ReleaseLease mocks base method.
+func (m *MockStorage) ReleaseLease(name, holder string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLease", name, holder)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ReleaseLease indicates an expected call of ReleaseLease.
+func (mr *MockStorageMockRecorder) ReleaseLease(name, holder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLease", reflect.TypeOf((*MockStorage)(nil).ReleaseLease), name, holder)
+}
+
+// SaveBalanceSnapshot mocks base method.
+func (m *MockStorage) SaveBalanceSnapshot(address string, snapshot repository.BalanceSnapshot) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveBalanceSnapshot", address, snapshot)
+}
+
+// SaveBalanceSnapshot indicates an expected call of SaveBalanceSnapshot.
+func (mr *MockStorageMockRecorder) SaveBalanceSnapshot(address, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBalanceSnapshot", reflect.TypeOf((*MockStorage)(nil).SaveBalanceSnapshot), address, snapshot)
+}
+
+// SaveBlockReward mocks base method.
+func (m *MockStorage) SaveBlockReward(address string, reward repository.BlockReward) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveBlockReward", address, reward)
+}
+
+// SaveBlockReward indicates an expected call of SaveBlockReward.
+func (mr *MockStorageMockRecorder) SaveBlockReward(address, reward any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBlockReward", reflect.TypeOf((*MockStorage)(nil).SaveBlockReward), address, reward)
+}
+
+// SaveContractEvent mocks base method.
+func (m *MockStorage) SaveContractEvent(subscriptionID string, event repository.ContractEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveContractEvent", subscriptionID, event)
+}
+
+// SaveContractEvent indicates an expected call of SaveContractEvent.
+func (mr *MockStorageMockRecorder) SaveContractEvent(subscriptionID, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveContractEvent", reflect.TypeOf((*MockStorage)(nil).SaveContractEvent), subscriptionID, event)
+}
+
+// SaveEventSubscription mocks base method.
+func (m *MockStorage) SaveEventSubscription(subscription repository.EventSubscription) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveEventSubscription", subscription)
+}
+
+// SaveEventSubscription indicates an expected call of SaveEventSubscription.
+func (mr *MockStorageMockRecorder) SaveEventSubscription(subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveEventSubscription", reflect.TypeOf((*MockStorage)(nil).SaveEventSubscription), subscription)
+}
+
+// SaveInternalTransaction mocks base method.
+func (m *MockStorage) SaveInternalTransaction(address string, tx repository.InternalTransaction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveInternalTransaction", address, tx)
+}
+
+// SaveInternalTransaction indicates an expected call of SaveInternalTransaction.
+func (mr *MockStorageMockRecorder) SaveInternalTransaction(address, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveInternalTransaction", reflect.TypeOf((*MockStorage)(nil).SaveInternalTransaction), address, tx)
+}
+
+// SaveLastProcessedBlock mocks base method.
+func (m *MockStorage) SaveLastProcessedBlock(blockNumber int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveLastProcessedBlock", blockNumber)
+}
+
+// SaveLastProcessedBlock indicates an expected call of SaveLastProcessedBlock.
+func (mr *MockStorageMockRecorder) SaveLastProcessedBlock(blockNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLastProcessedBlock", reflect.TypeOf((*MockStorage)(nil).SaveLastProcessedBlock), blockNumber)
+}
+
+// SaveNFTTransfer mocks base method.
+func (m *MockStorage) SaveNFTTransfer(address string, transfer repository.NFTTransfer) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveNFTTransfer", address, transfer)
+}
+
+// SaveNFTTransfer indicates an expected call of SaveNFTTransfer.
+func (mr *MockStorageMockRecorder) SaveNFTTransfer(address, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNFTTransfer", reflect.TypeOf((*MockStorage)(nil).SaveNFTTransfer), address, transfer)
+}
+
+// SavePendingTransaction mocks base method.
+func (m *MockStorage) SavePendingTransaction(address string, tx repository.PendingTransaction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SavePendingTransaction", address, tx)
+}
+
+// SavePendingTransaction indicates an expected call of SavePendingTransaction.
+func (mr *MockStorageMockRecorder) SavePendingTransaction(address, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePendingTransaction", reflect.TypeOf((*MockStorage)(nil).SavePendingTransaction), address, tx)
+}
+
+// SaveTokenMetadata mocks base method.
+func (m *MockStorage) SaveTokenMetadata(metadata repository.TokenMetadata) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveTokenMetadata", metadata)
+}
+
+// SaveTokenMetadata indicates an expected call of SaveTokenMetadata.
+func (mr *MockStorageMockRecorder) SaveTokenMetadata(metadata any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTokenMetadata", reflect.TypeOf((*MockStorage)(nil).SaveTokenMetadata), metadata)
+}
+
+// SaveTokenTransfer mocks base method.
+func (m *MockStorage) SaveTokenTransfer(address string, transfer repository.TokenTransfer) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveTokenTransfer", address, transfer)
+}
+
+// SaveTokenTransfer indicates an expected call of SaveTokenTransfer.
+func (mr *MockStorageMockRecorder) SaveTokenTransfer(address, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTokenTransfer", reflect.TypeOf((*MockStorage)(nil).SaveTokenTransfer), address, transfer)
+}
+
 // SaveTransaction mocks base method.
 func (m *MockStorage) SaveTransaction(address string, tx repository.Transaction) {
 	m.ctrl.T.Helper()
@@ -79,14 +457,91 @@ func (mr *MockStorageMockRecorder) SaveTransaction(address, tx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTransaction", reflect.TypeOf((*MockStorage)(nil).SaveTransaction), address, tx)
 }
 
+// SaveWithdrawal mocks base method.
+func (m *MockStorage) SaveWithdrawal(address string, withdrawal repository.Withdrawal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveWithdrawal", address, withdrawal)
+}
+
+// SaveWithdrawal indicates an expected call of SaveWithdrawal.
+func (mr *MockStorageMockRecorder) SaveWithdrawal(address, withdrawal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWithdrawal", reflect.TypeOf((*MockStorage)(nil).SaveWithdrawal), address, withdrawal)
+}
+
+// SetSubscriptionPaused mocks base method.
+func (m *MockStorage) SetSubscriptionPaused(address string, paused bool) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSubscriptionPaused", address, paused)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SetSubscriptionPaused indicates an expected call of SetSubscriptionPaused.
+func (mr *MockStorageMockRecorder) SetSubscriptionPaused(address, paused any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSubscriptionPaused", reflect.TypeOf((*MockStorage)(nil).SetSubscriptionPaused), address, paused)
+}
+
 // SubscribeAddress mocks base method.
-func (m *MockStorage) SubscribeAddress(address string) {
+func (m *MockStorage) SubscribeAddress(subscription repository.Subscription) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "SubscribeAddress", address)
+	m.ctrl.Call(m, "SubscribeAddress", subscription)
 }
 
 // SubscribeAddress indicates an expected call of SubscribeAddress.
-func (mr *MockStorageMockRecorder) SubscribeAddress(address any) *gomock.Call {
+func (mr *MockStorageMockRecorder) SubscribeAddress(subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeAddress", reflect.TypeOf((*MockStorage)(nil).SubscribeAddress), subscription)
+}
+
+// UnsubscribeAddress mocks base method.
+func (m *MockStorage) UnsubscribeAddress(address string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeAddress", address)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// UnsubscribeAddress indicates an expected call of UnsubscribeAddress.
+func (mr *MockStorageMockRecorder) UnsubscribeAddress(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeAddress", reflect.TypeOf((*MockStorage)(nil).UnsubscribeAddress), address)
+}
+
+// MockSharedStorage is a mock of SharedStorage interface.
+type MockSharedStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockSharedStorageMockRecorder
+}
+
+// MockSharedStorageMockRecorder is the mock recorder for MockSharedStorage.
+type MockSharedStorageMockRecorder struct {
+	mock *MockSharedStorage
+}
+
+// NewMockSharedStorage creates a new mock instance.
+func NewMockSharedStorage(ctrl *gomock.Controller) *MockSharedStorage {
+	mock := &MockSharedStorage{ctrl: ctrl}
+	mock.recorder = &MockSharedStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSharedStorage) EXPECT() *MockSharedStorageMockRecorder {
+	return m.recorder
+}
+
+// Shared mocks base method.
+func (m *MockSharedStorage) Shared() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Shared")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Shared indicates an expected call of Shared.
+func (mr *MockSharedStorageMockRecorder) Shared() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeAddress", reflect.TypeOf((*MockStorage)(nil).SubscribeAddress), address)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shared", reflect.TypeOf((*MockSharedStorage)(nil).Shared))
 }
@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/usecase/leader.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/domain/usecase/leader.go -destination=./internal/mock/usecase/leader.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLeadership is a mock of Leadership interface.
+type MockLeadership struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeadershipMockRecorder
+}
+
+// MockLeadershipMockRecorder is the mock recorder for MockLeadership.
+type MockLeadershipMockRecorder struct {
+	mock *MockLeadership
+}
+
+// NewMockLeadership creates a new mock instance.
+func NewMockLeadership(ctrl *gomock.Controller) *MockLeadership {
+	mock := &MockLeadership{ctrl: ctrl}
+	mock.recorder = &MockLeadershipMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeadership) EXPECT() *MockLeadershipMockRecorder {
+	return m.recorder
+}
+
+// IsLeader mocks base method.
+func (m *MockLeadership) IsLeader() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLeader")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsLeader indicates an expected call of IsLeader.
+func (mr *MockLeadershipMockRecorder) IsLeader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLeader", reflect.TypeOf((*MockLeadership)(nil).IsLeader))
+}
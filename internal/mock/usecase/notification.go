@@ -50,3 +50,111 @@ func (mr *MockNotificationMockRecorder) Notify(address, tx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotification)(nil).Notify), address, tx)
 }
+
+// NotifyBalanceDiscrepancy mocks base method.
+func (m *MockNotification) NotifyBalanceDiscrepancy(address string, snapshot usecase.BalanceSnapshot) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyBalanceDiscrepancy", address, snapshot)
+}
+
+// NotifyBalanceDiscrepancy indicates an expected call of NotifyBalanceDiscrepancy.
+func (mr *MockNotificationMockRecorder) NotifyBalanceDiscrepancy(address, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyBalanceDiscrepancy", reflect.TypeOf((*MockNotification)(nil).NotifyBalanceDiscrepancy), address, snapshot)
+}
+
+// NotifyBlockReward mocks base method.
+func (m *MockNotification) NotifyBlockReward(address string, reward usecase.BlockReward) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyBlockReward", address, reward)
+}
+
+// NotifyBlockReward indicates an expected call of NotifyBlockReward.
+func (mr *MockNotificationMockRecorder) NotifyBlockReward(address, reward any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyBlockReward", reflect.TypeOf((*MockNotification)(nil).NotifyBlockReward), address, reward)
+}
+
+// NotifyContractEvent mocks base method.
+func (m *MockNotification) NotifyContractEvent(subscriptionID string, event usecase.ContractEvent) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyContractEvent", subscriptionID, event)
+}
+
+// NotifyContractEvent indicates an expected call of NotifyContractEvent.
+func (mr *MockNotificationMockRecorder) NotifyContractEvent(subscriptionID, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyContractEvent", reflect.TypeOf((*MockNotification)(nil).NotifyContractEvent), subscriptionID, event)
+}
+
+// NotifyENSChange mocks base method.
+func (m *MockNotification) NotifyENSChange(address string, change usecase.ENSChange) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyENSChange", address, change)
+}
+
+// NotifyENSChange indicates an expected call of NotifyENSChange.
+func (mr *MockNotificationMockRecorder) NotifyENSChange(address, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyENSChange", reflect.TypeOf((*MockNotification)(nil).NotifyENSChange), address, change)
+}
+
+// NotifyInternalTransaction mocks base method.
+func (m *MockNotification) NotifyInternalTransaction(address string, tx usecase.InternalTransaction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyInternalTransaction", address, tx)
+}
+
+// NotifyInternalTransaction indicates an expected call of NotifyInternalTransaction.
+func (mr *MockNotificationMockRecorder) NotifyInternalTransaction(address, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyInternalTransaction", reflect.TypeOf((*MockNotification)(nil).NotifyInternalTransaction), address, tx)
+}
+
+// NotifyNFTTransfer mocks base method.
+func (m *MockNotification) NotifyNFTTransfer(address string, transfer usecase.NFTTransfer) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyNFTTransfer", address, transfer)
+}
+
+// NotifyNFTTransfer indicates an expected call of NotifyNFTTransfer.
+func (mr *MockNotificationMockRecorder) NotifyNFTTransfer(address, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyNFTTransfer", reflect.TypeOf((*MockNotification)(nil).NotifyNFTTransfer), address, transfer)
+}
+
+// NotifyPendingTransaction mocks base method.
+func (m *MockNotification) NotifyPendingTransaction(address string, tx usecase.PendingTransaction) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyPendingTransaction", address, tx)
+}
+
+// NotifyPendingTransaction indicates an expected call of NotifyPendingTransaction.
+func (mr *MockNotificationMockRecorder) NotifyPendingTransaction(address, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyPendingTransaction", reflect.TypeOf((*MockNotification)(nil).NotifyPendingTransaction), address, tx)
+}
+
+// NotifyTokenTransfer mocks base method.
+func (m *MockNotification) NotifyTokenTransfer(address string, transfer usecase.TokenTransfer) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyTokenTransfer", address, transfer)
+}
+
+// NotifyTokenTransfer indicates an expected call of NotifyTokenTransfer.
+func (mr *MockNotificationMockRecorder) NotifyTokenTransfer(address, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyTokenTransfer", reflect.TypeOf((*MockNotification)(nil).NotifyTokenTransfer), address, transfer)
+}
+
+// NotifyWithdrawal mocks base method.
+func (m *MockNotification) NotifyWithdrawal(address string, withdrawal usecase.Withdrawal) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyWithdrawal", address, withdrawal)
+}
+
+// NotifyWithdrawal indicates an expected call of NotifyWithdrawal.
+func (mr *MockNotificationMockRecorder) NotifyWithdrawal(address, withdrawal any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyWithdrawal", reflect.TypeOf((*MockNotification)(nil).NotifyWithdrawal), address, withdrawal)
+}
@@ -39,6 +39,48 @@ func (m *MockParser) EXPECT() *MockParserMockRecorder {
 	return m.recorder
 }
 
+// GetBalanceHistory mocks base method.
+func (m *MockParser) GetBalanceHistory(address string) []usecase.BalanceSnapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceHistory", address)
+	ret0, _ := ret[0].([]usecase.BalanceSnapshot)
+	return ret0
+}
+
+// GetBalanceHistory indicates an expected call of GetBalanceHistory.
+func (mr *MockParserMockRecorder) GetBalanceHistory(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceHistory", reflect.TypeOf((*MockParser)(nil).GetBalanceHistory), address)
+}
+
+// GetBlockRewards mocks base method.
+func (m *MockParser) GetBlockRewards(address string) []usecase.BlockReward {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockRewards", address)
+	ret0, _ := ret[0].([]usecase.BlockReward)
+	return ret0
+}
+
+// GetBlockRewards indicates an expected call of GetBlockRewards.
+func (mr *MockParserMockRecorder) GetBlockRewards(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockRewards", reflect.TypeOf((*MockParser)(nil).GetBlockRewards), address)
+}
+
+// GetContractEvents mocks base method.
+func (m *MockParser) GetContractEvents(id string) []usecase.ContractEvent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContractEvents", id)
+	ret0, _ := ret[0].([]usecase.ContractEvent)
+	return ret0
+}
+
+// GetContractEvents indicates an expected call of GetContractEvents.
+func (mr *MockParserMockRecorder) GetContractEvents(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContractEvents", reflect.TypeOf((*MockParser)(nil).GetContractEvents), id)
+}
+
 // GetCurrentBlock mocks base method.
 func (m *MockParser) GetCurrentBlock() int {
 	m.ctrl.T.Helper()
@@ -53,6 +95,134 @@ func (mr *MockParserMockRecorder) GetCurrentBlock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentBlock", reflect.TypeOf((*MockParser)(nil).GetCurrentBlock))
 }
 
+// GetEventSubscriptions mocks base method.
+func (m *MockParser) GetEventSubscriptions() []usecase.EventSubscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventSubscriptions")
+	ret0, _ := ret[0].([]usecase.EventSubscription)
+	return ret0
+}
+
+// GetEventSubscriptions indicates an expected call of GetEventSubscriptions.
+func (mr *MockParserMockRecorder) GetEventSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventSubscriptions", reflect.TypeOf((*MockParser)(nil).GetEventSubscriptions))
+}
+
+// GetInternalTransactions mocks base method.
+func (m *MockParser) GetInternalTransactions(address string) []usecase.InternalTransaction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalTransactions", address)
+	ret0, _ := ret[0].([]usecase.InternalTransaction)
+	return ret0
+}
+
+// GetInternalTransactions indicates an expected call of GetInternalTransactions.
+func (mr *MockParserMockRecorder) GetInternalTransactions(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalTransactions", reflect.TypeOf((*MockParser)(nil).GetInternalTransactions), address)
+}
+
+// GetMetrics mocks base method.
+func (m *MockParser) GetMetrics() usecase.Metrics {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetrics")
+	ret0, _ := ret[0].(usecase.Metrics)
+	return ret0
+}
+
+// GetMetrics indicates an expected call of GetMetrics.
+func (mr *MockParserMockRecorder) GetMetrics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockParser)(nil).GetMetrics))
+}
+
+// GetNFTTransfers mocks base method.
+func (m *MockParser) GetNFTTransfers(address string) []usecase.NFTTransfer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNFTTransfers", address)
+	ret0, _ := ret[0].([]usecase.NFTTransfer)
+	return ret0
+}
+
+// GetNFTTransfers indicates an expected call of GetNFTTransfers.
+func (mr *MockParserMockRecorder) GetNFTTransfers(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNFTTransfers", reflect.TypeOf((*MockParser)(nil).GetNFTTransfers), address)
+}
+
+// GetPendingTransactions mocks base method.
+func (m *MockParser) GetPendingTransactions(address string) []usecase.PendingTransaction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransactions", address)
+	ret0, _ := ret[0].([]usecase.PendingTransaction)
+	return ret0
+}
+
+// GetPendingTransactions indicates an expected call of GetPendingTransactions.
+func (mr *MockParserMockRecorder) GetPendingTransactions(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransactions", reflect.TypeOf((*MockParser)(nil).GetPendingTransactions), address)
+}
+
+// GetSubscription mocks base method.
+func (m *MockParser) GetSubscription(address string) (usecase.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", address)
+	ret0, _ := ret[0].(usecase.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockParserMockRecorder) GetSubscription(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockParser)(nil).GetSubscription), address)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockParser) GetSubscriptions() []usecase.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions")
+	ret0, _ := ret[0].([]usecase.Subscription)
+	return ret0
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockParserMockRecorder) GetSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockParser)(nil).GetSubscriptions))
+}
+
+// GetTokenMetadata mocks base method.
+func (m *MockParser) GetTokenMetadata(contract string) (usecase.TokenMetadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenMetadata", contract)
+	ret0, _ := ret[0].(usecase.TokenMetadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokenMetadata indicates an expected call of GetTokenMetadata.
+func (mr *MockParserMockRecorder) GetTokenMetadata(contract any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenMetadata", reflect.TypeOf((*MockParser)(nil).GetTokenMetadata), contract)
+}
+
+// GetTokenTransfers mocks base method.
+func (m *MockParser) GetTokenTransfers(address string) []usecase.TokenTransfer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokenTransfers", address)
+	ret0, _ := ret[0].([]usecase.TokenTransfer)
+	return ret0
+}
+
+// GetTokenTransfers indicates an expected call of GetTokenTransfers.
+func (mr *MockParserMockRecorder) GetTokenTransfers(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenTransfers", reflect.TypeOf((*MockParser)(nil).GetTokenTransfers), address)
+}
+
 // GetTransactions mocks base method.
 func (m *MockParser) GetTransactions(address string) []usecase.Transaction {
 	m.ctrl.T.Helper()
@@ -67,6 +237,34 @@ func (mr *MockParserMockRecorder) GetTransactions(address any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockParser)(nil).GetTransactions), address)
 }
 
+// GetWithdrawals mocks base method.
+func (m *MockParser) GetWithdrawals(address string) []usecase.Withdrawal {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithdrawals", address)
+	ret0, _ := ret[0].([]usecase.Withdrawal)
+	return ret0
+}
+
+// GetWithdrawals indicates an expected call of GetWithdrawals.
+func (mr *MockParserMockRecorder) GetWithdrawals(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithdrawals", reflect.TypeOf((*MockParser)(nil).GetWithdrawals), address)
+}
+
+// PauseSubscription mocks base method.
+func (m *MockParser) PauseSubscription(address string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseSubscription", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseSubscription indicates an expected call of PauseSubscription.
+func (mr *MockParserMockRecorder) PauseSubscription(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseSubscription", reflect.TypeOf((*MockParser)(nil).PauseSubscription), address)
+}
+
 // PollForChanges mocks base method.
 func (m *MockParser) PollForChanges() {
 	m.ctrl.T.Helper()
@@ -79,16 +277,160 @@ func (mr *MockParserMockRecorder) PollForChanges() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollForChanges", reflect.TypeOf((*MockParser)(nil).PollForChanges))
 }
 
+// Reprocess mocks base method.
+func (m *MockParser) Reprocess(request usecase.ReprocessRequest) (usecase.BackfillJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reprocess", request)
+	ret0, _ := ret[0].(usecase.BackfillJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reprocess indicates an expected call of Reprocess.
+func (mr *MockParserMockRecorder) Reprocess(request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reprocess", reflect.TypeOf((*MockParser)(nil).Reprocess), request)
+}
+
+// ResolveAddress mocks base method.
+func (m *MockParser) ResolveAddress(nameOrAddress string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAddress", nameOrAddress)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ResolveAddress indicates an expected call of ResolveAddress.
+func (mr *MockParserMockRecorder) ResolveAddress(nameOrAddress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAddress", reflect.TypeOf((*MockParser)(nil).ResolveAddress), nameOrAddress)
+}
+
+// ResumeSubscription mocks base method.
+func (m *MockParser) ResumeSubscription(address string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSubscription", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeSubscription indicates an expected call of ResumeSubscription.
+func (mr *MockParserMockRecorder) ResumeSubscription(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSubscription", reflect.TypeOf((*MockParser)(nil).ResumeSubscription), address)
+}
+
+// StartEventBackfill mocks base method.
+func (m *MockParser) StartEventBackfill(request usecase.EventBackfillRequest) (usecase.BackfillJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartEventBackfill", request)
+	ret0, _ := ret[0].(usecase.BackfillJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartEventBackfill indicates an expected call of StartEventBackfill.
+func (mr *MockParserMockRecorder) StartEventBackfill(request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartEventBackfill", reflect.TypeOf((*MockParser)(nil).StartEventBackfill), request)
+}
+
+// StartReprocess mocks base method.
+func (m *MockParser) StartReprocess(request usecase.ReprocessRequest) (usecase.BackfillJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartReprocess", request)
+	ret0, _ := ret[0].(usecase.BackfillJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartReprocess indicates an expected call of StartReprocess.
+func (mr *MockParserMockRecorder) StartReprocess(request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartReprocess", reflect.TypeOf((*MockParser)(nil).StartReprocess), request)
+}
+
+// Status mocks base method.
+func (m *MockParser) Status() usecase.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status")
+	ret0, _ := ret[0].(usecase.Status)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockParserMockRecorder) Status() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockParser)(nil).Status))
+}
+
 // Subscribe mocks base method.
-func (m *MockParser) Subscribe(address string) bool {
+func (m *MockParser) Subscribe(subscription usecase.Subscription) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Subscribe", address)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "Subscribe", subscription)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Subscribe indicates an expected call of Subscribe.
-func (mr *MockParserMockRecorder) Subscribe(address any) *gomock.Call {
+func (mr *MockParserMockRecorder) Subscribe(subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockParser)(nil).Subscribe), subscription)
+}
+
+// SubscribeEvent mocks base method.
+func (m *MockParser) SubscribeEvent(subscription usecase.EventSubscription) (usecase.EventSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeEvent", subscription)
+	ret0, _ := ret[0].(usecase.EventSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeEvent indicates an expected call of SubscribeEvent.
+func (mr *MockParserMockRecorder) SubscribeEvent(subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeEvent", reflect.TypeOf((*MockParser)(nil).SubscribeEvent), subscription)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockParser) Unsubscribe(address string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockParserMockRecorder) Unsubscribe(address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockParser)(nil).Unsubscribe), address)
+}
+
+// UnsubscribeEvent mocks base method.
+func (m *MockParser) UnsubscribeEvent(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeEvent", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribeEvent indicates an expected call of UnsubscribeEvent.
+func (mr *MockParserMockRecorder) UnsubscribeEvent(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeEvent", reflect.TypeOf((*MockParser)(nil).UnsubscribeEvent), id)
+}
+
+// WatchPendingTransactions mocks base method.
+func (m *MockParser) WatchPendingTransactions() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WatchPendingTransactions")
+}
+
+// WatchPendingTransactions indicates an expected call of WatchPendingTransactions.
+func (mr *MockParserMockRecorder) WatchPendingTransactions() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockParser)(nil).Subscribe), address)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchPendingTransactions", reflect.TypeOf((*MockParser)(nil).WatchPendingTransactions))
 }
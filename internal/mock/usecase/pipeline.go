@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/usecase/pipeline.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./internal/domain/usecase/pipeline.go -destination=./internal/mock/usecase/pipeline.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	usecase "parse_server/internal/domain/usecase"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProcessor is a mock of Processor interface.
+type MockProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockProcessorMockRecorder
+}
+
+// MockProcessorMockRecorder is the mock recorder for MockProcessor.
+type MockProcessorMockRecorder struct {
+	mock *MockProcessor
+}
+
+// NewMockProcessor creates a new mock instance.
+func NewMockProcessor(ctrl *gomock.Controller) *MockProcessor {
+	mock := &MockProcessor{ctrl: ctrl}
+	mock.recorder = &MockProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProcessor) EXPECT() *MockProcessorMockRecorder {
+	return m.recorder
+}
+
+// Process mocks base method.
+func (m *MockProcessor) Process(item *usecase.PipelineItem) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Process", item)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Process indicates an expected call of Process.
+func (mr *MockProcessorMockRecorder) Process(item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockProcessor)(nil).Process), item)
+}
+
+// MockPipeline is a mock of Pipeline interface.
+type MockPipeline struct {
+	ctrl     *gomock.Controller
+	recorder *MockPipelineMockRecorder
+}
+
+// MockPipelineMockRecorder is the mock recorder for MockPipeline.
+type MockPipelineMockRecorder struct {
+	mock *MockPipeline
+}
+
+// NewMockPipeline creates a new mock instance.
+func NewMockPipeline(ctrl *gomock.Controller) *MockPipeline {
+	mock := &MockPipeline{ctrl: ctrl}
+	mock.recorder = &MockPipelineMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPipeline) EXPECT() *MockPipelineMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockPipeline) Run(item *usecase.PipelineItem) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", item)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockPipelineMockRecorder) Run(item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockPipeline)(nil).Run), item)
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// builtinABIs 內建的 ERC-20、ERC-721 與常見 DEX router ABI
+//
+//go:embed abis/*.json
+var builtinABIs embed.FS
+
+type ABIRegistryParam struct {
+	// Dir 額外載入的 JSON ABI 目錄，會覆蓋內建 ABI 中相同 selector 的函式
+	Dir string
+}
+
+// ABIRegistry 實現了 ABIRegistry interface
+type ABIRegistry struct {
+	mu      sync.RWMutex
+	methods map[[4]byte]abi.Entry
+	events  map[domain.Hash][]abi.Entry
+}
+
+func NewABIRegistry(param ABIRegistryParam) (*ABIRegistry, error) {
+	r := &ABIRegistry{
+		methods: make(map[[4]byte]abi.Entry),
+		events:  make(map[domain.Hash][]abi.Entry),
+	}
+
+	if err := r.loadFS(builtinABIs, "abis"); err != nil {
+		return nil, err
+	}
+	if param.Dir != "" {
+		if err := r.loadFS(os.DirFS(param.Dir), "."); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func MustABIRegistry(param ABIRegistryParam) repository.ABIRegistry {
+	r, err := NewABIRegistry(param)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// loadFS 依檔名順序載入目錄下所有 .json 檔案
+func (r *ABIRegistry) loadFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		entries, err := abi.Parse(data)
+		if err != nil {
+			return fmt.Errorf("load abi %s: %w", file, err)
+		}
+		r.Register(entries)
+	}
+	return nil
+}
+
+// Register 登錄 ABI 項目，後登錄的函式會覆蓋相同 selector 的舊項目
+// 事件以 topic 分組保留所有項目，例如 ERC-20 與 ERC-721 的 Transfer 僅 indexed 數量不同
+func (r *ABIRegistry) Register(entries []abi.Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, entry := range entries {
+		switch entry.Type {
+		case "function":
+			r.methods[entry.Selector()] = entry
+		case "event":
+			if entry.Anonymous {
+				continue
+			}
+			topic := entry.Topic()
+			r.events[topic] = append(r.events[topic], entry)
+		}
+	}
+}
+
+// DecodeInput 解碼交易 input，input 少於 4 bytes 時視為一般轉帳
+func (r *ABIRegistry) DecodeInput(input []byte) (*abi.Call, bool) {
+	if len(input) < 4 {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	entry, ok := r.methods[[4]byte(input[:4])]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	call, err := entry.DecodeInput(input)
+	if err != nil {
+		return nil, false
+	}
+	return call, true
+}
+
+// DecodeLog 解碼事件紀錄，相同 topic 有多個項目時從最後登錄的開始嘗試
+func (r *ABIRegistry) DecodeLog(log repository.Log) (*abi.Call, bool) {
+	if len(log.Topics) == 0 {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	entries := r.events[domain.Hash(strings.ToLower(log.Topics[0].String()))]
+	r.mu.RUnlock()
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if call, err := entries[i].DecodeLog(log.Topics, log.Data); err == nil {
+			return call, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"parse_server/internal/domain"
+	domainRepo "parse_server/internal/domain/repository"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func abiWords(words ...string) []byte {
+	var data []byte
+	for _, word := range words {
+		b, _ := hex.DecodeString(strings.Repeat("0", 64-len(word)) + word)
+		data = append(data, b...)
+	}
+	return data
+}
+
+func TestABIRegistry_DecodeInput(t *testing.T) {
+	dir := t.TempDir()
+	custom := `{"abi":[{"type":"function","name":"mint","inputs":[{"name":"to","type":"address"},{"name":"quantity","type":"uint256"}]}]}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "custom.json"), []byte(custom), 0o644))
+
+	registry, err := NewABIRegistry(ABIRegistryParam{Dir: dir})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "Built-in ERC-20 approve",
+			input:    append([]byte{0x09, 0x5e, 0xa7, 0xb3}, abiWords("0123", "64")...),
+			expected: "approve(spender: 0x0000000000000000000000000000000000000123, amount: 100)",
+			ok:       true,
+		},
+		{
+			name:     "Local ABI file",
+			input:    append([]byte{0x40, 0xc1, 0x0f, 0x19}, abiWords("0123", "2")...),
+			expected: "mint(to: 0x0000000000000000000000000000000000000123, quantity: 2)",
+			ok:       true,
+		},
+		{name: "Plain transfer", input: nil},
+		{name: "Unknown selector", input: []byte{0xde, 0xad, 0xbe, 0xef}},
+		{name: "Malformed arguments", input: []byte{0x09, 0x5e, 0xa7, 0xb3, 0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, ok := registry.DecodeInput(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, call.String())
+			}
+		})
+	}
+}
+
+func TestABIRegistry_DecodeLog(t *testing.T) {
+	registry, err := NewABIRegistry(ABIRegistryParam{})
+	assert.NoError(t, err)
+
+	from := domain.Hash("0x0000000000000000000000000000000000000000000000000000000000000123")
+	to := domain.Hash("0x0000000000000000000000000000000000000000000000000000000000000456")
+
+	tests := []struct {
+		name     string
+		log      domainRepo.Log
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "ERC-20 Transfer",
+			log:      domainRepo.Log{Topics: []domain.Hash{domain.TransferEventTopic, from, to}, Data: abiWords("3e8")},
+			expected: "Transfer(from: 0x0000000000000000000000000000000000000123, to: 0x0000000000000000000000000000000000000456, amount: 1000)",
+			ok:       true,
+		},
+		{
+			name: "ERC-721 Transfer",
+			log: domainRepo.Log{Topics: []domain.Hash{
+				domain.TransferEventTopic, from, to,
+				"0x0000000000000000000000000000000000000000000000000000000000000007",
+			}},
+			expected: "Transfer(from: 0x0000000000000000000000000000000000000123, to: 0x0000000000000000000000000000000000000456, tokenId: 7)",
+			ok:       true,
+		},
+		{name: "No topics", log: domainRepo.Log{}},
+		{name: "Unknown event", log: domainRepo.Log{Topics: []domain.Hash{from}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, ok := registry.DecodeLog(tt.log)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.expected, call.String())
+			}
+		})
+	}
+}
+
+func TestNewABIRegistry_InvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"name":"x"}`), 0o644))
+
+	_, err := NewABIRegistry(ABIRegistryParam{Dir: dir})
+	assert.Error(t, err)
+}
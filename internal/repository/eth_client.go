@@ -9,9 +9,22 @@ import (
 	"strings"
 )
 
-type ClientParam struct{}
+type ClientParam struct {
+	// URL JSON-RPC 節點的位址，未設定時使用預設的公開節點
+	URL string
+}
+
+type Client struct {
+	url string
+}
 
-type Client struct{}
+// Endpoint 返回節點的位址，實現了 EndpointNamer interface
+func (c Client) Endpoint() string {
+	if c.url == "" {
+		return domain.DefaultURL
+	}
+	return c.url
+}
 
 func (c Client) CallEthereum(method string, params []interface{}) ([]byte, error) {
 	rpcBody := map[string]interface{}{
@@ -25,7 +38,7 @@ func (c Client) CallEthereum(method string, params []interface{}) ([]byte, error
 		return []byte{}, err
 	}
 
-	resp, err := http.Post(domain.DefaultURL, "application/json", strings.NewReader(string(jsonBody)))
+	resp, err := http.Post(c.Endpoint(), "application/json", strings.NewReader(string(jsonBody)))
 	if err != nil {
 		return []byte{}, err
 	}
@@ -40,6 +53,6 @@ func (c Client) CallEthereum(method string, params []interface{}) ([]byte, error
 	return body, nil
 }
 
-func MustETHClient(_ ClientParam) repository.ETHClient {
-	return &Client{}
+func MustETHClient(param ClientParam) repository.ETHClient {
+	return &Client{url: param.URL}
 }
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"os"
+	"parse_server/internal/domain/repository"
+	"strconv"
+	"sync"
+)
+
+type FileLockParam struct {
+	// Path 鎖檔的路徑，同一主機上的實例需使用相同的路徑
+	Path string
+}
+
+// FileLockElector 以檔案鎖選出同一主機上的 leader
+// 鎖由作業系統持有，leader 結束時即自動釋放，其他實例下次競選即可接手
+type FileLockElector struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+func NewFileLockElector(param FileLockParam) *FileLockElector {
+	return &FileLockElector{path: param.Path}
+}
+
+func MustFileLockElector(param FileLockParam) repository.LeaderElector {
+	return NewFileLockElector(param)
+}
+
+func (l *FileLockElector) TryAcquire() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		return true, nil
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return false, err
+	}
+	locked, err := lockFile(file)
+	if err != nil || !locked {
+		file.Close()
+		return false, err
+	}
+
+	// 寫入 leader 的 pid 方便排查，寫入失敗不影響鎖的持有
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	l.file = file
+	return true, nil
+}
+
+func (l *FileLockElector) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	l.file = nil
+	return err
+}
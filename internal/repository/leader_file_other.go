@@ -0,0 +1,18 @@
+//go:build !unix
+
+package repository
+
+import (
+	"errors"
+	"os"
+)
+
+var ErrFileLockUnsupported = errors.New("file lock leader election is not supported on this platform")
+
+func lockFile(_ *os.File) (bool, error) {
+	return false, ErrFileLockUnsupported
+}
+
+func unlockFile(_ *os.File) error {
+	return ErrFileLockUnsupported
+}
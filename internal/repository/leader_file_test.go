@@ -0,0 +1,35 @@
+//go:build unix
+
+package repository
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileLockElector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parser.lock")
+	leader := NewFileLockElector(FileLockParam{Path: path})
+	follower := NewFileLockElector(FileLockParam{Path: path})
+
+	acquired, err := leader.TryAcquire()
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// 已持有時續約仍成功，其他實例無法取得
+	acquired, err = leader.TryAcquire()
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = follower.TryAcquire()
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	// leader 釋放後其他實例即可接手
+	assert.NoError(t, leader.Release())
+	acquired, err = follower.TryAcquire()
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.NoError(t, follower.Release())
+	assert.NoError(t, follower.Release())
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package repository
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile 以非阻塞的 flock 取得獨佔鎖，已被其他實例持有時返回 false
+func lockFile(file *os.File) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"time"
+)
+
+const (
+	defaultLeaseName = "parser"
+	defaultLeaseTTL  = 30 * time.Second
+)
+
+type LeaseParam struct {
+	// Storage 所有實例共用的 Storage，需實作 SharedStorage
+	Storage repository.Storage
+	// Name 租約名稱，預設為 parser
+	Name string
+	// Holder 實例的識別，需在所有實例間唯一
+	Holder string
+	// TTL 租約的有效期限，leader 需在期限內續約，預設為 30 秒
+	TTL time.Duration
+}
+
+// LeaseElector 以共用 Storage 中的租約選出 leader
+// leader 結束後租約在 TTL 內過期，其他實例即可取得
+// 租約只在共用的 Storage 中有意義，MemoryStorage 只存在於單一程序中，每個實例都會成為 leader
+type LeaseElector struct {
+	storage repository.Storage
+	name    string
+	holder  string
+	ttl     time.Duration
+}
+
+func NewLeaseElector(param LeaseParam) (*LeaseElector, error) {
+	if shared, ok := param.Storage.(repository.SharedStorage); !ok || !shared.Shared() {
+		return nil, domain.ErrStorageNotShared
+	}
+	l := &LeaseElector{
+		storage: param.Storage,
+		name:    param.Name,
+		holder:  param.Holder,
+		ttl:     param.TTL,
+	}
+	if l.name == "" {
+		l.name = defaultLeaseName
+	}
+	if l.ttl <= 0 {
+		l.ttl = defaultLeaseTTL
+	}
+	return l, nil
+}
+
+func MustLeaseElector(param LeaseParam) repository.LeaderElector {
+	l, err := NewLeaseElector(param)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+func (l *LeaseElector) TryAcquire() (bool, error) {
+	return l.storage.AcquireLease(l.name, l.holder, l.ttl), nil
+}
+
+func (l *LeaseElector) Release() error {
+	l.storage.ReleaseLease(l.name, l.holder)
+	return nil
+}
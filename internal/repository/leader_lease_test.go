@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/stretchr/testify/assert"
+	"parse_server/internal/domain"
+	"testing"
+	"time"
+)
+
+// sharedMemoryStorage 模擬所有實例共用的 Storage
+type sharedMemoryStorage struct {
+	*MemoryStorage
+}
+
+func (sharedMemoryStorage) Shared() bool {
+	return true
+}
+
+func TestLeaseElector(t *testing.T) {
+	storage := sharedMemoryStorage{NewMemoryStorage()}
+	leader, err := NewLeaseElector(LeaseParam{Storage: storage, Holder: "a", TTL: 50 * time.Millisecond})
+	assert.NoError(t, err)
+	follower, err := NewLeaseElector(LeaseParam{Storage: storage, Holder: "b", TTL: 50 * time.Millisecond})
+	assert.NoError(t, err)
+
+	acquired, err := leader.TryAcquire()
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, _ = follower.TryAcquire()
+	assert.False(t, acquired)
+
+	// leader 停止續約後，租約過期即可由其他實例接手
+	time.Sleep(60 * time.Millisecond)
+	acquired, _ = follower.TryAcquire()
+	assert.True(t, acquired)
+	acquired, _ = leader.TryAcquire()
+	assert.False(t, acquired)
+
+	// 主動釋放後立即可被取得
+	assert.NoError(t, follower.Release())
+	acquired, _ = leader.TryAcquire()
+	assert.True(t, acquired)
+}
+
+func TestLeaseElector_RequiresSharedStorage(t *testing.T) {
+	_, err := NewLeaseElector(LeaseParam{Storage: NewMemoryStorage(), Holder: "a"})
+	assert.ErrorIs(t, err, domain.ErrStorageNotShared)
+}
@@ -2,37 +2,353 @@ package repository
 
 import (
 	"parse_server/internal/domain/repository"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
 // MemoryStorage 實現了 Storage interface
+// Parser 的輪詢、待處理交易監聽與 HTTP handler 會同時存取，因此以讀寫鎖保護
 type MemoryStorage struct {
-	addresses    map[string]bool
-	transactions map[string][]repository.Transaction
+	mu             sync.RWMutex
+	subscriptions  map[string]repository.Subscription
+	transactions   map[string][]repository.Transaction
+	tokenTransfers map[string][]repository.TokenTransfer
+	nftTransfers   map[string][]repository.NFTTransfer
+	internalTxs    map[string][]repository.InternalTransaction
+	pendingTxs     map[string][]repository.PendingTransaction
+	withdrawals    map[string][]repository.Withdrawal
+	blockRewards   map[string][]repository.BlockReward
+	balances       map[string][]repository.BalanceSnapshot
+	leases         map[string]repository.Lease
+	tokens         map[string]repository.TokenMetadata
+	eventSubs      map[string]repository.EventSubscription
+	events         map[string][]repository.ContractEvent
+	lastProcessed  *int
 }
 
 func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
-		addresses:    make(map[string]bool),
-		transactions: make(map[string][]repository.Transaction),
+		subscriptions:  make(map[string]repository.Subscription),
+		transactions:   make(map[string][]repository.Transaction),
+		tokenTransfers: make(map[string][]repository.TokenTransfer),
+		nftTransfers:   make(map[string][]repository.NFTTransfer),
+		internalTxs:    make(map[string][]repository.InternalTransaction),
+		pendingTxs:     make(map[string][]repository.PendingTransaction),
+		withdrawals:    make(map[string][]repository.Withdrawal),
+		blockRewards:   make(map[string][]repository.BlockReward),
+		balances:       make(map[string][]repository.BalanceSnapshot),
+		leases:         make(map[string]repository.Lease),
+		tokens:         make(map[string]repository.TokenMetadata),
+		eventSubs:      make(map[string]repository.EventSubscription),
+		events:         make(map[string][]repository.ContractEvent),
 	}
 }
 
+// upsert 以 same 找出相同的紀錄並覆蓋，找不到時附加在最後，保留原本的順序
+func upsert[T any](items []T, item T, same func(T) bool) []T {
+	if i := slices.IndexFunc(items, same); i >= 0 {
+		items[i] = item
+		return items
+	}
+	return append(items, item)
+}
+
 func (m *MemoryStorage) SaveTransaction(address string, tx repository.Transaction) {
-	m.transactions[address] = append(m.transactions[address], tx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transactions[address] = upsert(m.transactions[address], tx, func(item repository.Transaction) bool {
+		return item.Hash == tx.Hash
+	})
 }
 
 func (m *MemoryStorage) GetTransactions(address string) []repository.Transaction {
-	return m.transactions[address]
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.transactions[address])
+}
+
+func (m *MemoryStorage) SaveTokenTransfer(address string, transfer repository.TokenTransfer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokenTransfers[address] = upsert(m.tokenTransfers[address], transfer, func(item repository.TokenTransfer) bool {
+		return item.TxHash == transfer.TxHash && item.LogIndex == transfer.LogIndex
+	})
+}
+
+func (m *MemoryStorage) GetTokenTransfers(address string) []repository.TokenTransfer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.tokenTransfers[address])
+}
+
+func (m *MemoryStorage) SaveNFTTransfer(address string, transfer repository.NFTTransfer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nftTransfers[address] = upsert(m.nftTransfers[address], transfer, func(item repository.NFTTransfer) bool {
+		return item.TxHash == transfer.TxHash && item.LogIndex == transfer.LogIndex && item.BatchIndex == transfer.BatchIndex
+	})
+}
+
+func (m *MemoryStorage) GetNFTTransfers(address string) []repository.NFTTransfer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.nftTransfers[address])
+}
+
+func (m *MemoryStorage) SaveInternalTransaction(address string, tx repository.InternalTransaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.internalTxs[address] = upsert(m.internalTxs[address], tx, func(item repository.InternalTransaction) bool {
+		return item.ParentTxHash == tx.ParentTxHash && item.Index == tx.Index
+	})
+}
+
+func (m *MemoryStorage) GetInternalTransactions(address string) []repository.InternalTransaction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.internalTxs[address])
+}
+
+func (m *MemoryStorage) SaveWithdrawal(address string, withdrawal repository.Withdrawal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.withdrawals[address] = upsert(m.withdrawals[address], withdrawal, func(item repository.Withdrawal) bool {
+		return item.Index == withdrawal.Index
+	})
+}
+
+func (m *MemoryStorage) GetWithdrawals(address string) []repository.Withdrawal {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.withdrawals[address])
+}
+
+func (m *MemoryStorage) SaveBlockReward(address string, reward repository.BlockReward) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blockRewards[address] = upsert(m.blockRewards[address], reward, func(item repository.BlockReward) bool {
+		return item.BlockNumber == reward.BlockNumber
+	})
+}
+
+func (m *MemoryStorage) GetBlockRewards(address string) []repository.BlockReward {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.blockRewards[address])
+}
+
+func (m *MemoryStorage) SaveBalanceSnapshot(address string, snapshot repository.BalanceSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.balances[address] = append(m.balances[address], snapshot)
+}
+
+func (m *MemoryStorage) GetBalanceSnapshots(address string) []repository.BalanceSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.balances[address])
+}
+
+func (m *MemoryStorage) GetLatestBalanceSnapshot(address string) (repository.BalanceSnapshot, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	snapshots := m.balances[address]
+	if len(snapshots) == 0 {
+		return repository.BalanceSnapshot{}, false
+	}
+	return snapshots[len(snapshots)-1], true
+}
+
+// SavePendingTransaction 以交易哈希值為鍵，已存在時更新其狀態
+func (m *MemoryStorage) SavePendingTransaction(address string, tx repository.PendingTransaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingTxs[address] = upsert(m.pendingTxs[address], tx, func(item repository.PendingTransaction) bool {
+		return item.Hash == tx.Hash
+	})
+}
+
+func (m *MemoryStorage) GetPendingTransactions(address string) []repository.PendingTransaction {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.pendingTxs[address])
+}
+
+func (m *MemoryStorage) SubscribeAddress(subscription repository.Subscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscriptions[subscription.Address] = subscription
+}
+
+func (m *MemoryStorage) UnsubscribeAddress(address string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.subscriptions[address]; !ok {
+		return false
+	}
+	delete(m.subscriptions, address)
+	return true
 }
 
-func (m *MemoryStorage) GetSubscribedAddresses() []string {
-	var addresses []string
-	for addr := range m.addresses {
-		addresses = append(addresses, addr)
+func (m *MemoryStorage) SetSubscriptionPaused(address string, paused bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	subscription, ok := m.subscriptions[address]
+	if !ok {
+		return false
 	}
-	return addresses
+	subscription.Paused = paused
+	m.subscriptions[address] = subscription
+	return true
+}
+
+func (m *MemoryStorage) GetSubscription(address string) (repository.Subscription, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subscription, ok := m.subscriptions[address]
+	return subscription, ok
+}
+
+// GetSubscriptions 依建立時間排序返回所有訂閱
+func (m *MemoryStorage) GetSubscriptions() []repository.Subscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subscriptions := make([]repository.Subscription, 0, len(m.subscriptions))
+	for _, subscription := range m.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	slices.SortFunc(subscriptions, func(a, b repository.Subscription) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Address, b.Address)
+	})
+	return subscriptions
+}
+
+// MoveSubscription 比對與寫入在同一個鎖內完成，避免覆蓋其他請求在比對後所做的修改
+func (m *MemoryStorage) MoveSubscription(from repository.Subscription, kept, target *repository.Subscription, moved repository.Subscription) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, ok := m.subscriptions[from.Address]; !ok || !reflect.DeepEqual(current, from) {
+		return false
+	}
+	existing, exists := m.subscriptions[moved.Address]
+	if exists != (target != nil) || exists && !reflect.DeepEqual(existing, *target) {
+		return false
+	}
+
+	if kept != nil {
+		m.subscriptions[from.Address] = *kept
+	} else {
+		delete(m.subscriptions, from.Address)
+	}
+	m.subscriptions[moved.Address] = moved
+	return true
+}
+
+func (m *MemoryStorage) AcquireLease(name, holder string, ttl time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if lease, ok := m.leases[name]; ok && lease.Holder != holder && now.Before(lease.ExpiresAt) {
+		return false
+	}
+	m.leases[name] = repository.Lease{Name: name, Holder: holder, ExpiresAt: now.Add(ttl)}
+	return true
+}
+
+func (m *MemoryStorage) ReleaseLease(name, holder string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if lease, ok := m.leases[name]; !ok || lease.Holder != holder {
+		return false
+	}
+	delete(m.leases, name)
+	return true
+}
+
+func (m *MemoryStorage) SaveLastProcessedBlock(blockNumber int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastProcessed = &blockNumber
+}
+
+func (m *MemoryStorage) GetLastProcessedBlock() (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.lastProcessed == nil {
+		return 0, false
+	}
+	return *m.lastProcessed, true
+}
+
+func (m *MemoryStorage) SaveTokenMetadata(metadata repository.TokenMetadata) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[metadata.Contract] = metadata
+}
+
+func (m *MemoryStorage) GetTokenMetadata(contract string) (repository.TokenMetadata, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	metadata, ok := m.tokens[contract]
+	return metadata, ok
+}
+
+func (m *MemoryStorage) SaveEventSubscription(subscription repository.EventSubscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.eventSubs[subscription.ID] = subscription
+}
+
+func (m *MemoryStorage) DeleteEventSubscription(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.eventSubs[id]; !ok {
+		return false
+	}
+	delete(m.eventSubs, id)
+	return true
+}
+
+func (m *MemoryStorage) GetEventSubscription(id string) (repository.EventSubscription, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subscription, ok := m.eventSubs[id]
+	return subscription, ok
+}
+
+// GetEventSubscriptions 依建立時間排序返回所有事件訂閱
+func (m *MemoryStorage) GetEventSubscriptions() []repository.EventSubscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subscriptions := make([]repository.EventSubscription, 0, len(m.eventSubs))
+	for _, subscription := range m.eventSubs {
+		subscriptions = append(subscriptions, subscription)
+	}
+	slices.SortFunc(subscriptions, func(a, b repository.EventSubscription) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return subscriptions
+}
+
+func (m *MemoryStorage) SaveContractEvent(subscriptionID string, event repository.ContractEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events[subscriptionID] = upsert(m.events[subscriptionID], event, func(item repository.ContractEvent) bool {
+		return item.TxHash == event.TxHash && item.LogIndex == event.LogIndex
+	})
 }
 
-func (m *MemoryStorage) SubscribeAddress(address string) {
-	m.addresses[address] = true
+func (m *MemoryStorage) GetContractEvents(subscriptionID string) []repository.ContractEvent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.events[subscriptionID])
 }
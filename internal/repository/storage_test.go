@@ -2,8 +2,10 @@ package repository
 
 import (
 	"github.com/stretchr/testify/assert"
+	"parse_server/internal/domain"
 	domainRepo "parse_server/internal/domain/repository"
 	"testing"
+	"time"
 )
 
 func TestMemoryStorage_SaveAndGetTransactions(t *testing.T) {
@@ -18,11 +20,12 @@ func TestMemoryStorage_SaveAndGetTransactions(t *testing.T) {
 			address: "0x123",
 			transactions: []domainRepo.Transaction{
 				{
+					Hash:        "0xtx1",
 					BlockHash:   "0xhash1",
 					BlockNumber: "100",
 					From:        "0xfrom1",
 					To:          "0xto1",
-					Value:       "0x10",
+					Value:       domain.BigIntFromUint64(0x10),
 				},
 			},
 			expectedCount: 1,
@@ -32,18 +35,20 @@ func TestMemoryStorage_SaveAndGetTransactions(t *testing.T) {
 			address: "0x456",
 			transactions: []domainRepo.Transaction{
 				{
+					Hash:        "0xtx2",
 					BlockHash:   "0xhash2",
 					BlockNumber: "101",
 					From:        "0xfrom2",
 					To:          "0xto2",
-					Value:       "0x20",
+					Value:       domain.BigIntFromUint64(0x20),
 				},
 				{
+					Hash:        "0xtx3",
 					BlockHash:   "0xhash3",
 					BlockNumber: "102",
 					From:        "0xfrom3",
 					To:          "0xto3",
-					Value:       "0x30",
+					Value:       domain.BigIntFromUint64(0x30),
 				},
 			},
 			expectedCount: 2,
@@ -68,6 +73,232 @@ func TestMemoryStorage_SaveAndGetTransactions(t *testing.T) {
 	}
 }
 
+func TestMemoryStorage_SaveAndGetTokenTransfers(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	transfer := domainRepo.TokenTransfer{
+		TxHash:      "0xtx1",
+		BlockHash:   "0xhash1",
+		BlockNumber: "100",
+		LogIndex:    "0x0",
+		Contract:    "0xtoken",
+		From:        "0xfrom1",
+		To:          "0x123",
+		Amount:      domain.BigIntFromUint64(0x10),
+	}
+	storage.SaveTokenTransfer("0x123", transfer)
+
+	assert.Equal(t, []domainRepo.TokenTransfer{transfer}, storage.GetTokenTransfers("0x123"))
+	assert.Empty(t, storage.GetTokenTransfers("0x456"))
+}
+
+func TestMemoryStorage_SaveAndGetNFTTransfers(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	transfer := domainRepo.NFTTransfer{
+		TxHash:   "0xtx1",
+		Standard: "ERC-721",
+		Contract: "0xnft",
+		From:     "0xfrom1",
+		To:       "0x123",
+		TokenID:  domain.BigIntFromUint64(0x1),
+		Amount:   domain.BigIntFromUint64(0x1),
+	}
+	storage.SaveNFTTransfer("0x123", transfer)
+
+	assert.Equal(t, []domainRepo.NFTTransfer{transfer}, storage.GetNFTTransfers("0x123"))
+	assert.Empty(t, storage.GetNFTTransfers("0x456"))
+}
+
+func TestMemoryStorage_SaveAndGetInternalTransactions(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	tx := domainRepo.InternalTransaction{
+		ParentTxHash: "0xtx1",
+		BlockNumber:  "100",
+		Type:         "CALL",
+		From:         "0xmultisig",
+		To:           "0x123",
+		Value:        domain.BigIntFromUint64(0x10),
+		Depth:        1,
+	}
+	storage.SaveInternalTransaction("0x123", tx)
+
+	assert.Equal(t, []domainRepo.InternalTransaction{tx}, storage.GetInternalTransactions("0x123"))
+	assert.Empty(t, storage.GetInternalTransactions("0x456"))
+}
+
+func TestMemoryStorage_SaveUpserts(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	// 重新處理區塊時相同的紀錄會覆蓋原本的紀錄並保留順序
+	storage.SaveTransaction("0x123", domainRepo.Transaction{Hash: "0xtx1", Value: domain.BigIntFromUint64(1)})
+	storage.SaveTransaction("0x123", domainRepo.Transaction{Hash: "0xtx2", Value: domain.BigIntFromUint64(2)})
+	storage.SaveTransaction("0x123", domainRepo.Transaction{Hash: "0xtx1", Value: domain.BigIntFromUint64(1), Fee: domain.BigIntFromUint64(21)})
+	transactions := storage.GetTransactions("0x123")
+	assert.Len(t, transactions, 2)
+	assert.Equal(t, "0xtx1", transactions[0].Hash)
+	assert.Equal(t, "21", transactions[0].Fee.String())
+
+	storage.SaveTokenTransfer("0x123", domainRepo.TokenTransfer{TxHash: "0xtx1", LogIndex: "0x0"})
+	storage.SaveTokenTransfer("0x123", domainRepo.TokenTransfer{TxHash: "0xtx1", LogIndex: "0x1"})
+	storage.SaveTokenTransfer("0x123", domainRepo.TokenTransfer{TxHash: "0xtx1", LogIndex: "0x0"})
+	assert.Len(t, storage.GetTokenTransfers("0x123"), 2)
+
+	// TransferBatch 拆成的多筆紀錄有相同的 LogIndex，以 BatchIndex 區分，同一批次重複的 TokenID 各自保存
+	storage.SaveNFTTransfer("0x123", domainRepo.NFTTransfer{TxHash: "0xtx1", LogIndex: "0x2", BatchIndex: 0, TokenID: domain.BigIntFromUint64(1)})
+	storage.SaveNFTTransfer("0x123", domainRepo.NFTTransfer{TxHash: "0xtx1", LogIndex: "0x2", BatchIndex: 1, TokenID: domain.BigIntFromUint64(1)})
+	storage.SaveNFTTransfer("0x123", domainRepo.NFTTransfer{TxHash: "0xtx1", LogIndex: "0x2", BatchIndex: 2, TokenID: domain.BigIntFromUint64(2)})
+	storage.SaveNFTTransfer("0x123", domainRepo.NFTTransfer{TxHash: "0xtx1", LogIndex: "0x2", BatchIndex: 1, TokenID: domain.BigIntFromUint64(1)})
+	assert.Len(t, storage.GetNFTTransfers("0x123"), 3)
+
+	storage.SaveInternalTransaction("0x123", domainRepo.InternalTransaction{ParentTxHash: "0xtx1", Index: 0})
+	storage.SaveInternalTransaction("0x123", domainRepo.InternalTransaction{ParentTxHash: "0xtx1", Index: 1})
+	storage.SaveInternalTransaction("0x123", domainRepo.InternalTransaction{ParentTxHash: "0xtx1", Index: 1})
+	assert.Len(t, storage.GetInternalTransactions("0x123"), 2)
+
+	storage.SaveWithdrawal("0x123", domainRepo.Withdrawal{Index: "0x1"})
+	storage.SaveWithdrawal("0x123", domainRepo.Withdrawal{Index: "0x1"})
+	assert.Len(t, storage.GetWithdrawals("0x123"), 1)
+
+	storage.SaveBlockReward("0x123", domainRepo.BlockReward{BlockNumber: "0x10"})
+	storage.SaveBlockReward("0x123", domainRepo.BlockReward{BlockNumber: "0x10"})
+	assert.Len(t, storage.GetBlockRewards("0x123"), 1)
+}
+
+func TestMemoryStorage_SavePendingTransactionUpdatesStatus(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	tx := domainRepo.PendingTransaction{Hash: "0xtx1", From: "0x123", Nonce: "0x1", Status: "pending"}
+	storage.SavePendingTransaction("0x123", tx)
+
+	tx.Status = "mined"
+	tx.BlockNumber = "0x10"
+	storage.SavePendingTransaction("0x123", tx)
+
+	assert.Equal(t, []domainRepo.PendingTransaction{tx}, storage.GetPendingTransactions("0x123"))
+}
+
+func TestMemoryStorage_SaveAndGetWithdrawals(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	withdrawal := domainRepo.Withdrawal{
+		BlockNumber:    "0x10",
+		Index:          "0x1",
+		ValidatorIndex: "0x2",
+		Address:        "0x123",
+		Amount:         domain.BigIntFromUint64(1_000_000_000),
+	}
+	storage.SaveWithdrawal("0x123", withdrawal)
+
+	assert.Equal(t, []domainRepo.Withdrawal{withdrawal}, storage.GetWithdrawals("0x123"))
+	assert.Empty(t, storage.GetWithdrawals("0x456"))
+}
+
+func TestMemoryStorage_SaveAndGetBlockRewards(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	reward := domainRepo.BlockReward{
+		BlockNumber:      "0x10",
+		FeeRecipient:     "0x123",
+		PriorityFees:     domain.BigIntFromUint64(21000),
+		TransactionCount: 1,
+	}
+	storage.SaveBlockReward("0x123", reward)
+
+	assert.Equal(t, []domainRepo.BlockReward{reward}, storage.GetBlockRewards("0x123"))
+	assert.Empty(t, storage.GetBlockRewards("0x456"))
+}
+
+func TestMemoryStorage_SaveAndGetBalanceSnapshots(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	_, ok := storage.GetLatestBalanceSnapshot("0x123")
+	assert.False(t, ok)
+
+	baseline := domainRepo.BalanceSnapshot{
+		BlockNumber: "0x10",
+		Balance:     domain.BigIntFromUint64(100),
+		Expected:    domain.BigIntFromUint64(100),
+		Baseline:    true,
+	}
+	snapshot := domainRepo.BalanceSnapshot{
+		BlockNumber: "0x11",
+		Balance:     domain.BigIntFromUint64(90),
+		Expected:    domain.BigIntFromUint64(90),
+		Change:      domain.BigIntFromUint64(0).Sub(domain.BigIntFromUint64(10)),
+	}
+	storage.SaveBalanceSnapshot("0x123", baseline)
+	storage.SaveBalanceSnapshot("0x123", snapshot)
+
+	assert.Equal(t, []domainRepo.BalanceSnapshot{baseline, snapshot}, storage.GetBalanceSnapshots("0x123"))
+	latest, ok := storage.GetLatestBalanceSnapshot("0x123")
+	assert.True(t, ok)
+	assert.Equal(t, snapshot, latest)
+	assert.Empty(t, storage.GetBalanceSnapshots("0x456"))
+}
+
+func TestMemoryStorage_Lease(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	assert.True(t, storage.AcquireLease("parser", "a", time.Minute))
+	assert.True(t, storage.AcquireLease("parser", "a", time.Minute))
+	assert.False(t, storage.AcquireLease("parser", "b", time.Minute))
+	assert.True(t, storage.AcquireLease("other", "b", time.Minute))
+
+	assert.False(t, storage.ReleaseLease("parser", "b"))
+	assert.True(t, storage.ReleaseLease("parser", "a"))
+	assert.False(t, storage.ReleaseLease("parser", "a"))
+	assert.True(t, storage.AcquireLease("parser", "b", time.Minute))
+
+	// 過期的租約可被其他持有者取得
+	assert.True(t, storage.AcquireLease("expired", "a", -time.Second))
+	assert.True(t, storage.AcquireLease("expired", "b", time.Minute))
+}
+
+func TestMemoryStorage_TokenMetadata(t *testing.T) {
+	storage := NewMemoryStorage()
+	contract := "0x000000000000000000000000000000000000ee20"
+
+	_, ok := storage.GetTokenMetadata(contract)
+	assert.False(t, ok)
+
+	decimals := 6
+	storage.SaveTokenMetadata(domainRepo.TokenMetadata{Contract: contract, Symbol: "USDC"})
+	storage.SaveTokenMetadata(domainRepo.TokenMetadata{Contract: contract, Symbol: "USDC", Decimals: &decimals})
+	metadata, ok := storage.GetTokenMetadata(contract)
+	assert.True(t, ok)
+	assert.Equal(t, domainRepo.TokenMetadata{Contract: contract, Symbol: "USDC", Decimals: &decimals}, metadata)
+}
+
+func TestMemoryStorage_EventSubscriptions(t *testing.T) {
+	storage := NewMemoryStorage()
+	now := time.Now()
+
+	storage.SaveEventSubscription(domainRepo.EventSubscription{ID: "evt-b", CreatedAt: now})
+	storage.SaveEventSubscription(domainRepo.EventSubscription{ID: "evt-a", CreatedAt: now.Add(-time.Minute)})
+	storage.SaveEventSubscription(domainRepo.EventSubscription{ID: "evt-b", CreatedAt: now, Label: "renamed"})
+
+	subscriptions := storage.GetEventSubscriptions()
+	assert.Len(t, subscriptions, 2)
+	assert.Equal(t, "evt-a", subscriptions[0].ID)
+	assert.Equal(t, "renamed", subscriptions[1].Label)
+
+	// 事件以交易哈希值與 LogIndex upsert，取消訂閱後仍保留
+	storage.SaveContractEvent("evt-a", domainRepo.ContractEvent{TxHash: "0x1", LogIndex: "0x1"})
+	storage.SaveContractEvent("evt-a", domainRepo.ContractEvent{TxHash: "0x1", LogIndex: "0x2"})
+	storage.SaveContractEvent("evt-a", domainRepo.ContractEvent{TxHash: "0x1", LogIndex: "0x1", Contract: "0xc"})
+	assert.True(t, storage.DeleteEventSubscription("evt-a"))
+	assert.False(t, storage.DeleteEventSubscription("evt-a"))
+	_, ok := storage.GetEventSubscription("evt-a")
+	assert.False(t, ok)
+
+	events := storage.GetContractEvents("evt-a")
+	assert.Len(t, events, 2)
+	assert.Equal(t, "0xc", events[0].Contract)
+	assert.Empty(t, storage.GetContractEvents("evt-b"))
+}
+
 func TestMemoryStorage_SubscribeAddress(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -93,11 +324,14 @@ func TestMemoryStorage_SubscribeAddress(t *testing.T) {
 
 			// 訂閱地址
 			for _, addr := range tt.addresses {
-				storage.SubscribeAddress(addr)
+				storage.SubscribeAddress(domainRepo.Subscription{Address: addr})
 			}
 
 			// 獲取訂閱的地址
-			result := storage.GetSubscribedAddresses()
+			var result []string
+			for _, subscription := range storage.GetSubscriptions() {
+				result = append(result, subscription.Address)
+			}
 
 			// 檢查結果
 			assert.ElementsMatch(t, tt.expectedResult, result)
@@ -105,6 +339,36 @@ func TestMemoryStorage_SubscribeAddress(t *testing.T) {
 	}
 }
 
+func TestMemoryStorage_SubscriptionLifecycle(t *testing.T) {
+	storage := NewMemoryStorage()
+
+	_, ok := storage.GetSubscription("0x123")
+	assert.False(t, ok)
+	assert.False(t, storage.SetSubscriptionPaused("0x123", true))
+	assert.False(t, storage.UnsubscribeAddress("0x123"))
+
+	storage.SubscribeAddress(domainRepo.Subscription{Address: "0x123", Filter: domainRepo.SubscriptionFilter{Direction: domain.DirectionIncoming}})
+	// 重複訂閱時以新的內容覆蓋
+	subscription := domainRepo.Subscription{
+		Address: "0x123",
+		Filter:  domainRepo.SubscriptionFilter{Direction: domain.DirectionOutgoing, ExcludeFailed: true},
+		Label:   "treasury",
+		Owner:   "ops",
+	}
+	storage.SubscribeAddress(subscription)
+
+	result, ok := storage.GetSubscription("0x123")
+	assert.True(t, ok)
+	assert.Equal(t, subscription, result)
+
+	assert.True(t, storage.SetSubscriptionPaused("0x123", true))
+	result, _ = storage.GetSubscription("0x123")
+	assert.True(t, result.Paused)
+
+	assert.True(t, storage.UnsubscribeAddress("0x123"))
+	assert.Empty(t, storage.GetSubscriptions())
+}
+
 func TestMemoryStorage_GetTransactionsForNonExistentAddress(t *testing.T) {
 	storage := NewMemoryStorage()
 
@@ -114,3 +378,43 @@ func TestMemoryStorage_GetTransactionsForNonExistentAddress(t *testing.T) {
 	// 應該返回空的交易列表
 	assert.Empty(t, result)
 }
+
+func TestMemoryStorage_MoveSubscription(t *testing.T) {
+	storage := NewMemoryStorage()
+	from := domainRepo.Subscription{Address: "0x123", ENSName: "alice.eth", Label: "alice"}
+	moved := domainRepo.Subscription{Address: "0x456", ENSName: "alice.eth", Label: "alice"}
+
+	// 原地址的訂閱已取消時不重新建立
+	assert.False(t, storage.MoveSubscription(from, nil, nil, moved))
+	assert.Empty(t, storage.GetSubscriptions())
+
+	// 原地址的訂閱已被修改時不覆蓋
+	paused := from
+	paused.Paused = true
+	storage.SubscribeAddress(paused)
+	assert.False(t, storage.MoveSubscription(from, nil, nil, moved))
+	_, ok := storage.GetSubscription("0x456")
+	assert.False(t, ok)
+
+	// 新地址的訂閱與預期不同時不覆蓋
+	storage.SubscribeAddress(from)
+	storage.SubscribeAddress(domainRepo.Subscription{Address: "0x456", Label: "bob"})
+	assert.False(t, storage.MoveSubscription(from, nil, nil, moved))
+	target, _ := storage.GetSubscription("0x456")
+	assert.Equal(t, "bob", target.Label)
+
+	// 比對成功時以 kept 取代原地址的訂閱並寫入新地址
+	kept := domainRepo.Subscription{Address: "0x123", Direct: true, Label: "alice"}
+	assert.True(t, storage.MoveSubscription(from, &kept, &target, moved))
+	subscription, _ := storage.GetSubscription("0x123")
+	assert.Equal(t, kept, subscription)
+	subscription, _ = storage.GetSubscription("0x456")
+	assert.Equal(t, moved, subscription)
+
+	// kept 為 nil 時移除原地址的訂閱
+	assert.True(t, storage.MoveSubscription(moved, nil, &kept, from))
+	_, ok = storage.GetSubscription("0x456")
+	assert.False(t, ok)
+	subscription, _ = storage.GetSubscription("0x123")
+	assert.Equal(t, from, subscription)
+}
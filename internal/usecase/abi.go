@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+)
+
+type ABIDecodeParam struct {
+	// Registry 解碼合約呼叫的 ABI，為 nil 時不解碼
+	Registry repository.ABIRegistry
+}
+
+// ABIDecodeProcessor 以 ABI registry 解碼交易呼叫的函式的 enricher，實現了 Processor interface
+// 收據中的事件需要原始的 log，在取得收據時解碼
+type ABIDecodeProcessor struct {
+	registry repository.ABIRegistry
+}
+
+func NewABIDecodeProcessor(param ABIDecodeParam) *ABIDecodeProcessor {
+	return &ABIDecodeProcessor{registry: param.Registry}
+}
+
+// Process 將交易的 input 解碼為 Method，前面的階段已設定 Method 或無法解碼時不修改，其他紀錄不處理
+func (a *ABIDecodeProcessor) Process(item *usecase.PipelineItem) (bool, error) {
+	tx := item.Transaction
+	if tx == nil || tx.Method != nil || a.registry == nil {
+		return true, nil
+	}
+	if call, ok := a.registry.DecodeInput(tx.Input); ok {
+		tx.Method = call
+	}
+	return true, nil
+}
+
+// decodeEvents 以 ABI registry 解碼交易收據中的事件，無法解碼的事件會被略過
+func (p *EthereumParser) decodeEvents(logs []repository.Log) []abi.Call {
+	if p.abiRegistry == nil {
+		return nil
+	}
+	var events []abi.Call
+	for _, log := range logs {
+		if call, ok := p.abiRegistry.DecodeLog(log); ok {
+			events = append(events, *call)
+		}
+	}
+	return events
+}
+
+func toUsecaseTransaction(tx repository.Transaction) usecase.Transaction {
+	return usecase.Transaction{
+		Hash:        tx.Hash,
+		BlockHash:   tx.BlockHash,
+		BlockNumber: tx.BlockNumber,
+		From:        tx.From,
+		To:          tx.To,
+		Value:       tx.Value,
+		GasPrice:    tx.GasPrice,
+		GasUsed:     tx.GasUsed,
+		Fee:         tx.Fee,
+		Nonce:       tx.Nonce,
+		Input:       tx.Input,
+		Failed:      tx.Failed,
+
+		Type:                 tx.Type,
+		MaxFeePerGas:         tx.MaxFeePerGas,
+		MaxPriorityFeePerGas: tx.MaxPriorityFeePerGas,
+		MaxFeePerBlobGas:     tx.MaxFeePerBlobGas,
+		BlobVersionedHashes:  tx.BlobVersionedHashes,
+		BlobGasUsed:          tx.BlobGasUsed,
+		BlobGasPrice:         tx.BlobGasPrice,
+		BlobFee:              tx.BlobFee,
+		AccessList:           toUsecaseAccessList(tx.AccessList),
+		YParity:              tx.YParity,
+		AuthorizationList:    toUsecaseAuthorizations(tx.AuthorizationList),
+		L1Fee:                tx.L1Fee,
+		SourceHash:           tx.SourceHash,
+		Mint:                 tx.Mint,
+		Method:               tx.Method,
+		Events:               tx.Events,
+		Labels:               tx.Labels,
+	}
+}
+
+func toRepositoryTransaction(tx usecase.Transaction) repository.Transaction {
+	return repository.Transaction{
+		Hash:        tx.Hash,
+		BlockHash:   tx.BlockHash,
+		BlockNumber: tx.BlockNumber,
+		From:        tx.From,
+		To:          tx.To,
+		Value:       tx.Value,
+		GasPrice:    tx.GasPrice,
+		GasUsed:     tx.GasUsed,
+		Fee:         tx.Fee,
+		Nonce:       tx.Nonce,
+		Input:       tx.Input,
+		Failed:      tx.Failed,
+
+		Type:                 tx.Type,
+		MaxFeePerGas:         tx.MaxFeePerGas,
+		MaxPriorityFeePerGas: tx.MaxPriorityFeePerGas,
+		MaxFeePerBlobGas:     tx.MaxFeePerBlobGas,
+		BlobVersionedHashes:  tx.BlobVersionedHashes,
+		BlobGasUsed:          tx.BlobGasUsed,
+		BlobGasPrice:         tx.BlobGasPrice,
+		BlobFee:              tx.BlobFee,
+		AccessList:           toRepositoryAccessList(tx.AccessList),
+		YParity:              tx.YParity,
+		AuthorizationList:    toRepositoryAuthorizations(tx.AuthorizationList),
+		L1Fee:                tx.L1Fee,
+		SourceHash:           tx.SourceHash,
+		Mint:                 tx.Mint,
+		Method:               tx.Method,
+		Events:               tx.Events,
+		Labels:               tx.Labels,
+	}
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func TestFetchTransactionsForAddress_DecodeContractCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+	mockRegistry := repoMock.NewMockABIRegistry(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+		ABIRegistry:  mockRegistry,
+	})
+
+	address := "0x0000000000000000000000000000000000000123"
+	approve := &abi.Call{Name: "approve", Signature: "approve(address,uint256)", Params: []abi.Param{
+		{Name: "spender", Type: "address", Value: "0x0000000000000000000000000000000000000456"},
+		{Name: "amount", Type: "uint256", Value: domain.BigIntFromUint64(100)},
+	}}
+	approval := &abi.Call{Name: "Approval", Signature: "Approval(address,address,uint256)"}
+
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"result": {
+			"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+			"transactions": [
+				{"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "0x0000000000000000000000000000000000000123", "to": "0x000000000000000000000000000000000000ee20", "value": "0x0", "gasPrice": "0x5", "input": "0x095ea7b3"}
+			]
+		}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", gomock.Any()).Return(json.RawMessage(`{
+		"result": {
+			"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111",
+			"gasUsed": "0x5208",
+			"status": "0x1",
+			"logs": [
+				{"address": "0x000000000000000000000000000000000000ee20", "topics": ["0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"], "data": "0x"},
+				{"address": "0x000000000000000000000000000000000000ee20", "topics": ["0x1111111111111111111111111111111111111111111111111111111111111111"], "data": "0x"}
+			]
+		}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil)
+
+	mockRegistry.EXPECT().DecodeInput(domain.Bytes{0x09, 0x5e, 0xa7, 0xb3}).Return(approve, true)
+	mockRegistry.EXPECT().DecodeLog(gomock.Any()).DoAndReturn(func(log repository.Log) (*abi.Call, bool) {
+		if log.Topics[0] == "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925" {
+			return approval, true
+		}
+		return nil, false
+	}).Times(2)
+
+	mockStorage.EXPECT().GetPendingTransactions(address).Return(nil)
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{Address: address}, true)
+	var saved repository.Transaction
+	mockStorage.EXPECT().SaveTransaction(address, gomock.Any()).Do(func(_ string, tx repository.Transaction) {
+		saved = tx
+	})
+	var notified usecase.Transaction
+	mockNotification.EXPECT().Notify(address, gomock.Any()).Do(func(_ string, tx usecase.Transaction) {
+		notified = tx
+	})
+
+	parser.(*EthereumParser).FetchTransactionsForAddress(address)
+
+	// 無法解碼的事件會被略過
+	assert.Equal(t, approve, saved.Method)
+	assert.Equal(t, []abi.Call{*approval}, saved.Events)
+	assert.Equal(t, "approve(spender: 0x0000000000000000000000000000000000000456, amount: 100)", notified.Method.String())
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"time"
+)
+
+// balanceChange 累計區塊內觀察到的活動對地址 ETH 餘額的影響
+type balanceChange struct {
+	address string
+	amount  domain.BigInt
+	// active 區塊內有與該地址相關的活動
+	active bool
+	// incomplete 收據或內部交易無法取得，amount 可能不完整
+	incomplete bool
+}
+
+func (c *balanceChange) involves(from, to string) bool {
+	return from == c.address || to == c.address
+}
+
+// transfer 記錄 from 轉給 to 的金額，from 為空代表新發行的 ETH，例如提款與手續費收入
+func (c *balanceChange) transfer(from, to string, value domain.BigInt) {
+	if !c.involves(from, to) {
+		return
+	}
+	c.active = true
+	if from == c.address {
+		c.amount = c.amount.Sub(value)
+	}
+	if to == c.address {
+		c.amount = c.amount.Add(value)
+	}
+}
+
+// addTransaction 記錄交易的影響：發送者支付手續費，交易成功時才轉移金額
+// OP Stack 的 deposit 交易會先將 Mint 鑄造給發送者，即使交易失敗也不會回滾
+func (c *balanceChange) addTransaction(tx repository.Transaction) {
+	if !c.involves(tx.From, tx.To) {
+		return
+	}
+	c.active = true
+	if tx.From == c.address {
+		c.amount = c.amount.Sub(tx.Fee).Add(tx.Mint)
+	}
+	if !tx.Failed {
+		c.transfer(tx.From, tx.To, tx.Value)
+	}
+}
+
+// fetchBalance 以 eth_getBalance 取得地址在指定區塊結束時的餘額
+func (p *EthereumParser) fetchBalance(address, blockNumber string) (domain.BigInt, error) {
+	result, err := p.ethClient.CallEthereum("eth_getBalance", []any{address, blockNumber})
+	if err != nil {
+		return domain.BigInt{}, err
+	}
+
+	var rpcResponse repository.BalanceResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return domain.BigInt{}, err
+	}
+	if rpcResponse.Error != nil {
+		return domain.BigInt{}, rpcResponse.Error
+	}
+
+	return rpcResponse.Result.BigInt, nil
+}
+
+// recordBalanceBaseline 記錄地址目前的餘額，作為之後對帳的基準
+func (p *EthereumParser) recordBalanceBaseline(address string) {
+	blockNumber := p.currentBlock
+	if blockNumber == 0 {
+		var err error
+		blockNumber, err = p.fetchBlockNumber()
+		if err != nil {
+			p.reportError("Error fetching block number for balance baseline:", err)
+			return
+		}
+	}
+
+	tag := fmt.Sprintf("0x%x", blockNumber)
+	balance, err := p.fetchBalance(address, tag)
+	if err != nil {
+		p.reportError("Error fetching balance:", err)
+		return
+	}
+
+	p.storage.SaveBalanceSnapshot(address, repository.BalanceSnapshot{
+		BlockNumber: tag,
+		Balance:     balance,
+		Expected:    balance,
+		Baseline:    true,
+		CreatedAt:   time.Now(),
+	})
+}
+
+// reconcileBalance 以前一次快照的餘額加上區塊內的變化推算預期餘額，與實際餘額不符時通知
+// 前一次快照已涵蓋此區塊時不重複計算；區塊活動不完整時只記錄新的基準，避免誤報
+func (p *EthereumParser) reconcileBalance(address, blockNumber string, change balanceChange) {
+	previous, hasPrevious := p.storage.GetLatestBalanceSnapshot(address)
+	if hasPrevious && !isLaterBlock(blockNumber, previous.BlockNumber) {
+		return
+	}
+
+	balance, err := p.fetchBalance(address, blockNumber)
+	if err != nil {
+		p.reportError("Error fetching balance:", err)
+		return
+	}
+
+	snapshot := repository.BalanceSnapshot{
+		BlockNumber: blockNumber,
+		Balance:     balance,
+		Expected:    balance,
+		Change:      change.amount,
+		Baseline:    true,
+		CreatedAt:   time.Now(),
+	}
+	if hasPrevious && !change.incomplete {
+		snapshot.Expected = previous.Balance.Add(change.amount)
+		snapshot.Discrepancy = balance.Sub(snapshot.Expected)
+		snapshot.Baseline = false
+	}
+
+	p.storage.SaveBalanceSnapshot(address, snapshot)
+	if !snapshot.Discrepancy.IsZero() {
+		p.notification.NotifyBalanceDiscrepancy(address, toUsecaseBalanceSnapshot(snapshot))
+	}
+}
+
+// isLaterBlock 比較兩個十六進位區塊號，無法解析時視為較新的區塊
+func isLaterBlock(blockNumber, other string) bool {
+	n, err := domain.ParseHexBigInt(blockNumber)
+	if err != nil {
+		return true
+	}
+	o, err := domain.ParseHexBigInt(other)
+	if err != nil {
+		return true
+	}
+	return n.Cmp(o) > 0
+}
+
+// toUsecaseBalanceSnapshot 將 repository 的餘額快照轉為 usecase 層的結構
+func toUsecaseBalanceSnapshot(item repository.BalanceSnapshot) usecase.BalanceSnapshot {
+	return usecase.BalanceSnapshot{
+		BlockNumber: item.BlockNumber,
+		Balance:     item.Balance,
+		Expected:    item.Expected,
+		Change:      item.Change,
+		Discrepancy: item.Discrepancy,
+		Baseline:    item.Baseline,
+		CreatedAt:   item.CreatedAt,
+	}
+}
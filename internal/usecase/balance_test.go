@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func TestBalanceChange(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000123"
+	other := "0x0000000000000000000000000000000000000456"
+
+	tests := []struct {
+		name     string
+		apply    func(c *balanceChange)
+		expected int64
+		active   bool
+	}{
+		{
+			name: "Outgoing transaction pays value and fee",
+			apply: func(c *balanceChange) {
+				c.addTransaction(repository.Transaction{From: address, To: other, Value: domain.BigIntFromUint64(100), Fee: domain.BigIntFromUint64(21)})
+			},
+			expected: -121,
+			active:   true,
+		},
+		{
+			name: "Incoming transaction",
+			apply: func(c *balanceChange) {
+				c.addTransaction(repository.Transaction{From: other, To: address, Value: domain.BigIntFromUint64(100), Fee: domain.BigIntFromUint64(21)})
+			},
+			expected: 100,
+			active:   true,
+		},
+		{
+			name: "Failed transaction only pays fee",
+			apply: func(c *balanceChange) {
+				c.addTransaction(repository.Transaction{From: address, To: other, Value: domain.BigIntFromUint64(100), Fee: domain.BigIntFromUint64(21), Failed: true})
+			},
+			expected: -21,
+			active:   true,
+		},
+		{
+			name: "Self transfer only pays fee",
+			apply: func(c *balanceChange) {
+				c.addTransaction(repository.Transaction{From: address, To: address, Value: domain.BigIntFromUint64(100), Fee: domain.BigIntFromUint64(21)})
+			},
+			expected: -21,
+			active:   true,
+		},
+		{
+			name: "Deposit mints to sender",
+			apply: func(c *balanceChange) {
+				c.addTransaction(repository.Transaction{From: address, To: other, Value: domain.BigIntFromUint64(40), Mint: domain.BigIntFromUint64(100), Type: domain.TxTypeDeposit})
+			},
+			expected: 60,
+			active:   true,
+		},
+		{
+			name: "Withdrawal, reward and internal transaction",
+			apply: func(c *balanceChange) {
+				c.transfer("", address, domain.BigIntFromUint64(1000))
+				c.transfer("", address, domain.BigIntFromUint64(50))
+				c.transfer(address, other, domain.BigIntFromUint64(30))
+			},
+			expected: 1020,
+			active:   true,
+		},
+		{
+			name: "Unrelated activity",
+			apply: func(c *balanceChange) {
+				c.addTransaction(repository.Transaction{From: other, To: other, Value: domain.BigIntFromUint64(100)})
+				c.transfer("", other, domain.BigIntFromUint64(1000))
+			},
+			expected: 0,
+			active:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := balanceChange{address: address}
+			tt.apply(&change)
+			assert.Equal(t, tt.expected, change.amount.Int().Int64())
+			assert.Equal(t, tt.active, change.active)
+		})
+	}
+}
+
+func TestFetchTransactionsForAddress_BalanceReconciliation(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000123"
+	block := `{
+		"result": {
+			"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+			"number": "0x10",
+			"transactions": [
+				{"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "0x0000000000000000000000000000000000000123", "to": "0x0000000000000000000000000000000000000456", "value": "0x64", "gasPrice": "0x1"}
+			],
+			"withdrawals": [
+				{"index": "0x1", "validatorIndex": "0x64", "address": "0x0000000000000000000000000000000000000123", "amount": "0x1"}
+			]
+		}
+	}`
+
+	tests := []struct {
+		name                string
+		balance             string
+		expectedDiscrepancy string
+	}{
+		// 1000000000000 - 100 - 21000 + 1000000000
+		{name: "Balance matches observed activity", balance: "0xe9103f8794", expectedDiscrepancy: "0"},
+		{name: "Missed activity", balance: "0xe9103f8799", expectedDiscrepancy: "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := repoMock.NewMockETHClient(ctrl)
+			mockStorage := repoMock.NewMockStorage(ctrl)
+			mockNotification := ucMock.NewMockNotification(ctrl)
+			parser := NewEthereumParser(EthereumParserParam{
+				Storage:               mockStorage,
+				Notification:          mockNotification,
+				EthClient:             mockClient,
+				EnableBalanceTracking: true,
+			}).(*EthereumParser)
+			parser.currentBlock = 0x10
+
+			// 只訂閱轉入，轉出的交易不會被記錄，但仍計入餘額變化
+			mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{
+				Address: address,
+				Filter:  repository.SubscriptionFilter{Direction: domain.DirectionIncoming},
+			}, true)
+			mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(json.RawMessage(block), nil)
+			mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", gomock.Any()).Return(json.RawMessage(`{
+				"result": {"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111", "gasUsed": "0x5208", "effectiveGasPrice": "0x1", "status": "0x1"}
+			}`), nil)
+			mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil)
+			mockStorage.EXPECT().GetPendingTransactions(address).Return(nil)
+			mockStorage.EXPECT().SaveWithdrawal(address, gomock.Any())
+			mockNotification.EXPECT().NotifyWithdrawal(address, gomock.Any())
+
+			mockStorage.EXPECT().GetLatestBalanceSnapshot(address).Return(repository.BalanceSnapshot{
+				BlockNumber: "0xf",
+				Balance:     domain.BigIntFromUint64(1000000000000),
+				Baseline:    true,
+			}, true)
+			mockClient.EXPECT().CallEthereum("eth_getBalance", []any{address, "0x10"}).Return(json.RawMessage(`{"result": "`+tt.balance+`"}`), nil)
+
+			var saved repository.BalanceSnapshot
+			mockStorage.EXPECT().SaveBalanceSnapshot(address, gomock.Any()).Do(func(_ string, snapshot repository.BalanceSnapshot) {
+				saved = snapshot
+			})
+			if tt.expectedDiscrepancy != "0" {
+				mockNotification.EXPECT().NotifyBalanceDiscrepancy(address, gomock.Any()).Do(func(_ string, snapshot usecase.BalanceSnapshot) {
+					assert.Equal(t, tt.expectedDiscrepancy, snapshot.Discrepancy.String())
+				})
+			}
+
+			parser.FetchTransactionsForAddress(address)
+
+			assert.Equal(t, "0x10", saved.BlockNumber)
+			assert.Equal(t, "999978900", saved.Change.String())
+			assert.Equal(t, "1000999978900", saved.Expected.String())
+			assert.Equal(t, tt.expectedDiscrepancy, saved.Discrepancy.String())
+			assert.False(t, saved.Baseline)
+		})
+	}
+}
+
+func TestReconcileBalance_SkipsCoveredBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, _ := newSubscriptionTestParser(ctrl)
+	address := "0x0000000000000000000000000000000000000123"
+
+	// 訂閱時的基準已是此區塊結束時的餘額，不再查詢也不重複計算
+	mockStorage.EXPECT().GetLatestBalanceSnapshot(address).Return(repository.BalanceSnapshot{BlockNumber: "0x10", Baseline: true}, true)
+
+	parser.reconcileBalance(address, "0x10", balanceChange{address: address, active: true})
+}
+
+func TestReconcileBalance_IncompleteActivityRecordsBaseline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	address := "0x0000000000000000000000000000000000000123"
+
+	mockStorage.EXPECT().GetLatestBalanceSnapshot(address).Return(repository.BalanceSnapshot{BlockNumber: "0xf", Balance: domain.BigIntFromUint64(100)}, true)
+	mockClient.EXPECT().CallEthereum("eth_getBalance", []any{address, "0x10"}).Return(json.RawMessage(`{"result": "0x1"}`), nil)
+	mockStorage.EXPECT().SaveBalanceSnapshot(address, gomock.Any()).Do(func(_ string, snapshot repository.BalanceSnapshot) {
+		assert.True(t, snapshot.Baseline)
+		assert.Equal(t, "1", snapshot.Expected.String())
+		assert.True(t, snapshot.Discrepancy.IsZero())
+	})
+
+	parser.reconcileBalance(address, "0x10", balanceChange{address: address, active: true, incomplete: true})
+}
+
+func TestSubscribe_RecordsBalanceBaseline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	parser.trackBalances = true
+	address := "0x0000000000000000000000000000000000000123"
+
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{}, false)
+	mockStorage.EXPECT().SubscribeAddress(gomock.Any())
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x10"}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getBalance", []any{address, "0x10"}).Return(json.RawMessage(`{"result": "0xde0b6b3a7640000"}`), nil)
+	mockStorage.EXPECT().SaveBalanceSnapshot(address, gomock.Any()).Do(func(_ string, snapshot repository.BalanceSnapshot) {
+		assert.Equal(t, "0x10", snapshot.BlockNumber)
+		assert.Equal(t, "1000000000000000000", snapshot.Balance.String())
+		assert.True(t, snapshot.Baseline)
+	})
+
+	assert.NoError(t, parser.Subscribe(usecase.Subscription{Address: address}))
+}
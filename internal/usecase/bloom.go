@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"strings"
+)
+
+// transferEventTopics 代幣與 NFT 轉移事件的 topic0
+var transferEventTopics = []string{
+	domain.TransferEventTopic,
+	domain.TransferSingleEventTopic,
+	domain.TransferBatchEventTopic,
+}
+
+// fetchBlockHeader 根據區塊號取得不含完整交易的區塊，同一區塊只查詢一次
+func (p *EthereumParser) fetchBlockHeader(blockNumber string) (repository.BlockHeader, error) {
+	p.headerMu.Lock()
+	cached := p.lastHeader
+	p.headerMu.Unlock()
+	if cached != nil && cached.Number.String() == blockNumber {
+		return *cached, nil
+	}
+
+	result, err := p.ethClient.CallEthereum("eth_getBlockByNumber", []any{blockNumber, false})
+	if err != nil {
+		return repository.BlockHeader{}, err
+	}
+
+	var rpcResponse repository.BlockHeaderResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return repository.BlockHeader{}, err
+	}
+	if rpcResponse.Error != nil {
+		return repository.BlockHeader{}, rpcResponse.Error
+	}
+
+	p.headerMu.Lock()
+	p.lastHeader = &rpcResponse.Result
+	p.headerMu.Unlock()
+	return rpcResponse.Result, nil
+}
+
+// bloomMayMatch 以區塊的 logs bloom 判斷區塊內是否可能有該地址的代幣或 NFT 轉移
+// 轉移事件以 topic 記錄轉出與轉入的地址，因此需同時包含任一轉移事件的 topic0 與該地址補零後的 topic，
+// 訂閱限定代幣合約時也需包含其中一個合約地址
+func bloomMayMatch(bloom domain.Bytes, address string, filter repository.SubscriptionFilter) bool {
+	if !bloomMayContainAny(bloom, transferEventTopics) {
+		return false
+	}
+	if !bloomMayContainAny(bloom, []string{addressTopic(address)}) {
+		return false
+	}
+	return len(filter.TokenContracts) == 0 || bloomMayContainAny(bloom, filter.TokenContracts)
+}
+
+// bloomMayContainAny values 為十六進位的地址或 topic，格式錯誤時視為可能包含
+func bloomMayContainAny(bloom domain.Bytes, values []string) bool {
+	for _, value := range values {
+		data, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		if err != nil || domain.BloomMayContain(bloom, data) {
+			return true
+		}
+	}
+	return false
+}
+
+// addressTopic 將地址左側補零為 32 bytes 的 topic
+func addressTopic(address string) string {
+	return "0x" + strings.Repeat("0", 24) + strings.TrimPrefix(address, "0x")
+}
+
+// bloomApplies logs bloom 只記錄事件，無法排除 ETH 交易與內部轉帳，因此只有不記錄交易的訂閱可以依 bloom 略過區塊；
+// 追蹤餘額時每筆 ETH 交易都會影響餘額，略過區塊會造成錯誤的對帳結果
+func (p *EthereumParser) bloomApplies(subscription repository.Subscription) bool {
+	return subscription.Filter.TokensOnly && !p.trackBalances
+}
+
+// checkBloom 啟用 bloom 預先過濾時檢查區塊是否需要處理，取得區塊失敗時保守地處理該區塊
+// 區塊標頭已包含 fee recipient 與提款，地址為其中之一時一定處理，不經 bloom 判斷；bloomMatched 表示由 bloom 判斷可能符合
+func (p *EthereumParser) checkBloom(blockNumber, address string, filter repository.SubscriptionFilter) (process, bloomMatched bool, err error) {
+	header, err := p.fetchBlockHeader(blockNumber)
+	if err != nil {
+		return true, false, err
+	}
+	if headerInvolves(header, address) {
+		return true, false, nil
+	}
+
+	p.metrics.bloomChecks.Add(1)
+	if !bloomMayMatch(header.LogsBloom, address, filter) {
+		p.metrics.bloomSkips.Add(1)
+		return false, false, nil
+	}
+	p.metrics.bloomMatches.Add(1)
+	return true, true, nil
+}
+
+// headerInvolves 地址是否為區塊的 fee recipient 或提款的收款地址
+func headerInvolves(header repository.BlockHeader, address string) bool {
+	if header.Miner.String() == address {
+		return true
+	}
+	for _, withdrawal := range header.Withdrawals {
+		if withdrawal.Address.String() == address {
+			return true
+		}
+	}
+	return false
+}
+
+// transfersInvolve 事件中是否有符合訂閱條件、與該地址相關的代幣或 NFT 轉移
+func transfersInvolve(address string, filter repository.SubscriptionFilter, tokenTransfers []repository.TokenTransfer, nftTransfers []repository.NFTTransfer) bool {
+	for _, transfer := range tokenTransfers {
+		if (transfer.From == address || transfer.To == address) && matchTokenContract(filter, transfer.Contract) {
+			return true
+		}
+	}
+	for _, transfer := range nftTransfers {
+		if (transfer.From == address || transfer.To == address) && matchTokenContract(filter, transfer.Contract) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"strings"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+// newTestBloom 產生包含 values（十六進位的地址或 topic）的 logs bloom
+func newTestBloom(values ...string) domain.Bytes {
+	bloom := make(domain.Bytes, domain.BloomLength)
+	for _, value := range values {
+		data, _ := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		domain.BloomAdd(bloom, data)
+	}
+	return bloom
+}
+
+func TestBloomMayMatch(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000123"
+	token := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+
+	tests := []struct {
+		name     string
+		bloom    domain.Bytes
+		filter   repository.SubscriptionFilter
+		expected bool
+	}{
+		{
+			name:     "Transfer involving address",
+			bloom:    newTestBloom(domain.TransferEventTopic, addressTopic(address), token),
+			expected: true,
+		},
+		{
+			name:     "ERC-1155 transfer involving address",
+			bloom:    newTestBloom(domain.TransferSingleEventTopic, addressTopic(address)),
+			expected: true,
+		},
+		{
+			name:     "Transfer of other addresses",
+			bloom:    newTestBloom(domain.TransferEventTopic, token),
+			expected: false,
+		},
+		{
+			name:     "Address without transfer event",
+			bloom:    newTestBloom(addressTopic(address)),
+			expected: false,
+		},
+		{
+			name:     "Allowlisted token contract",
+			bloom:    newTestBloom(domain.TransferEventTopic, addressTopic(address), token),
+			filter:   repository.SubscriptionFilter{TokenContracts: []string{token}},
+			expected: true,
+		},
+		{
+			name:     "Other token contract",
+			bloom:    newTestBloom(domain.TransferEventTopic, addressTopic(address), token),
+			filter:   repository.SubscriptionFilter{TokenContracts: []string{"0x0000000000000000000000000000000000000789"}},
+			expected: false,
+		},
+		{
+			name:     "Missing bloom",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, bloomMayMatch(tt.bloom, address, tt.filter))
+		})
+	}
+}
+
+func TestFetchTransactionsForAddress_BloomFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:           mockStorage,
+		Notification:      mockNotification,
+		EthClient:         mockClient,
+		EnableBloomFilter: true,
+	}).(*EthereumParser)
+	parser.currentBlock = 0x10
+
+	skipped := "0x0000000000000000000000000000000000000123"
+	matched := "0x0000000000000000000000000000000000000456"
+	miner := "0x0000000000000000000000000000000000000789"
+	plain := "0x0000000000000000000000000000000000000abc"
+	tokensOnly := repository.SubscriptionFilter{TokensOnly: true}
+	bloom := newTestBloom(domain.TransferEventTopic, addressTopic(matched))
+
+	// 同一區塊的 header 只查詢一次
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", []any{"0x10", false}).Return(json.RawMessage(`{
+		"result": {"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000", "number": "0x10", "miner": "`+miner+`", "logsBloom": "`+bloom.String()+`", "transactions": []}
+	}`), nil).Times(1)
+	mockStorage.EXPECT().GetSubscription(skipped).Return(repository.Subscription{Address: skipped, Filter: tokensOnly}, true)
+	mockStorage.EXPECT().GetSubscription(matched).Return(repository.Subscription{Address: matched, Filter: tokensOnly}, true)
+	mockStorage.EXPECT().GetSubscription(miner).Return(repository.Subscription{Address: miner, Filter: tokensOnly}, true)
+	mockStorage.EXPECT().GetSubscription(plain).Return(repository.Subscription{Address: plain}, true)
+
+	// bloom 可能符合的地址下載完整區塊與事件，事件中沒有該地址的轉移即為誤判；
+	// fee recipient 即使 bloom 不符合也需處理，記錄交易的訂閱不檢查 bloom
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", []any{"0x10", true}).Return(json.RawMessage(`{
+		"result": {"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000", "number": "0x10", "miner": "`+miner+`", "transactions": []}
+	}`), nil).Times(3)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil).Times(3)
+	mockStorage.EXPECT().GetPendingTransactions(matched).Return(nil)
+	mockStorage.EXPECT().GetPendingTransactions(miner).Return(nil)
+	mockStorage.EXPECT().GetPendingTransactions(plain).Return(nil)
+	mockStorage.EXPECT().SaveBlockReward(miner, gomock.Any())
+	mockNotification.EXPECT().NotifyBlockReward(miner, gomock.Any())
+
+	parser.FetchTransactionsForAddress(skipped)
+	parser.FetchTransactionsForAddress(matched)
+	parser.FetchTransactionsForAddress(miner)
+	parser.FetchTransactionsForAddress(plain)
+
+	assert.Equal(t, usecase.Metrics{
+		BloomChecks:         2,
+		BloomSkips:          1,
+		BloomMatches:        1,
+		BloomFalsePositives: 1,
+	}, parser.GetMetrics())
+}
+
+func TestHeaderInvolves(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000123"
+	var header repository.BlockHeader
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"miner": "0x0000000000000000000000000000000000000456",
+		"withdrawals": [{"index": "0x1", "validatorIndex": "0x2", "address": "`+address+`", "amount": "0x3"}]
+	}`), &header))
+
+	assert.True(t, headerInvolves(header, address))
+	assert.True(t, headerInvolves(header, "0x0000000000000000000000000000000000000456"))
+	assert.False(t, headerInvolves(header, "0x0000000000000000000000000000000000000789"))
+}
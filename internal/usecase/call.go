@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"encoding/json"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+)
+
+// ethCall 以 eth_call 在最新區塊上呼叫合約的唯讀函式
+func (p *EthereumParser) ethCall(to string, data []byte) (domain.Bytes, error) {
+	result, err := p.ethClient.CallEthereum("eth_call", []any{repository.CallMsg{To: to, Data: data}, "latest"})
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResponse repository.CallResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return nil, err
+	}
+	if rpcResponse.Error != nil {
+		return nil, rpcResponse.Error
+	}
+
+	return rpcResponse.Result, nil
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"time"
+)
+
+// ensResolveInterval 重新解析訂閱 ENS 名稱的間隔
+const ensResolveInterval = 10 * time.Minute
+
+var (
+	// ensResolverSelector resolver(bytes32)
+	ensResolverSelector = []byte{0x01, 0x78, 0xb8, 0xbf}
+	// ensAddrSelector addr(bytes32)
+	ensAddrSelector = []byte{0x3b, 0x3b, 0x57, 0xde}
+)
+
+// ResolveAddress 將 ENS 名稱或地址轉為標準格式的地址，輸入為地址時 name 為空字串
+func (p *EthereumParser) ResolveAddress(nameOrAddress string) (address string, name string, err error) {
+	if !domain.IsENSName(nameOrAddress) {
+		address, err = domain.NormalizeAddress(nameOrAddress)
+		return address, "", err
+	}
+
+	name, err = domain.NormalizeENSName(nameOrAddress)
+	if err != nil {
+		return "", "", err
+	}
+	address, err = p.resolveENSName(name)
+	if err != nil {
+		return "", "", err
+	}
+	return address, name, nil
+}
+
+// resolveENSName 先向 registry 查詢名稱的 resolver，再向 resolver 查詢名稱對應的地址
+func (p *EthereumParser) resolveENSName(name string) (string, error) {
+	node := domain.NameHash(name)
+
+	resolver, err := p.ethCallAddress(domain.ENSRegistryAddress, append(bytes.Clone(ensResolverSelector), node...))
+	if err != nil {
+		return "", err
+	}
+	if resolver == "" {
+		return "", fmt.Errorf("%w: %s has no resolver", domain.ErrENSNameNotFound, name)
+	}
+
+	address, err := p.ethCallAddress(resolver, append(bytes.Clone(ensAddrSelector), node...))
+	if err != nil {
+		return "", err
+	}
+	if address == "" {
+		return "", fmt.Errorf("%w: %s", domain.ErrENSNameNotFound, name)
+	}
+	return address, nil
+}
+
+// ethCallAddress 呼叫返回單一 address 的函式，零地址或空結果返回空字串
+func (p *EthereumParser) ethCallAddress(to string, data []byte) (string, error) {
+	result, err := p.ethCall(to, data)
+	if err != nil {
+		return "", err
+	}
+	if len(result) < 32 || bytes.Equal(result[12:32], make([]byte, 20)) {
+		return "", nil
+	}
+	return domain.Bytes(result[12:32]).String(), nil
+}
+
+// startENSRefresh 在背景重新解析 ENS 名稱，解析較慢時不影響區塊處理，前一次尚未完成時不重複執行
+func (p *EthereumParser) startENSRefresh() {
+	if !p.ensRefreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer p.ensRefreshing.Store(false)
+		p.refreshENSNames()
+	}()
+}
+
+// refreshENSNames 重新解析以 ENS 名稱訂閱的地址，名稱指向新地址時將訂閱移至新地址並通知
+// 舊地址也以地址直接訂閱時只移除名稱，保留舊地址的訂閱；新地址沒有訂閱時記錄餘額基準
+func (p *EthereumParser) refreshENSNames() {
+	for _, subscription := range p.storage.GetSubscriptions() {
+		if subscription.ENSName == "" {
+			continue
+		}
+
+		address, err := p.resolveENSName(subscription.ENSName)
+		if err != nil {
+			p.reportError("Error resolving ens name:", err)
+			continue
+		}
+		if address == subscription.Address {
+			continue
+		}
+
+		// 新地址已另外訂閱時保留其設定，只補上名稱
+		moved := subscription
+		moved.Direct = false
+		var target *repository.Subscription
+		existing, exists := p.storage.GetSubscription(address)
+		if exists {
+			target = &existing
+			moved = existing
+			moved.ENSName = subscription.ENSName
+			moved.Direct = isDirectSubscription(existing)
+		}
+		moved.Address = address
+
+		var kept *repository.Subscription
+		if subscription.Direct {
+			direct := subscription
+			direct.ENSName = ""
+			kept = &direct
+		}
+		// 解析期間訂閱可能已被取消、暫停或更新，此時不以過期的資料覆蓋，留待下次重新解析
+		if !p.storage.MoveSubscription(subscription, kept, target, moved) {
+			continue
+		}
+		if p.trackBalances && !exists {
+			p.recordBalanceBaseline(address)
+		}
+
+		p.notification.NotifyENSChange(address, usecase.ENSChange{
+			Name:       subscription.ENSName,
+			OldAddress: subscription.Address,
+			NewAddress: address,
+			DetectedAt: time.Now(),
+		})
+	}
+}
+
+// subscriptionAddress 將輸入轉為訂閱的地址，ENS 名稱優先比對已記錄的名稱，避免名稱改指向後找不到原訂閱
+func (p *EthereumParser) subscriptionAddress(nameOrAddress string) (string, error) {
+	if domain.IsENSName(nameOrAddress) {
+		name, err := domain.NormalizeENSName(nameOrAddress)
+		if err != nil {
+			return "", err
+		}
+		for _, subscription := range p.storage.GetSubscriptions() {
+			if subscription.ENSName == name {
+				return subscription.Address, nil
+			}
+		}
+	}
+
+	address, _, err := p.ResolveAddress(nameOrAddress)
+	return address, err
+}
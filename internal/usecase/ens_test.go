@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+const (
+	ensTestResolver = "0x0000000000000000000000000000000000000e25"
+	ensTestAddress  = "0x0000000000000000000000000000000000000123"
+)
+
+// expectENSCall 模擬 registry 與 resolver 的 eth_call，resolver 為空字串時表示名稱未設定 resolver
+func expectENSCall(mockClient *repoMock.MockETHClient, name, resolver, address string) {
+	node := domain.NameHash(name)
+	word := func(address string) json.RawMessage {
+		if address == "" {
+			return json.RawMessage(`{"result": "0x0000000000000000000000000000000000000000000000000000000000000000"}`)
+		}
+		return json.RawMessage(`{"result": "0x000000000000000000000000` + address[2:] + `"}`)
+	}
+
+	mockClient.EXPECT().CallEthereum("eth_call", []any{
+		repository.CallMsg{To: domain.ENSRegistryAddress, Data: append([]byte{0x01, 0x78, 0xb8, 0xbf}, node...)},
+		"latest",
+	}).Return(word(resolver), nil)
+	if resolver == "" {
+		return
+	}
+	mockClient.EXPECT().CallEthereum("eth_call", []any{
+		repository.CallMsg{To: resolver, Data: append([]byte{0x3b, 0x3b, 0x57, 0xde}, node...)},
+		"latest",
+	}).Return(word(address), nil)
+}
+
+func TestENSSelectors(t *testing.T) {
+	resolver := abi.Entry{Name: "resolver", Inputs: []abi.Argument{{Type: "bytes32"}}}.Selector()
+	addr := abi.Entry{Name: "addr", Inputs: []abi.Argument{{Type: "bytes32"}}}.Selector()
+	assert.Equal(t, ensResolverSelector, resolver[:])
+	assert.Equal(t, ensAddrSelector, addr[:])
+}
+
+func TestResolveAddress(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		mock            func(m *repoMock.MockETHClient)
+		expectedAddress string
+		expectedName    string
+		expectedErr     error
+	}{
+		{
+			name:            "Address is normalized without rpc",
+			input:           "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			mock:            func(m *repoMock.MockETHClient) {},
+			expectedAddress: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			name:  "ENS name",
+			input: "Alice.eth",
+			mock: func(m *repoMock.MockETHClient) {
+				expectENSCall(m, "alice.eth", ensTestResolver, ensTestAddress)
+			},
+			expectedAddress: ensTestAddress,
+			expectedName:    "alice.eth",
+		},
+		{
+			name:  "No resolver",
+			input: "nobody.eth",
+			mock: func(m *repoMock.MockETHClient) {
+				expectENSCall(m, "nobody.eth", "", "")
+			},
+			expectedErr: domain.ErrENSNameNotFound,
+		},
+		{
+			name:  "Resolver has no address",
+			input: "empty.eth",
+			mock: func(m *repoMock.MockETHClient) {
+				expectENSCall(m, "empty.eth", ensTestResolver, "")
+			},
+			expectedErr: domain.ErrENSNameNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := repoMock.NewMockETHClient(ctrl)
+			tt.mock(mockClient)
+			parser := NewEthereumParser(EthereumParserParam{EthClient: mockClient})
+
+			address, name, err := parser.ResolveAddress(tt.input)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAddress, address)
+			assert.Equal(t, tt.expectedName, name)
+		})
+	}
+}
+
+func TestRefreshENSNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	}).(*EthereumParser)
+
+	newAddress := "0x0000000000000000000000000000000000000456"
+	moved := repository.Subscription{Address: ensTestAddress, ENSName: "alice.eth", Label: "alice"}
+	unchanged := repository.Subscription{Address: "0x0000000000000000000000000000000000000789", ENSName: "bob.eth"}
+
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{
+		moved,
+		unchanged,
+		{Address: "0x0000000000000000000000000000000000000abc"},
+	})
+	expectENSCall(mockClient, "alice.eth", ensTestResolver, newAddress)
+	expectENSCall(mockClient, "bob.eth", ensTestResolver, unchanged.Address)
+
+	// 訂閱移至新地址並保留原本的設定
+	mockStorage.EXPECT().GetSubscription(newAddress).Return(repository.Subscription{}, false)
+	mockStorage.EXPECT().MoveSubscription(moved, nil, nil, repository.Subscription{Address: newAddress, ENSName: "alice.eth", Label: "alice"}).Return(true)
+	mockNotification.EXPECT().NotifyENSChange(newAddress, gomock.Any()).Do(func(_ string, change usecase.ENSChange) {
+		assert.Equal(t, "alice.eth", change.Name)
+		assert.Equal(t, ensTestAddress, change.OldAddress)
+		assert.Equal(t, newAddress, change.NewAddress)
+	})
+
+	parser.refreshENSNames()
+}
+
+func TestRefreshENSNames_DirectSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:               mockStorage,
+		Notification:          mockNotification,
+		EthClient:             mockClient,
+		EnableBalanceTracking: true,
+	}).(*EthereumParser)
+	parser.currentBlock = 0x20
+
+	newAddress := "0x0000000000000000000000000000000000000456"
+	subscription := repository.Subscription{Address: ensTestAddress, ENSName: "alice.eth", Direct: true, Label: "alice"}
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{subscription})
+	expectENSCall(mockClient, "alice.eth", ensTestResolver, newAddress)
+	mockStorage.EXPECT().GetSubscription(newAddress).Return(repository.Subscription{}, false)
+
+	// 舊地址也以地址直接訂閱，只移除名稱，新地址記錄餘額基準
+	mockStorage.EXPECT().MoveSubscription(
+		subscription,
+		&repository.Subscription{Address: ensTestAddress, Direct: true, Label: "alice"},
+		nil,
+		repository.Subscription{Address: newAddress, ENSName: "alice.eth", Label: "alice"},
+	).Return(true)
+	mockClient.EXPECT().CallEthereum("eth_getBalance", []any{newAddress, "0x20"}).Return(json.RawMessage(`{"result": "0x64"}`), nil)
+	mockStorage.EXPECT().SaveBalanceSnapshot(newAddress, gomock.Any()).Do(func(_ string, snapshot repository.BalanceSnapshot) {
+		assert.True(t, snapshot.Baseline)
+		assert.Equal(t, "100", snapshot.Balance.String())
+	})
+	mockNotification.EXPECT().NotifyENSChange(newAddress, gomock.Any())
+
+	parser.refreshENSNames()
+}
+
+func TestRefreshENSNames_UnsubscribedDuringResolve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	}).(*EthereumParser)
+
+	newAddress := "0x0000000000000000000000000000000000000456"
+	subscription := repository.Subscription{Address: ensTestAddress, ENSName: "alice.eth", Label: "alice"}
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{subscription})
+
+	// resolver 查詢被阻塞時取消訂閱
+	resolving, release := make(chan struct{}), make(chan struct{})
+	mockClient.EXPECT().CallEthereum("eth_call", gomock.Any()).DoAndReturn(func(string, []any) ([]byte, error) {
+		close(resolving)
+		<-release
+		return []byte(`{"result": "0x000000000000000000000000` + ensTestResolver[2:] + `"}`), nil
+	})
+	mockClient.EXPECT().CallEthereum("eth_call", gomock.Any()).Return(json.RawMessage(`{"result": "0x000000000000000000000000`+newAddress[2:]+`"}`), nil)
+	mockStorage.EXPECT().UnsubscribeAddress(ensTestAddress).Return(true)
+
+	// 訂閱已不存在，MoveSubscription 比對失敗，不重新建立訂閱也不通知
+	mockStorage.EXPECT().GetSubscription(newAddress).Return(repository.Subscription{}, false)
+	mockStorage.EXPECT().MoveSubscription(subscription, nil, nil, gomock.Any()).Return(false)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		parser.refreshENSNames()
+	}()
+	<-resolving
+	assert.NoError(t, parser.Unsubscribe(ensTestAddress))
+	close(release)
+	<-done
+}
+
+func TestSubscribe_ENSName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:   mockStorage,
+		EthClient: mockClient,
+	})
+
+	expectENSCall(mockClient, "alice.eth", ensTestResolver, ensTestAddress)
+	mockStorage.EXPECT().GetSubscription(ensTestAddress).Return(repository.Subscription{}, false)
+	mockStorage.EXPECT().SubscribeAddress(gomock.Any()).Do(func(subscription repository.Subscription) {
+		assert.Equal(t, ensTestAddress, subscription.Address)
+		assert.Equal(t, "alice.eth", subscription.ENSName)
+		assert.False(t, subscription.Direct)
+	})
+
+	err := parser.Subscribe(usecase.Subscription{Address: "alice.eth"})
+	assert.NoError(t, err)
+
+	// 同一地址再以地址訂閱時保留名稱並記錄為直接訂閱
+	mockStorage.EXPECT().GetSubscription(ensTestAddress).Return(repository.Subscription{Address: ensTestAddress, ENSName: "alice.eth"}, true)
+	mockStorage.EXPECT().SubscribeAddress(gomock.Any()).Do(func(subscription repository.Subscription) {
+		assert.Equal(t, "alice.eth", subscription.ENSName)
+		assert.True(t, subscription.Direct)
+	})
+
+	err = parser.Subscribe(usecase.Subscription{Address: ensTestAddress})
+	assert.NoError(t, err)
+
+	// 以名稱管理訂閱時優先使用已記錄的地址，不需重新解析
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{{Address: ensTestAddress, ENSName: "alice.eth"}})
+	mockStorage.EXPECT().SetSubscriptionPaused(ensTestAddress, true).Return(true)
+
+	err = parser.PauseSubscription("alice.eth")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"encoding/hex"
+	"fmt"
+	"golang.org/x/crypto/sha3"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SubscribeEvent 訂閱合約事件，合約地址與 topics 會先驗證並轉為標準格式
+// 訂閱的 ID 由合約、事件簽名與 topics 決定，重複訂閱時更新描述資料並保留原本的建立時間
+func (p *EthereumParser) SubscribeEvent(subscription usecase.EventSubscription) (usecase.EventSubscription, error) {
+	contract, _, err := p.ResolveAddress(subscription.Contract)
+	if err != nil {
+		return usecase.EventSubscription{}, err
+	}
+	event := subscription.Event
+	if event.Type != "event" || event.Name == "" {
+		return usecase.EventSubscription{}, fmt.Errorf("%w: expected a named event", abi.ErrInvalidABI)
+	}
+	topics, err := normalizeEventTopics(event, subscription.Topics)
+	if err != nil {
+		return usecase.EventSubscription{}, err
+	}
+
+	reply := repository.EventSubscription{
+		ID:        eventSubscriptionID(contract, event, topics),
+		Contract:  contract,
+		Topics:    topics,
+		Event:     event,
+		Label:     subscription.Label,
+		CreatedAt: time.Now(),
+	}
+	if existing, ok := p.storage.GetEventSubscription(reply.ID); ok {
+		reply.CreatedAt = existing.CreatedAt
+	}
+	p.storage.SaveEventSubscription(reply)
+	return toUsecaseEventSubscription(reply), nil
+}
+
+// UnsubscribeEvent 取消事件訂閱，已記錄的事件仍可查詢
+func (p *EthereumParser) UnsubscribeEvent(id string) error {
+	if !p.storage.DeleteEventSubscription(id) {
+		return domain.ErrEventSubscriptionNotFound
+	}
+	return nil
+}
+
+func (p *EthereumParser) GetEventSubscriptions() []usecase.EventSubscription {
+	subscriptions := p.storage.GetEventSubscriptions()
+	reply := make([]usecase.EventSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		reply = append(reply, toUsecaseEventSubscription(subscription))
+	}
+	return reply
+}
+
+func (p *EthereumParser) GetContractEvents(id string) []usecase.ContractEvent {
+	events := p.storage.GetContractEvents(id)
+	reply := make([]usecase.ContractEvent, 0, len(events))
+	for _, event := range events {
+		reply = append(reply, toUsecaseContractEvent(id, event))
+	}
+	return reply
+}
+
+// normalizeEventTopics 驗證並正規化 topics，同一位置的值排序去重，未過濾的位置為 nil 並去除結尾未過濾的位置
+// 非匿名事件的第一個位置固定為事件簽名，指定其他值時返回錯誤
+func normalizeEventTopics(event abi.Entry, topics [][]string) ([][]string, error) {
+	if len(topics) > domain.MaxEventTopics {
+		return nil, domain.ErrTooManyTopics
+	}
+
+	normalized := make([][]string, 0, domain.MaxEventTopics)
+	for _, values := range topics {
+		var position []string
+		for _, value := range values {
+			topic, err := domain.NormalizeTopic(value)
+			if err != nil {
+				return nil, err
+			}
+			position = append(position, topic)
+		}
+		slices.Sort(position)
+		normalized = append(normalized, slices.Compact(position))
+	}
+
+	if !event.Anonymous {
+		signature := event.Topic().String()
+		if len(normalized) == 0 {
+			normalized = append(normalized, nil)
+		}
+		if len(normalized[0]) > 0 && !slices.Equal(normalized[0], []string{signature}) {
+			return nil, domain.ErrEventTopicMismatch
+		}
+		normalized[0] = []string{signature}
+	}
+
+	for len(normalized) > 0 && len(normalized[len(normalized)-1]) == 0 {
+		normalized = normalized[:len(normalized)-1]
+	}
+	return normalized, nil
+}
+
+// eventSubscriptionID 以合約、事件簽名與 topics 的 keccak256 前 8 bytes 作為訂閱的 ID
+func eventSubscriptionID(contract string, event abi.Entry, topics [][]string) string {
+	positions := make([]string, 0, len(topics))
+	for _, values := range topics {
+		positions = append(positions, strings.Join(values, "|"))
+	}
+
+	hash := sha3.NewLegacyKeccak256()
+	fmt.Fprintf(hash, "%s\n%s\n%t\n%s", contract, event.Signature(), event.Anonymous, strings.Join(positions, ","))
+	return "evt-" + hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+// contractEvents 一個事件訂閱在區塊中符合的事件
+type contractEvents struct {
+	subscriptionID string
+	events         []repository.ContractEvent
+}
+
+// fetchContractEvents 以 eth_getLogs 取得每個事件訂閱在區塊中符合的事件並以訂閱的 ABI 解碼，只返回有事件的訂閱
+// 任一訂閱查詢失敗時返回錯誤，呼叫端不保存該區塊的事件並重試，以免遺漏
+func (p *EthereumParser) fetchContractEvents(blockNumber int, subscriptions []repository.EventSubscription) ([]contractEvents, error) {
+	batches := make([]contractEvents, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		logs, err := p.logs.GetLogs(eventLogFilter(subscription), blockNumber, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("event subscription %s: %w", subscription.ID, err)
+		}
+
+		batch := decodeContractEvents(subscription, logs)
+		if len(batch.events) > 0 {
+			batches = append(batches, batch)
+		}
+	}
+	return batches, nil
+}
+
+// eventLogFilter 事件訂閱的查詢條件，區塊範圍由 LogRangeFetcher 設定
+func eventLogFilter(subscription repository.EventSubscription) repository.LogFilter {
+	return repository.LogFilter{
+		Address: []string{subscription.Contract},
+		Topics:  logFilterTopics(subscription.Topics),
+	}
+}
+
+// logFilterTopics 轉為 eth_getLogs 的 topics，未過濾的位置為 null，單一值以字串表示
+func logFilterTopics(topics [][]string) []any {
+	filter := make([]any, 0, len(topics))
+	for _, values := range topics {
+		switch len(values) {
+		case 0:
+			filter = append(filter, nil)
+		case 1:
+			filter = append(filter, values[0])
+		default:
+			filter = append(filter, values)
+		}
+	}
+	return filter
+}
+
+// decodeContractEvents 解碼事件訂閱查詢到的事件，因鏈重組移除的事件略過
+func decodeContractEvents(subscription repository.EventSubscription, logs []repository.Log) contractEvents {
+	batch := contractEvents{subscriptionID: subscription.ID}
+	for _, log := range logs {
+		if !log.Removed {
+			batch.events = append(batch.events, decodeContractEvent(subscription.Event, log))
+		}
+	}
+	return batch
+}
+
+// decodeContractEvent 以訂閱的 ABI 解碼事件，無法解碼時只保留原始的 topics 與 data
+func decodeContractEvent(event abi.Entry, log repository.Log) repository.ContractEvent {
+	topics := make([]string, 0, len(log.Topics))
+	for _, topic := range log.Topics {
+		topics = append(topics, topic.String())
+	}
+	decoded, _ := event.DecodeLog(log.Topics, log.Data)
+
+	return repository.ContractEvent{
+		TxHash:      log.TransactionHash.String(),
+		BlockHash:   log.BlockHash.String(),
+		BlockNumber: log.BlockNumber.String(),
+		LogIndex:    log.LogIndex.String(),
+		Contract:    log.Address.String(),
+		Topics:      topics,
+		Data:        log.Data,
+		Event:       decoded,
+	}
+}
+
+// commitContractEvents 保存並通知事件，訂閱在下載期間被取消時略過
+func (p *EthereumParser) commitContractEvents(batches []contractEvents, processing blockProcessing) {
+	for _, batch := range batches {
+		if _, ok := p.storage.GetEventSubscription(batch.subscriptionID); ok {
+			p.saveContractEvents(batch, processing)
+		}
+	}
+}
+
+func (p *EthereumParser) saveContractEvents(batch contractEvents, processing blockProcessing) {
+	for _, event := range batch.events {
+		p.storage.SaveContractEvent(batch.subscriptionID, event)
+		processing.notification.NotifyContractEvent(batch.subscriptionID, toUsecaseContractEvent(batch.subscriptionID, event))
+	}
+}
+
+// StartEventBackfill 在背景以 eth_getLogs 查詢事件訂閱在歷史區塊範圍內的事件，進度可由 Status 查詢
+// 以節點接受的最大區塊範圍分段查詢，長時間的歷史也只需少量請求；事件以唯一鍵 upsert，重複執行不會產生重複的紀錄
+func (p *EthereumParser) StartEventBackfill(request usecase.EventBackfillRequest) (usecase.BackfillJob, error) {
+	// 只有 leader 保存與通知事件，follower 回補會與 leader 重複寫入
+	if !p.isLeader() {
+		return usecase.BackfillJob{}, domain.ErrNotLeader
+	}
+	subscription, ok := p.storage.GetEventSubscription(request.SubscriptionID)
+	if !ok {
+		return usecase.BackfillJob{}, domain.ErrEventSubscriptionNotFound
+	}
+	if err := p.validateBlockRange(request.FromBlock, request.ToBlock); err != nil {
+		return usecase.BackfillJob{}, err
+	}
+
+	job := p.addBackfillJob("events", usecase.BackfillJob{
+		FromBlock:           request.FromBlock,
+		ToBlock:             request.ToBlock,
+		EventSubscriptionID: subscription.ID,
+		Notify:              request.Notify,
+		CurrentBlock:        request.FromBlock - 1,
+		Status:              domain.BackfillStatusRunning,
+		StartedAt:           time.Now(),
+	})
+	go p.runEventBackfill(job, request, subscription)
+	return job, nil
+}
+
+// runEventBackfill 依區塊順序分段查詢並保存事件，無法查詢時停止，CurrentBlock 之後的區塊記錄為失敗以便再次執行
+func (p *EthereumParser) runEventBackfill(job usecase.BackfillJob, request usecase.EventBackfillRequest, subscription repository.EventSubscription) usecase.BackfillJob {
+	fmt.Printf("Backfilling events of blocks %d-%d for subscription %s (job %s)\n", request.FromBlock, request.ToBlock, subscription.ID, job.ID)
+
+	processing := blockProcessing{notification: silentNotification{}}
+	if request.Notify {
+		processing.notification = p.notification
+	}
+
+	err := p.logs.FetchLogs(eventLogFilter(subscription), request.FromBlock, request.ToBlock, func(end int, logs []repository.Log) bool {
+		if _, ok := p.storage.GetEventSubscription(subscription.ID); !ok {
+			return false
+		}
+		p.saveContractEvents(decodeContractEvents(subscription, logs), processing)
+		p.updateBackfillJob(job.ID, func(job *usecase.BackfillJob) {
+			job.CurrentBlock = end
+			job.ProcessedBlocks = end - job.FromBlock + 1
+		})
+		return true
+	})
+
+	job = p.updateBackfillJob(job.ID, func(job *usecase.BackfillJob) {
+		now := time.Now()
+		job.FinishedAt = &now
+		job.Status = domain.BackfillStatusCompleted
+		if err == nil && job.CurrentBlock < job.ToBlock {
+			// 訂閱在執行期間被取消
+			err = domain.ErrEventSubscriptionNotFound
+		}
+		if err != nil {
+			job.Status = domain.BackfillStatusFailed
+			job.FailedBlocks = job.ToBlock - job.CurrentBlock
+			job.Error = err.Error()
+		}
+	})
+	fmt.Printf("Event backfill job %s %s: %d blocks, %d failed\n", job.ID, job.Status, job.ProcessedBlocks, job.FailedBlocks)
+	return job
+}
+
+func toUsecaseEventSubscription(subscription repository.EventSubscription) usecase.EventSubscription {
+	return usecase.EventSubscription{
+		ID:        subscription.ID,
+		Contract:  subscription.Contract,
+		Topics:    subscription.Topics,
+		Event:     subscription.Event,
+		Signature: subscription.Event.Signature(),
+		Label:     subscription.Label,
+		CreatedAt: subscription.CreatedAt,
+	}
+}
+
+func toUsecaseContractEvent(subscriptionID string, event repository.ContractEvent) usecase.ContractEvent {
+	return usecase.ContractEvent{
+		SubscriptionID: subscriptionID,
+		TxHash:         event.TxHash,
+		BlockHash:      event.BlockHash,
+		BlockNumber:    event.BlockNumber,
+		LogIndex:       event.LogIndex,
+		Contract:       event.Contract,
+		Topics:         event.Topics,
+		Data:           event.Data,
+		Event:          event.Event,
+	}
+}
@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+	"time"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+const (
+	eventTestContract = "0x000000000000000000000000000000000000dec0"
+	eventTestUser     = "0x0000000000000000000000000000000000000123"
+)
+
+// depositEvent Deposit(address indexed user, uint256 amount)
+var depositEvent = abi.Entry{
+	Type: "event",
+	Name: "Deposit",
+	Inputs: []abi.Argument{
+		{Name: "user", Type: "address", Indexed: true},
+		{Name: "amount", Type: "uint256"},
+	},
+}
+
+func TestNormalizeEventTopics(t *testing.T) {
+	signature := depositEvent.Topic().String()
+	userTopic := "0x000000000000000000000000" + eventTestUser[2:]
+	otherTopic := "0x0000000000000000000000000000000000000000000000000000000000000456"
+
+	tests := []struct {
+		name        string
+		event       abi.Entry
+		topics      [][]string
+		expected    [][]string
+		expectedErr error
+	}{
+		{name: "No filter", event: depositEvent, expected: [][]string{{signature}}},
+		{name: "Signature given", event: depositEvent, topics: [][]string{{signature}}, expected: [][]string{{signature}}},
+		{
+			name:     "Indexed address",
+			event:    depositEvent,
+			topics:   [][]string{nil, {otherTopic, eventTestUser, otherTopic}, {}},
+			expected: [][]string{{signature}, {userTopic, otherTopic}},
+		},
+		{
+			name:     "Anonymous",
+			event:    abi.Entry{Type: "event", Name: "Raw", Anonymous: true},
+			topics:   [][]string{{}, {otherTopic}},
+			expected: [][]string{nil, {otherTopic}},
+		},
+		{name: "Other signature", event: depositEvent, topics: [][]string{{otherTopic}}, expectedErr: domain.ErrEventTopicMismatch},
+		{name: "Invalid topic", event: depositEvent, topics: [][]string{nil, {"0x1234"}}, expectedErr: domain.ErrInvalidTopic},
+		{name: "Too many", event: depositEvent, topics: make([][]string, 5), expectedErr: domain.ErrTooManyTopics},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topics, err := normalizeEventTopics(tt.event, tt.topics)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, topics)
+		})
+	}
+}
+
+func TestSubscribeEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, _ := newSubscriptionTestParser(ctrl)
+	other := "0x0000000000000000000000000000000000000456"
+
+	_, err := parser.SubscribeEvent(usecase.EventSubscription{Contract: eventTestContract, Event: abi.Entry{Type: "function", Name: "deposit"}})
+	assert.ErrorIs(t, err, abi.ErrInvalidABI)
+	_, err = parser.SubscribeEvent(usecase.EventSubscription{Contract: "0x123", Event: depositEvent})
+	assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+
+	var saved []repository.EventSubscription
+	mockStorage.EXPECT().SaveEventSubscription(gomock.Any()).Do(func(subscription repository.EventSubscription) {
+		saved = append(saved, subscription)
+	}).Times(2)
+
+	mockStorage.EXPECT().GetEventSubscription(gomock.Any()).Return(repository.EventSubscription{}, false)
+	subscription, err := parser.SubscribeEvent(usecase.EventSubscription{
+		Contract: eventTestContract,
+		Topics:   [][]string{nil, {eventTestUser, other}},
+		Event:    depositEvent,
+		Label:    "deposits",
+	})
+	assert.NoError(t, err)
+	assert.Regexp(t, "^evt-[0-9a-f]{16}$", subscription.ID)
+	assert.Equal(t, "Deposit(address,uint256)", subscription.Signature)
+	assert.Equal(t, eventTestContract, subscription.Contract)
+
+	// 相同的合約、事件與 topics 對應同一個訂閱，保留原本的建立時間
+	createdAt := time.Now().Add(-time.Hour)
+	mockStorage.EXPECT().GetEventSubscription(subscription.ID).Return(repository.EventSubscription{ID: subscription.ID, CreatedAt: createdAt}, true)
+	again, err := parser.SubscribeEvent(usecase.EventSubscription{
+		Contract: eventTestContract,
+		Topics:   [][]string{{depositEvent.Topic().String()}, {other, eventTestUser}},
+		Event:    depositEvent,
+		Label:    "renamed",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, subscription.ID, again.ID)
+	assert.Equal(t, createdAt, saved[1].CreatedAt)
+	assert.Equal(t, "renamed", saved[1].Label)
+
+	// 不同的 topics 為不同的訂閱
+	assert.NotEqual(t, subscription.ID, eventSubscriptionID(eventTestContract, depositEvent, [][]string{{depositEvent.Topic().String()}}))
+
+	mockStorage.EXPECT().DeleteEventSubscription("evt-missing").Return(false)
+	assert.ErrorIs(t, parser.UnsubscribeEvent("evt-missing"), domain.ErrEventSubscriptionNotFound)
+}
+
+func TestPollOnce_ContractEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	}).(*EthereumParser)
+	parser.currentBlock = 0x10
+	parser.lastENSCheck = time.Now()
+
+	signature := depositEvent.Topic().String()
+	userTopic := "0x000000000000000000000000" + eventTestUser[2:]
+	subscription := repository.EventSubscription{
+		ID:       "evt-0000000000000001",
+		Contract: eventTestContract,
+		Topics:   [][]string{{signature}, {userTopic}},
+		Event:    depositEvent,
+	}
+	mockStorage.EXPECT().GetSubscriptions().Return(nil).AnyTimes()
+	mockStorage.EXPECT().GetEventSubscriptions().Return([]repository.EventSubscription{subscription})
+	mockStorage.EXPECT().GetEventSubscription(subscription.ID).Return(subscription, true)
+	mockStorage.EXPECT().GetLastProcessedBlock().Return(0, false)
+	mockStorage.EXPECT().SaveLastProcessedBlock(0x11)
+
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x11"}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", []any{repository.LogFilter{
+		FromBlock: "0x11",
+		ToBlock:   "0x11",
+		Address:   []string{eventTestContract},
+		Topics:    []any{signature, userTopic},
+	}}).Return(json.RawMessage(fmt.Sprintf(`{"result": [
+		{"address": "%[1]s", "topics": ["%[2]s", "%[3]s"], "data": "0x00000000000000000000000000000000000000000000000000000000000003e8", "blockNumber": "0x11", "transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111", "logIndex": "0x1"},
+		{"address": "%[1]s", "topics": ["%[2]s", "%[3]s"], "data": "0x", "blockNumber": "0x11", "transactionHash": "0x2222222222222222222222222222222222222222222222222222222222222222", "logIndex": "0x2"},
+		{"address": "%[1]s", "topics": ["%[2]s", "%[3]s"], "data": "0x", "blockNumber": "0x11", "transactionHash": "0x3333333333333333333333333333333333333333333333333333333333333333", "logIndex": "0x3", "removed": true}
+	]}`, eventTestContract, signature, userTopic)), nil)
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", []any{"0x11", false}).Return(json.RawMessage(`{"result": {"number": "0x11", "timestamp": "0x6553f100"}}`), nil)
+
+	// 無法解碼的事件仍保存原始資料，因鏈重組移除的事件略過
+	var saved []repository.ContractEvent
+	mockStorage.EXPECT().SaveContractEvent(subscription.ID, gomock.Any()).Do(func(_ string, event repository.ContractEvent) {
+		saved = append(saved, event)
+	}).Times(2)
+	var notified []usecase.ContractEvent
+	mockNotification.EXPECT().NotifyContractEvent(subscription.ID, gomock.Any()).Do(func(_ string, event usecase.ContractEvent) {
+		notified = append(notified, event)
+	}).Times(2)
+
+	parser.pollOnce()
+	assert.Equal(t, 0x11, parser.currentBlock)
+
+	assert.Len(t, saved, 2)
+	assert.Equal(t, "Deposit(user: 0x0000000000000000000000000000000000000123, amount: 1000)", saved[0].Event.String())
+	assert.Equal(t, []string{signature, userTopic}, saved[0].Topics)
+	assert.Equal(t, "0x1", saved[0].LogIndex)
+	assert.Nil(t, saved[1].Event)
+	assert.Equal(t, subscription.ID, notified[0].SubscriptionID)
+	assert.Equal(t, saved[0].Event, notified[0].Event)
+}
+
+func TestPollOnce_ContractEventsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	}).(*EthereumParser)
+	parser.currentBlock = 0x10
+	parser.lastENSCheck = time.Now()
+
+	subscription := repository.EventSubscription{ID: "evt-0000000000000001", Contract: eventTestContract, Event: depositEvent}
+	mockStorage.EXPECT().GetSubscriptions().Return(nil).AnyTimes()
+	mockStorage.EXPECT().GetEventSubscriptions().Return([]repository.EventSubscription{subscription})
+	mockStorage.EXPECT().GetLastProcessedBlock().Return(0, false)
+
+	// 事件查詢失敗時不推進目前區塊，也不記錄進度，下次輪詢重新查詢該區塊的事件
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x11"}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(nil, errors.New("connection reset")).AnyTimes()
+	parser.pollOnce()
+	assert.Equal(t, 0x10, parser.currentBlock)
+	assert.Equal(t, 0, parser.status.lastProcessedBlock)
+	assert.Contains(t, parser.status.lastError.Message, "Error fetching block 17 contract events")
+}
+
+func TestCommitContractEvents_Unsubscribed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// 下載期間取消的訂閱不保存也不通知
+	parser, mockStorage, _ := newSubscriptionTestParser(ctrl)
+	mockStorage.EXPECT().GetEventSubscription("evt-0000000000000001").Return(repository.EventSubscription{}, false)
+	parser.commitContractEvents([]contractEvents{{
+		subscriptionID: "evt-0000000000000001",
+		events:         []repository.ContractEvent{{TxHash: "0x1111111111111111111111111111111111111111111111111111111111111111"}},
+	}}, blockProcessing{notification: parser.notification, live: true})
+}
+
+func TestStartEventBackfill_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	subscription := repository.EventSubscription{ID: "evt-0000000000000001", Contract: eventTestContract, Event: depositEvent}
+	mockStorage.EXPECT().GetEventSubscription("evt-missing").Return(repository.EventSubscription{}, false)
+	mockStorage.EXPECT().GetEventSubscription(subscription.ID).Return(subscription, true).Times(2)
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x14"}`), nil)
+
+	_, err := parser.StartEventBackfill(usecase.EventBackfillRequest{SubscriptionID: "evt-missing", FromBlock: 1, ToBlock: 2})
+	assert.ErrorIs(t, err, domain.ErrEventSubscriptionNotFound)
+	_, err = parser.StartEventBackfill(usecase.EventBackfillRequest{SubscriptionID: subscription.ID, FromBlock: 3, ToBlock: 2})
+	assert.ErrorIs(t, err, domain.ErrInvalidBlockRange)
+	_, err = parser.StartEventBackfill(usecase.EventBackfillRequest{SubscriptionID: subscription.ID, FromBlock: 1, ToBlock: 0x15})
+	assert.ErrorIs(t, err, domain.ErrBlockBeyondHead)
+	assert.Empty(t, parser.status.backfillJobs)
+
+	// follower 不接受回補的工作
+	mockLeadership := ucMock.NewMockLeadership(ctrl)
+	parser.leadership = mockLeadership
+	mockLeadership.EXPECT().IsLeader().Return(false)
+	_, err = parser.StartEventBackfill(usecase.EventBackfillRequest{SubscriptionID: subscription.ID, FromBlock: 1, ToBlock: 2})
+	assert.ErrorIs(t, err, domain.ErrNotLeader)
+	assert.Empty(t, parser.status.backfillJobs)
+}
+
+func TestRunEventBackfill(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	parser.logs = NewLogRangeFetcher(LogRangeParam{EthClient: mockClient, MaxRange: 100})
+	signature := depositEvent.Topic().String()
+	subscription := repository.EventSubscription{
+		ID:       "evt-0000000000000001",
+		Contract: eventTestContract,
+		Topics:   [][]string{{signature}},
+		Event:    depositEvent,
+	}
+	request := usecase.EventBackfillRequest{SubscriptionID: subscription.ID, FromBlock: 0x10, ToBlock: 0x20}
+	job := parser.addBackfillJob("events", usecase.BackfillJob{
+		FromBlock:           request.FromBlock,
+		ToBlock:             request.ToBlock,
+		EventSubscriptionID: subscription.ID,
+		CurrentBlock:        request.FromBlock - 1,
+		Status:              domain.BackfillStatusRunning,
+	})
+	assert.Equal(t, "events-1", job.ID)
+
+	// 範圍被拒絕時切分，之後的區段沿用較小的範圍；不通知時只保存紀錄
+	filter := func(from, to string) []any {
+		return []any{repository.LogFilter{FromBlock: from, ToBlock: to, Address: []string{eventTestContract}, Topics: []any{signature}}}
+	}
+	gomock.InOrder(
+		mockClient.EXPECT().CallEthereum("eth_getLogs", filter("0x10", "0x20")).Return(json.RawMessage(`{"error": {"code": -32005, "message": "query returned more than 10000 results"}}`), nil),
+		mockClient.EXPECT().CallEthereum("eth_getLogs", filter("0x10", "0x17")).Return(json.RawMessage(fmt.Sprintf(`{"result": [
+			{"address": "%s", "topics": ["%s", "0x000000000000000000000000%s"], "data": "0x00000000000000000000000000000000000000000000000000000000000003e8", "blockNumber": "0x12", "transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111", "logIndex": "0x0"}
+		]}`, eventTestContract, signature, eventTestUser[2:])), nil),
+		mockClient.EXPECT().CallEthereum("eth_getLogs", filter("0x18", "0x1f")).Return(json.RawMessage(`{"result": []}`), nil),
+		mockClient.EXPECT().CallEthereum("eth_getLogs", filter("0x20", "0x20")).Return(json.RawMessage(`{"result": []}`), nil),
+	)
+	mockStorage.EXPECT().GetEventSubscription(subscription.ID).Return(subscription, true).Times(3)
+	mockStorage.EXPECT().SaveContractEvent(subscription.ID, gomock.Any()).Do(func(_ string, event repository.ContractEvent) {
+		assert.Equal(t, "Deposit(user: 0x0000000000000000000000000000000000000123, amount: 1000)", event.Event.String())
+	})
+
+	job = parser.runEventBackfill(job, request, subscription)
+	assert.Equal(t, domain.BackfillStatusCompleted, job.Status)
+	assert.Equal(t, 0x20, job.CurrentBlock)
+	assert.Equal(t, 17, job.ProcessedBlocks)
+	assert.Zero(t, job.FailedBlocks)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Equal(t, 8, parser.logs.RangeSize())
+}
+
+func TestRunEventBackfill_Failed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	parser.logs = NewLogRangeFetcher(LogRangeParam{EthClient: mockClient, MaxRange: 4})
+	subscription := repository.EventSubscription{ID: "evt-0000000000000001", Contract: eventTestContract, Event: depositEvent}
+	request := usecase.EventBackfillRequest{SubscriptionID: subscription.ID, FromBlock: 0, ToBlock: 9}
+	job := parser.addBackfillJob("events", usecase.BackfillJob{FromBlock: 0, ToBlock: 9, CurrentBlock: -1, Status: domain.BackfillStatusRunning})
+
+	// 第二段無法查詢時停止，之後的區塊記錄為失敗
+	gomock.InOrder(
+		mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil),
+		mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"error": {"code": -32000, "message": "header not found"}}`), nil),
+	)
+	mockStorage.EXPECT().GetEventSubscription(subscription.ID).Return(subscription, true)
+
+	job = parser.runEventBackfill(job, request, subscription)
+	assert.Equal(t, domain.BackfillStatusFailed, job.Status)
+	assert.Equal(t, 3, job.CurrentBlock)
+	assert.Equal(t, 6, job.FailedBlocks)
+	assert.Contains(t, job.Error, "eth_getLogs blocks 4-7: rpc error -32000: header not found")
+}
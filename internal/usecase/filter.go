@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"encoding/hex"
+	"fmt"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"slices"
+)
+
+// toRepositoryFilter 驗證過濾條件並將地址、selector 轉為標準格式
+func toRepositoryFilter(filter usecase.SubscriptionFilter) (repository.SubscriptionFilter, error) {
+	direction, err := domain.NormalizeDirection(filter.Direction)
+	if err != nil {
+		return repository.SubscriptionFilter{}, err
+	}
+	if filter.MinValue.Sign() < 0 {
+		return repository.SubscriptionFilter{}, domain.ErrNegativeMinValue
+	}
+
+	reply := repository.SubscriptionFilter{
+		Direction:     direction,
+		MinValue:      filter.MinValue,
+		ExcludeFailed: filter.ExcludeFailed,
+		TokensOnly:    filter.TokensOnly,
+	}
+	for _, contract := range filter.TokenContracts {
+		contract, err = domain.NormalizeAddress(contract)
+		if err != nil {
+			return repository.SubscriptionFilter{}, err
+		}
+		reply.TokenContracts = append(reply.TokenContracts, contract)
+	}
+	for _, selector := range filter.MethodSelectors {
+		selector, err = domain.NormalizeSelector(selector)
+		if err != nil {
+			return repository.SubscriptionFilter{}, err
+		}
+		reply.MethodSelectors = append(reply.MethodSelectors, selector)
+	}
+
+	return reply, nil
+}
+
+func toUsecaseFilter(filter repository.SubscriptionFilter) usecase.SubscriptionFilter {
+	return usecase.SubscriptionFilter{
+		Direction:       filter.Direction,
+		MinValue:        filter.MinValue,
+		TokenContracts:  filter.TokenContracts,
+		MethodSelectors: filter.MethodSelectors,
+		ExcludeFailed:   filter.ExcludeFailed,
+		TokensOnly:      filter.TokensOnly,
+	}
+}
+
+// matchDirection 以訂閱地址的角度檢查方向，自己轉給自己的交易同時符合兩個方向
+func matchDirection(filter repository.SubscriptionFilter, address, from, to string) bool {
+	switch filter.Direction {
+	case domain.DirectionIncoming:
+		return to == address
+	case domain.DirectionOutgoing:
+		return from == address
+	}
+	return to == address || from == address
+}
+
+// matchValue 檢查轉帳金額是否達到最低金額（wei）
+func matchValue(filter repository.SubscriptionFilter, value domain.BigInt) bool {
+	return value.Cmp(filter.MinValue) >= 0
+}
+
+// matchSelector 檢查 input 的 4 bytes selector，設定 selector 後一般轉帳不會符合
+func matchSelector(filter repository.SubscriptionFilter, input []byte) bool {
+	if len(filter.MethodSelectors) == 0 {
+		return true
+	}
+	if len(input) < 4 {
+		return false
+	}
+	return slices.Contains(filter.MethodSelectors, "0x"+hex.EncodeToString(input[:4]))
+}
+
+// matchTokenContract 檢查代幣合約是否在允許清單中
+func matchTokenContract(filter repository.SubscriptionFilter, contract string) bool {
+	return len(filter.TokenContracts) == 0 || slices.Contains(filter.TokenContracts, contract)
+}
+
+// matchTransaction 檢查交易是否符合過濾條件，交易在取得收據後才能以 ExcludeFailed 判斷是否失敗
+func matchTransaction(filter repository.SubscriptionFilter, address string, tx usecase.Transaction) bool {
+	return !filter.TokensOnly &&
+		matchDirection(filter, address, tx.From, tx.To) &&
+		matchValue(filter, tx.Value) &&
+		matchSelector(filter, tx.Input) &&
+		!(tx.Failed && filter.ExcludeFailed)
+}
+
+// SubscriptionFilterProcessor 依訂閱的過濾條件決定是否保留紀錄的 filter，實現了 Processor interface
+type SubscriptionFilterProcessor struct{}
+
+// Process 保留符合訂閱方向、金額、代幣合約與 selector 等條件的紀錄
+func (SubscriptionFilterProcessor) Process(item *usecase.PipelineItem) (bool, error) {
+	filter := repository.SubscriptionFilter{
+		Direction:       item.Filter.Direction,
+		MinValue:        item.Filter.MinValue,
+		TokenContracts:  item.Filter.TokenContracts,
+		MethodSelectors: item.Filter.MethodSelectors,
+		ExcludeFailed:   item.Filter.ExcludeFailed,
+		TokensOnly:      item.Filter.TokensOnly,
+	}
+	address := item.Address
+
+	switch {
+	case item.Transaction != nil:
+		return matchTransaction(filter, address, *item.Transaction), nil
+	case item.TokenTransfer != nil:
+		transfer := item.TokenTransfer
+		return matchDirection(filter, address, transfer.From, transfer.To) && matchTokenContract(filter, transfer.Contract), nil
+	case item.NFTTransfer != nil:
+		transfer := item.NFTTransfer
+		return matchDirection(filter, address, transfer.From, transfer.To) && matchTokenContract(filter, transfer.Contract), nil
+	case item.InternalTransaction != nil:
+		tx := item.InternalTransaction
+		return !filter.TokensOnly && matchDirection(filter, address, tx.From, tx.To) && matchValue(filter, tx.Value), nil
+	}
+	return false, fmt.Errorf("unknown pipeline item kind %q", item.Kind)
+}
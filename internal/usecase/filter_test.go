@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func TestMatchTransaction(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000123"
+	incoming := usecase.Transaction{
+		From:  "0x0000000000000000000000000000000000000456",
+		To:    address,
+		Value: domain.BigIntFromUint64(1000),
+		Input: domain.Bytes{0x09, 0x5e, 0xa7, 0xb3, 0x00},
+	}
+	outgoing := usecase.Transaction{From: address, To: "0x0000000000000000000000000000000000000456"}
+	self := usecase.Transaction{From: address, To: address}
+	failed := usecase.Transaction{From: address, To: address, Failed: true}
+
+	tests := []struct {
+		name     string
+		filter   repository.SubscriptionFilter
+		tx       usecase.Transaction
+		expected bool
+	}{
+		{name: "No filter", tx: incoming, expected: true},
+		{name: "Unrelated transaction", tx: usecase.Transaction{From: "0x0000000000000000000000000000000000000456"}, expected: false},
+		{name: "Incoming only matches incoming", filter: repository.SubscriptionFilter{Direction: domain.DirectionIncoming}, tx: incoming, expected: true},
+		{name: "Incoming only skips outgoing", filter: repository.SubscriptionFilter{Direction: domain.DirectionIncoming}, tx: outgoing, expected: false},
+		{name: "Outgoing only skips incoming", filter: repository.SubscriptionFilter{Direction: domain.DirectionOutgoing}, tx: incoming, expected: false},
+		{name: "Self transfer matches outgoing", filter: repository.SubscriptionFilter{Direction: domain.DirectionOutgoing}, tx: self, expected: true},
+		{name: "Value equal to minimum", filter: repository.SubscriptionFilter{MinValue: domain.BigIntFromUint64(1000)}, tx: incoming, expected: true},
+		{name: "Value below minimum", filter: repository.SubscriptionFilter{MinValue: domain.BigIntFromUint64(1001)}, tx: incoming, expected: false},
+		{name: "Selector matches", filter: repository.SubscriptionFilter{MethodSelectors: []string{"0xa9059cbb", "0x095ea7b3"}}, tx: incoming, expected: true},
+		{name: "Selector does not match", filter: repository.SubscriptionFilter{MethodSelectors: []string{"0xa9059cbb"}}, tx: incoming, expected: false},
+		{name: "Plain transfer skipped when selectors set", filter: repository.SubscriptionFilter{MethodSelectors: []string{"0xa9059cbb"}}, tx: outgoing, expected: false},
+		{name: "Failed transaction kept by default", tx: failed, expected: true},
+		{name: "Failed transaction excluded", filter: repository.SubscriptionFilter{ExcludeFailed: true}, tx: failed, expected: false},
+		{name: "Tokens only skips transactions", filter: repository.SubscriptionFilter{TokensOnly: true}, tx: incoming, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchTransaction(tt.filter, address, tt.tx))
+		})
+	}
+}
+
+func TestSubscriptionFilterProcessor(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000123"
+	other := "0x0000000000000000000000000000000000000456"
+	contract := "0x000000000000000000000000000000000000ee20"
+	tokensOnly := usecase.SubscriptionFilter{TokensOnly: true, TokenContracts: []string{contract}}
+
+	tests := []struct {
+		name     string
+		item     usecase.PipelineItem
+		expected bool
+	}{
+		{
+			name:     "Transaction",
+			item:     usecase.PipelineItem{Kind: domain.PipelineKindTransaction, Transaction: &usecase.Transaction{From: other, To: address}},
+			expected: true,
+		},
+		{
+			name:     "Transaction of tokens only subscription",
+			item:     usecase.PipelineItem{Filter: tokensOnly, Kind: domain.PipelineKindTransaction, Transaction: &usecase.Transaction{From: other, To: address}},
+			expected: false,
+		},
+		{
+			name:     "Token transfer of allowed contract",
+			item:     usecase.PipelineItem{Filter: tokensOnly, Kind: domain.PipelineKindTokenTransfer, TokenTransfer: &usecase.TokenTransfer{Contract: contract, From: other, To: address}},
+			expected: true,
+		},
+		{
+			name:     "Token transfer of other contract",
+			item:     usecase.PipelineItem{Filter: tokensOnly, Kind: domain.PipelineKindTokenTransfer, TokenTransfer: &usecase.TokenTransfer{Contract: other, From: other, To: address}},
+			expected: false,
+		},
+		{
+			name:     "Outgoing NFT transfer of incoming subscription",
+			item:     usecase.PipelineItem{Filter: usecase.SubscriptionFilter{Direction: domain.DirectionIncoming}, Kind: domain.PipelineKindNFTTransfer, NFTTransfer: &usecase.NFTTransfer{From: address, To: other}},
+			expected: false,
+		},
+		{
+			name:     "Internal transaction below minimum value",
+			item:     usecase.PipelineItem{Filter: usecase.SubscriptionFilter{MinValue: domain.BigIntFromUint64(10)}, Kind: domain.PipelineKindInternalTransaction, InternalTransaction: &usecase.InternalTransaction{From: other, To: address, Value: domain.BigIntFromUint64(9)}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.item.Address = address
+			keep, err := SubscriptionFilterProcessor{}.Process(&tt.item)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, keep)
+		})
+	}
+
+	_, err := SubscriptionFilterProcessor{}.Process(&usecase.PipelineItem{Address: address, Kind: "withdrawal"})
+	assert.EqualError(t, err, `unknown pipeline item kind "withdrawal"`)
+}
+
+func TestFetchTransactionsForAddress_Filter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	})
+
+	address := "0x0000000000000000000000000000000000000123"
+	allowedToken := "0x000000000000000000000000000000000000ee20"
+
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"result": {
+			"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+			"transactions": [
+				{"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000123", "value": "0x10", "gasPrice": "0x5"},
+				{"hash": "0x2222222222222222222222222222222222222222222222222222222222222222", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000123", "value": "0x20", "gasPrice": "0x5"},
+				{"hash": "0x3333333333333333333333333333333333333333333333333333333333333333", "from": "0x0000000000000000000000000000000000000123", "to": "0x0000000000000000000000000000000000000789", "value": "0x20", "gasPrice": "0x5"}
+			]
+		}
+	}`), nil)
+	// 與地址相關的交易都會查詢收據後交給訂閱過濾階段：第一筆交易金額不足，第二筆交易執行失敗，第三筆為轉出交易
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", []any{"0x1111111111111111111111111111111111111111111111111111111111111111"}).Return(json.RawMessage(`{"result": null}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", []any{"0x2222222222222222222222222222222222222222222222222222222222222222"}).Return(json.RawMessage(`{
+		"result": {"transactionHash": "0x2222222222222222222222222222222222222222222222222222222222222222", "gasUsed": "0x5208", "status": "0x0"}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", []any{"0x3333333333333333333333333333333333333333333333333333333333333333"}).Return(json.RawMessage(`{"result": null}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{
+		"result": [
+			{
+				"address": "0x000000000000000000000000000000000000ee20",
+				"topics": [
+					"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+					"0x0000000000000000000000000000000000000000000000000000000000000789",
+					"0x0000000000000000000000000000000000000000000000000000000000000123"
+				],
+				"data": "0x0000000000000000000000000000000000000000000000000000000000000001"
+			},
+			{
+				"address": "0x000000000000000000000000000000000000ee21",
+				"topics": [
+					"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+					"0x0000000000000000000000000000000000000000000000000000000000000789",
+					"0x0000000000000000000000000000000000000000000000000000000000000123"
+				],
+				"data": "0x0000000000000000000000000000000000000000000000000000000000000001"
+			}
+		]
+	}`), nil)
+
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{
+		Address: address,
+		Filter: repository.SubscriptionFilter{
+			Direction:      domain.DirectionIncoming,
+			MinValue:       domain.BigIntFromUint64(0x20),
+			TokenContracts: []string{allowedToken},
+			ExcludeFailed:  true,
+		},
+	}, true)
+	mockStorage.EXPECT().GetPendingTransactions(address).Return(nil)
+	mockStorage.EXPECT().SaveTransaction(gomock.Any(), gomock.Any()).Times(0)
+	mockNotification.EXPECT().Notify(gomock.Any(), gomock.Any()).Times(0)
+	mockStorage.EXPECT().SaveTokenTransfer(address, gomock.Any()).Do(func(_ string, transfer repository.TokenTransfer) {
+		assert.Equal(t, allowedToken, transfer.Contract)
+	}).Times(1)
+	mockNotification.EXPECT().NotifyTokenTransfer(address, gomock.Any()).Times(1)
+
+	parser.(*EthereumParser).FetchTransactionsForAddress(address)
+}
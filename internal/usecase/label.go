@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"fmt"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/usecase"
+	"strings"
+)
+
+type AddressLabelParam struct {
+	// Labels 地址對應的標籤，例如交易所或合約的名稱
+	Labels map[string]string
+}
+
+// AddressLabelProcessor 以交易的 from 與 to 地址加上標籤的 enricher，實現了 Processor interface
+type AddressLabelProcessor struct {
+	labels map[string]string
+}
+
+// NewAddressLabelProcessor 正規化標籤的地址，地址格式錯誤時返回錯誤
+func NewAddressLabelProcessor(param AddressLabelParam) (*AddressLabelProcessor, error) {
+	labels := make(map[string]string, len(param.Labels))
+	for item, label := range param.Labels {
+		address, err := domain.NormalizeAddress(item)
+		if err != nil {
+			return nil, fmt.Errorf("address label %q: %w", item, err)
+		}
+		labels[address] = label
+	}
+	return &AddressLabelProcessor{labels: labels}, nil
+}
+
+// Process 將交易 from 與 to 地址的標籤記錄於 Labels 的 from 與 to，沒有標籤的地址不記錄，其他紀錄不處理
+func (a *AddressLabelProcessor) Process(item *usecase.PipelineItem) (bool, error) {
+	tx := item.Transaction
+	if tx == nil {
+		return true, nil
+	}
+	a.label(tx, "from", tx.From)
+	a.label(tx, "to", tx.To)
+	return true, nil
+}
+
+func (a *AddressLabelProcessor) label(tx *usecase.Transaction, key, address string) {
+	label, ok := a.labels[strings.ToLower(address)]
+	if !ok {
+		return
+	}
+	if tx.Labels == nil {
+		tx.Labels = make(map[string]string)
+	}
+	tx.Labels[key] = label
+}
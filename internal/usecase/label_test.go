@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"github.com/stretchr/testify/assert"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/usecase"
+	"testing"
+)
+
+func TestAddressLabelProcessor(t *testing.T) {
+	_, err := NewAddressLabelProcessor(AddressLabelParam{Labels: map[string]string{"0x123": "invalid"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+
+	processor, err := NewAddressLabelProcessor(AddressLabelParam{Labels: map[string]string{
+		"0x00000000000000000000000000000000000000AB": "exchange",
+		"0x0000000000000000000000000000000000000456": "treasury",
+	}})
+	assert.NoError(t, err)
+
+	// 地址不分大小寫，沒有標籤的地址不記錄
+	item := &usecase.PipelineItem{Transaction: &usecase.Transaction{
+		From: "0x0000000000000000000000000000000000000123",
+		To:   "0x00000000000000000000000000000000000000ab",
+	}}
+	keep, err := processor.Process(item)
+	assert.True(t, keep)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"to": "exchange"}, item.Transaction.Labels)
+
+	item = &usecase.PipelineItem{Transaction: &usecase.Transaction{From: "0x0000000000000000000000000000000000000123"}}
+	processor.Process(item)
+	assert.Nil(t, item.Transaction.Labels)
+
+	// 代幣轉帳等其他紀錄直接保留
+	keep, err = processor.Process(&usecase.PipelineItem{TokenTransfer: &usecase.TokenTransfer{To: "0x00000000000000000000000000000000000000ab"}})
+	assert.True(t, keep)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"fmt"
+	"parse_server/internal/domain/repository"
+	"sync/atomic"
+	"time"
+)
+
+// defaultLeaderInterval 競選與續約的預設間隔，需小於租約的有效期限
+const defaultLeaderInterval = 10 * time.Second
+
+type LeaderElectionParam struct {
+	Elector repository.LeaderElector
+	// Interval 競選與續約的間隔，follower 最慢在 leader 失效後的一個間隔（租約則另加 TTL）內接手
+	Interval time.Duration
+}
+
+// LeaderElection 定期競選或續約領導權，實現了 Leadership interface
+type LeaderElection struct {
+	elector  repository.LeaderElector
+	interval time.Duration
+	leading  atomic.Bool
+}
+
+func NewLeaderElection(param LeaderElectionParam) *LeaderElection {
+	interval := param.Interval
+	if interval <= 0 {
+		interval = defaultLeaderInterval
+	}
+	return &LeaderElection{
+		elector:  param.Elector,
+		interval: interval,
+	}
+}
+
+// Run 持續競選或續約領導權
+func (l *LeaderElection) Run() {
+	for {
+		l.campaign()
+		time.Sleep(l.interval)
+	}
+}
+
+// campaign 嘗試取得或續約領導權，發生錯誤時無法確認租約仍有效，視為失去領導權以免多個實例同時處理
+func (l *LeaderElection) campaign() {
+	leading, err := l.elector.TryAcquire()
+	if err != nil {
+		fmt.Println("Error acquiring leadership:", err)
+		leading = false
+	}
+
+	if previous := l.leading.Swap(leading); previous != leading {
+		if leading {
+			fmt.Println("Became leader, start polling for changes")
+		} else {
+			fmt.Println("Lost leadership, serving read API only")
+		}
+	}
+}
+
+// IsLeader 目前是否為 leader
+func (l *LeaderElection) IsLeader() bool {
+	return l.leading.Load()
+}
+
+// Resign 釋放領導權，讓其他實例立即接手
+func (l *LeaderElection) Resign() error {
+	l.leading.Store(false)
+	return l.elector.Release()
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func TestLeaderElection_Campaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockElector := repoMock.NewMockLeaderElector(ctrl)
+	election := NewLeaderElection(LeaderElectionParam{Elector: mockElector})
+	assert.False(t, election.IsLeader())
+
+	gomock.InOrder(
+		mockElector.EXPECT().TryAcquire().Return(false, nil),
+		mockElector.EXPECT().TryAcquire().Return(true, nil),
+		mockElector.EXPECT().TryAcquire().Return(true, nil),
+		// 無法確認租約時視為失去領導權
+		mockElector.EXPECT().TryAcquire().Return(true, errors.New("storage unavailable")),
+		mockElector.EXPECT().TryAcquire().Return(true, nil),
+	)
+
+	expected := []bool{false, true, true, false, true}
+	for _, leading := range expected {
+		election.campaign()
+		assert.Equal(t, leading, election.IsLeader())
+	}
+
+	mockElector.EXPECT().Release().Return(nil)
+	assert.NoError(t, election.Resign())
+	assert.False(t, election.IsLeader())
+}
+
+func TestEthereumParser_IsLeader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// 沒有設定 leader 選舉時視為唯一的實例
+	parser := NewEthereumParser(EthereumParserParam{}).(*EthereumParser)
+	assert.True(t, parser.isLeader())
+
+	mockLeadership := ucMock.NewMockLeadership(ctrl)
+	parser = NewEthereumParser(EthereumParserParam{Leadership: mockLeadership}).(*EthereumParser)
+	mockLeadership.EXPECT().IsLeader().Return(false)
+	assert.False(t, parser.isLeader())
+	mockLeadership.EXPECT().IsLeader().Return(true)
+	assert.True(t, parser.isLeader())
+}
+
+func TestPollOnce_LeaderTakeover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockLeadership := ucMock.NewMockLeadership(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:    mockStorage,
+		EthClient:  mockClient,
+		Leadership: mockLeadership,
+	}).(*EthereumParser)
+	parser.currentBlock = 0x10
+	parser.lastENSCheck = time.Now()
+	mockStorage.EXPECT().GetSubscriptions().Return(nil).AnyTimes()
+	mockStorage.EXPECT().GetEventSubscriptions().Return(nil).AnyTimes()
+
+	// follower 不處理區塊，只跟隨 leader 記錄的進度
+	mockLeadership.EXPECT().IsLeader().Return(false)
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x13"}`), nil)
+	mockStorage.EXPECT().GetLastProcessedBlock().Return(0x11, true)
+	parser.pollOnce()
+	assert.Equal(t, 0x11, parser.currentBlock)
+
+	// 成為 leader 時由前一個 leader 最後處理完的區塊繼續，不會跳到最新區塊
+	mockLeadership.EXPECT().IsLeader().Return(true).AnyTimes()
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x14"}`), nil)
+	mockStorage.EXPECT().GetLastProcessedBlock().Return(0x12, true)
+	for _, blockNumber := range []string{"0x13", "0x14"} {
+		mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", []any{blockNumber, false}).Return(json.RawMessage(`{"result": {"number": "`+blockNumber+`", "timestamp": "0x6553f100"}}`), nil)
+	}
+	mockStorage.EXPECT().SaveLastProcessedBlock(0x13)
+	mockStorage.EXPECT().SaveLastProcessedBlock(0x14)
+	parser.pollOnce()
+	assert.Equal(t, 0x14, parser.currentBlock)
+}
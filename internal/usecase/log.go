@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"math/big"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"strings"
+)
+
+// topicToAddress 取出 32 bytes topic 的後 20 bytes 作為地址
+func topicToAddress(topic string) (string, bool) {
+	hex := strings.TrimPrefix(strings.ToLower(topic), "0x")
+	if len(hex) != 64 {
+		return "", false
+	}
+	return "0x" + hex[24:], true
+}
+
+// dataToBigInt 將 ABI 編碼的 uint256 轉為 BigInt
+func dataToBigInt(data string) (domain.BigInt, bool) {
+	hex := strings.TrimPrefix(data, "0x")
+	if len(hex) != 64 {
+		return domain.BigInt{}, false
+	}
+	n, ok := new(big.Int).SetString(hex, 16)
+	if !ok {
+		return domain.BigInt{}, false
+	}
+	return domain.NewBigInt(n), true
+}
+
+// dataWords 將 ABI 編碼的 data 切成 32 bytes 一組的十六進位字串
+func dataWords(data string) ([]string, bool) {
+	hex := strings.TrimPrefix(data, "0x")
+	if len(hex)%64 != 0 {
+		return nil, false
+	}
+	words := make([]string, 0, len(hex)/64)
+	for i := 0; i < len(hex); i += 64 {
+		words = append(words, hex[i:i+64])
+	}
+	return words, true
+}
+
+// wordToInt 將 32 bytes word 轉為 int，用於解析 ABI 的 offset 與陣列長度
+func wordToInt(word string, limit int) (int, bool) {
+	n, ok := new(big.Int).SetString(word, 16)
+	if !ok || !n.IsInt64() || n.Int64() > int64(limit) {
+		return 0, false
+	}
+	return int(n.Int64()), true
+}
+
+// decodeUintArray 依 offset（以 bytes 為單位）解析 ABI 編碼的 uint256[]
+func decodeUintArray(words []string, offsetWord string) ([]domain.BigInt, bool) {
+	offset, ok := wordToInt(offsetWord, len(words)*32)
+	if !ok || offset%32 != 0 || offset/32 >= len(words) {
+		return nil, false
+	}
+	start := offset / 32
+	length, ok := wordToInt(words[start], len(words)-start-1)
+	if !ok {
+		return nil, false
+	}
+	values := make([]domain.BigInt, 0, length)
+	for _, word := range words[start+1 : start+1+length] {
+		value, _ := dataToBigInt(word)
+		values = append(values, value)
+	}
+	return values, true
+}
+
+// decodeTokenTransfer 解析 ERC-20 Transfer 事件
+// ERC-721 的 Transfer 與 ERC-20 共用同一個 topic0，但 tokenId 為 indexed，因此以 topics 數量區分
+func decodeTokenTransfer(log repository.Log) (repository.TokenTransfer, bool) {
+	if log.Removed || len(log.Topics) != 3 || log.Topics[0] != domain.TransferEventTopic {
+		return repository.TokenTransfer{}, false
+	}
+	from, ok := topicToAddress(log.Topics[1].String())
+	if !ok {
+		return repository.TokenTransfer{}, false
+	}
+	to, ok := topicToAddress(log.Topics[2].String())
+	if !ok {
+		return repository.TokenTransfer{}, false
+	}
+	amount, ok := dataToBigInt(log.Data.String())
+	if !ok {
+		return repository.TokenTransfer{}, false
+	}
+
+	return repository.TokenTransfer{
+		TxHash:      log.TransactionHash.String(),
+		BlockHash:   log.BlockHash.String(),
+		BlockNumber: log.BlockNumber.String(),
+		LogIndex:    log.LogIndex.String(),
+		Contract:    log.Address.String(),
+		From:        from,
+		To:          to,
+		Amount:      amount,
+	}, true
+}
+
+// decodeNFTTransfers 解析 ERC-721 Transfer 與 ERC-1155 TransferSingle / TransferBatch 事件
+// TransferBatch 會依序展開為多筆轉移紀錄，無法解析的事件返回 nil
+func decodeNFTTransfers(log repository.Log) []repository.NFTTransfer {
+	if log.Removed || len(log.Topics) != 4 {
+		return nil
+	}
+	addresses := make([]string, 0, 3)
+	for _, topic := range log.Topics[1:] {
+		address, ok := topicToAddress(topic.String())
+		if !ok {
+			return nil
+		}
+		addresses = append(addresses, address)
+	}
+	base := repository.NFTTransfer{
+		TxHash:      log.TransactionHash.String(),
+		BlockHash:   log.BlockHash.String(),
+		BlockNumber: log.BlockNumber.String(),
+		LogIndex:    log.LogIndex.String(),
+		Contract:    log.Address.String(),
+	}
+
+	switch log.Topics[0] {
+	case domain.TransferEventTopic:
+		// ERC-721: Transfer(from indexed, to indexed, tokenId indexed)
+		tokenID, ok := dataToBigInt(log.Topics[3].String())
+		if !ok {
+			return nil
+		}
+		base.Standard = domain.StandardERC721
+		base.From = addresses[0]
+		base.To = addresses[1]
+		base.TokenID = tokenID
+		base.Amount = domain.BigIntFromUint64(1)
+		return []repository.NFTTransfer{base}
+	case domain.TransferSingleEventTopic:
+		// ERC-1155: TransferSingle(operator indexed, from indexed, to indexed, id, value)
+		words, ok := dataWords(log.Data.String())
+		if !ok || len(words) != 2 {
+			return nil
+		}
+		base.Standard = domain.StandardERC1155
+		base.Operator = addresses[0]
+		base.From = addresses[1]
+		base.To = addresses[2]
+		base.TokenID, _ = dataToBigInt(words[0])
+		base.Amount, _ = dataToBigInt(words[1])
+		return []repository.NFTTransfer{base}
+	case domain.TransferBatchEventTopic:
+		// ERC-1155: TransferBatch(operator indexed, from indexed, to indexed, ids[], values[])
+		words, ok := dataWords(log.Data.String())
+		if !ok || len(words) < 2 {
+			return nil
+		}
+		ids, ok := decodeUintArray(words, words[0])
+		if !ok {
+			return nil
+		}
+		values, ok := decodeUintArray(words, words[1])
+		if !ok || len(ids) != len(values) {
+			return nil
+		}
+		base.Standard = domain.StandardERC1155
+		base.Operator = addresses[0]
+		base.From = addresses[1]
+		base.To = addresses[2]
+		reply := make([]repository.NFTTransfer, 0, len(ids))
+		for i := range ids {
+			transfer := base
+			transfer.BatchIndex = i
+			transfer.TokenID = ids[i]
+			transfer.Amount = values[i]
+			reply = append(reply, transfer)
+		}
+		return reply
+	}
+
+	return nil
+}
+
+// toUsecaseTokenTransfer 將 repository 的代幣轉帳轉為 usecase 層的結構
+func toUsecaseTokenTransfer(item repository.TokenTransfer) usecase.TokenTransfer {
+	return usecase.TokenTransfer{
+		TxHash:      item.TxHash,
+		BlockHash:   item.BlockHash,
+		BlockNumber: item.BlockNumber,
+		LogIndex:    item.LogIndex,
+		Contract:    item.Contract,
+		From:        item.From,
+		To:          item.To,
+		Amount:      item.Amount,
+	}
+}
+
+// toRepositoryTokenTransfer 將 pipeline 處理後的代幣轉帳轉回 repository 的結構
+func toRepositoryTokenTransfer(item usecase.TokenTransfer) repository.TokenTransfer {
+	return repository.TokenTransfer{
+		TxHash:      item.TxHash,
+		BlockHash:   item.BlockHash,
+		BlockNumber: item.BlockNumber,
+		LogIndex:    item.LogIndex,
+		Contract:    item.Contract,
+		From:        item.From,
+		To:          item.To,
+		Amount:      item.Amount,
+	}
+}
+
+// toUsecaseNFTTransfer 將 repository 的 NFT 轉移轉為 usecase 層的結構
+func toUsecaseNFTTransfer(item repository.NFTTransfer) usecase.NFTTransfer {
+	return usecase.NFTTransfer{
+		TxHash:      item.TxHash,
+		BlockHash:   item.BlockHash,
+		BlockNumber: item.BlockNumber,
+		LogIndex:    item.LogIndex,
+		BatchIndex:  item.BatchIndex,
+		Standard:    item.Standard,
+		Contract:    item.Contract,
+		Operator:    item.Operator,
+		From:        item.From,
+		To:          item.To,
+		TokenID:     item.TokenID,
+		Amount:      item.Amount,
+	}
+}
+
+// toRepositoryNFTTransfer 將 pipeline 處理後的 NFT 轉移轉回 repository 的結構
+func toRepositoryNFTTransfer(item usecase.NFTTransfer) repository.NFTTransfer {
+	return repository.NFTTransfer{
+		TxHash:      item.TxHash,
+		BlockHash:   item.BlockHash,
+		BlockNumber: item.BlockNumber,
+		LogIndex:    item.LogIndex,
+		BatchIndex:  item.BatchIndex,
+		Standard:    item.Standard,
+		Contract:    item.Contract,
+		Operator:    item.Operator,
+		From:        item.From,
+		To:          item.To,
+		TokenID:     item.TokenID,
+		Amount:      item.Amount,
+	}
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"strings"
+	"testing"
+)
+
+// hexBytes 將十六進位字串轉為 domain.Bytes，僅供測試使用
+func hexBytes(s string) domain.Bytes {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestDecodeNFTTransfers(t *testing.T) {
+	operator := domain.Hash("0x000000000000000000000000cccccccccccccccccccccccccccccccccccccccc")
+	from := domain.Hash("0x000000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	to := domain.Hash("0x000000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	nft := domain.Address("0xdddddddddddddddddddddddddddddddddddddddd")
+	txHash := domain.Hash("0x1111111111111111111111111111111111111111111111111111111111111111")
+
+	tests := []struct {
+		name     string
+		log      repository.Log
+		expected []repository.NFTTransfer
+	}{
+		{
+			name: "ERC-721 Transfer",
+			log: repository.Log{
+				Address:         nft,
+				Topics:          []domain.Hash{domain.TransferEventTopic, from, to, "0x00000000000000000000000000000000000000000000000000000000000000ff"},
+				BlockNumber:     0x10,
+				TransactionHash: txHash,
+				LogIndex:        1,
+			},
+			expected: []repository.NFTTransfer{
+				{
+					TxHash:      txHash.String(),
+					BlockNumber: "0x10",
+					LogIndex:    "0x1",
+					Standard:    domain.StandardERC721,
+					Contract:    nft.String(),
+					From:        "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					To:          "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+					TokenID:     domain.BigIntFromUint64(0xff),
+					Amount:      domain.BigIntFromUint64(0x1),
+				},
+			},
+		},
+		{
+			name: "ERC-1155 TransferSingle",
+			log: repository.Log{
+				Address: nft,
+				Topics:  []domain.Hash{domain.TransferSingleEventTopic, operator, from, to},
+				Data: hexBytes("0000000000000000000000000000000000000000000000000000000000000007" +
+					"000000000000000000000000000000000000000000000000000000000000000a"),
+				BlockNumber:     0x10,
+				TransactionHash: txHash,
+			},
+			expected: []repository.NFTTransfer{
+				{
+					TxHash:      txHash.String(),
+					BlockNumber: "0x10",
+					LogIndex:    "0x0",
+					Standard:    domain.StandardERC1155,
+					Contract:    nft.String(),
+					Operator:    "0xcccccccccccccccccccccccccccccccccccccccc",
+					From:        "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					To:          "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+					TokenID:     domain.BigIntFromUint64(0x7),
+					Amount:      domain.BigIntFromUint64(0xa),
+				},
+			},
+		},
+		{
+			name: "ERC-1155 TransferBatch",
+			log: repository.Log{
+				Address: nft,
+				Topics:  []domain.Hash{domain.TransferBatchEventTopic, operator, from, to},
+				Data: hexBytes("0000000000000000000000000000000000000000000000000000000000000040" +
+					"00000000000000000000000000000000000000000000000000000000000000a0" +
+					"0000000000000000000000000000000000000000000000000000000000000002" +
+					"0000000000000000000000000000000000000000000000000000000000000001" +
+					"0000000000000000000000000000000000000000000000000000000000000002" +
+					"0000000000000000000000000000000000000000000000000000000000000002" +
+					"0000000000000000000000000000000000000000000000000000000000000005" +
+					"0000000000000000000000000000000000000000000000000000000000000006"),
+				BlockNumber:     0x10,
+				TransactionHash: txHash,
+			},
+			expected: []repository.NFTTransfer{
+				{
+					TxHash:      txHash.String(),
+					BlockNumber: "0x10",
+					LogIndex:    "0x0",
+					Standard:    domain.StandardERC1155,
+					Contract:    nft.String(),
+					Operator:    "0xcccccccccccccccccccccccccccccccccccccccc",
+					From:        "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					To:          "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+					TokenID:     domain.BigIntFromUint64(0x1),
+					Amount:      domain.BigIntFromUint64(0x5),
+				},
+				{
+					TxHash:      txHash.String(),
+					BlockNumber: "0x10",
+					LogIndex:    "0x0",
+					Standard:    domain.StandardERC1155,
+					Contract:    nft.String(),
+					Operator:    "0xcccccccccccccccccccccccccccccccccccccccc",
+					From:        "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					To:          "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+					TokenID:     domain.BigIntFromUint64(0x2),
+					Amount:      domain.BigIntFromUint64(0x6),
+					BatchIndex:  1,
+				},
+			},
+		},
+		{
+			name: "TransferBatch with out of range offset",
+			log: repository.Log{
+				Topics: []domain.Hash{domain.TransferBatchEventTopic, operator, from, to},
+				Data: hexBytes("0000000000000000000000000000000000000000000000000000000000000400" +
+					"0000000000000000000000000000000000000000000000000000000000000040"),
+			},
+			expected: nil,
+		},
+		{
+			name: "Removed log is ignored",
+			log: repository.Log{
+				Topics:  []domain.Hash{domain.TransferEventTopic, from, to, from},
+				Removed: true,
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, decodeNFTTransfers(tt.log))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"parse_server/internal/domain/repository"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultLogRange eth_getLogs 每次查詢的預設區塊數上限，多數節點服務商限制為 2000 至 10000 個區塊
+const defaultLogRange = 2000
+
+// logRangeGrowAfter 以記住的範圍連續成功查詢的次數，達到後嘗試加倍範圍，節點的限制會隨結果數量變化
+const logRangeGrowAfter = 10
+
+// logRangeErrorMarkers 節點拒絕過大的區塊範圍或過多結果時錯誤訊息中的關鍵字，各服務商的用詞不同
+// 不使用單獨的 "limit" 或 "exceed"，限流的錯誤（例如 Infura 的 project ID request rate exceeded）也會用到
+var logRangeErrorMarkers = []string{
+	"too many", "more than", "block range", "range too", "range is too", "limited to", "response size", "timeout", "timed out",
+}
+
+// rateLimitCodes 代表請求頻率限制的錯誤代碼
+var rateLimitCodes = []int{429, -32029}
+
+// rateLimitMarkers 請求頻率限制的錯誤，與範圍無關，切分範圍只會送出更多請求
+var rateLimitMarkers = []string{
+	"rate limit", "too many requests", "request rate", "rate exceeded", "request count", "compute units", "credits",
+}
+
+// suggestedLogRangePattern 部分服務商（例如 Alchemy）在錯誤訊息中建議可用的範圍，例如 [0x10, 0x2f]
+var suggestedLogRangePattern = regexp.MustCompile(`\[(0x[0-9a-fA-F]+),\s*(0x[0-9a-fA-F]+)\]`)
+
+type LogRangeParam struct {
+	EthClient repository.ETHClient
+	// MaxRange 每次查詢的最大區塊數，為 0 時使用預設值
+	MaxRange int
+}
+
+// LogRangeFetcher 以 eth_getLogs 分段查詢區塊範圍內的事件，節點拒絕過大的範圍或過多的結果時切分範圍重試
+// 每個節點可用的範圍會被記住，之後的查詢直接使用；ETHClient 實作 EndpointNamer 時依節點分開記錄
+type LogRangeFetcher struct {
+	ethClient repository.ETHClient
+	maxRange  int
+
+	mu     sync.Mutex
+	ranges map[string]*logRange
+}
+
+// logRange 節點目前使用的範圍與以此範圍連續成功的次數
+type logRange struct {
+	size      int
+	successes int
+}
+
+func NewLogRangeFetcher(param LogRangeParam) *LogRangeFetcher {
+	maxRange := param.MaxRange
+	if maxRange <= 0 {
+		maxRange = defaultLogRange
+	}
+	return &LogRangeFetcher{
+		ethClient: param.EthClient,
+		maxRange:  maxRange,
+		ranges:    make(map[string]*logRange),
+	}
+}
+
+// GetLogs 查詢 from 至 to（包含）的事件，依區塊順序合併各段的結果
+func (f *LogRangeFetcher) GetLogs(filter repository.LogFilter, from, to int) ([]repository.Log, error) {
+	var logs []repository.Log
+	err := f.FetchLogs(filter, from, to, func(_ int, chunk []repository.Log) bool {
+		logs = append(logs, chunk...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// FetchLogs 依區塊順序分段查詢 from 至 to（包含）的事件，每段的結果與最後的區塊交給 commit，commit 返回 false 時停止
+// 查詢長時間的歷史時不需要將所有結果留在記憶體；單一區塊仍被拒絕或發生其他錯誤時返回錯誤，已交給 commit 的結果不受影響
+func (f *LogRangeFetcher) FetchLogs(filter repository.LogFilter, from, to int, commit func(end int, logs []repository.Log) bool) error {
+	endpoint := f.endpoint()
+	for start := from; start <= to; {
+		size := f.rangeSize(endpoint)
+		end := min(to, start+size-1)
+		filter.FromBlock = fmt.Sprintf("0x%x", start)
+		filter.ToBlock = fmt.Sprintf("0x%x", end)
+
+		logs, err := f.getLogs(filter)
+		if err != nil {
+			if end == start || !isLogRangeError(err) {
+				return fmt.Errorf("eth_getLogs blocks %d-%d: %w", start, end, err)
+			}
+			f.shrink(endpoint, end-start+1, suggestedLogRange(err))
+			continue
+		}
+
+		f.succeeded(endpoint, end-start+1)
+		if !commit(end, logs) {
+			return nil
+		}
+		start = end + 1
+	}
+	return nil
+}
+
+// RangeSize 目前節點記住的範圍
+func (f *LogRangeFetcher) RangeSize() int {
+	return f.rangeSize(f.endpoint())
+}
+
+func (f *LogRangeFetcher) getLogs(filter repository.LogFilter) ([]repository.Log, error) {
+	result, err := f.ethClient.CallEthereum("eth_getLogs", []any{filter})
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResponse repository.LogResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return nil, err
+	}
+	if rpcResponse.Error != nil {
+		return nil, rpcResponse.Error
+	}
+	return rpcResponse.Result, nil
+}
+
+func (f *LogRangeFetcher) endpoint() string {
+	if named, ok := f.ethClient.(repository.EndpointNamer); ok {
+		return named.Endpoint()
+	}
+	return ""
+}
+
+// state 返回節點的範圍紀錄，尚未記錄時以最大範圍開始，呼叫前須持有 mu
+func (f *LogRangeFetcher) state(endpoint string) *logRange {
+	r, ok := f.ranges[endpoint]
+	if !ok {
+		r = &logRange{size: f.maxRange}
+		f.ranges[endpoint] = r
+	}
+	return r
+}
+
+func (f *LogRangeFetcher) rangeSize(endpoint string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state(endpoint).size
+}
+
+// shrink 範圍被拒絕時改用節點建議的範圍，沒有建議時減半；同時查詢時保留較小的範圍
+func (f *LogRangeFetcher) shrink(endpoint string, attempted, suggested int) {
+	size := attempted / 2
+	if suggested > 0 && suggested < attempted {
+		size = suggested
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := f.state(endpoint)
+	r.size = max(min(r.size, size), 1)
+	r.successes = 0
+}
+
+// succeeded 以完整的範圍查詢成功時累計次數，連續成功 logRangeGrowAfter 次後加倍範圍，最多至 maxRange
+func (f *LogRangeFetcher) succeeded(endpoint string, attempted int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r := f.state(endpoint)
+	if attempted < r.size || r.size >= f.maxRange {
+		return
+	}
+	r.successes++
+	if r.successes >= logRangeGrowAfter {
+		r.size = min(2*r.size, f.maxRange)
+		r.successes = 0
+	}
+}
+
+// isLogRangeError 節點以 RPC 錯誤拒絕過大的範圍或過多的結果，連線錯誤與頻率限制等其他錯誤切分範圍也無法解決
+// 先以代碼與訊息排除頻率限制；-32005 同時用於限流與結果過多，因此只依訊息判斷
+func isLogRangeError(err error) bool {
+	var rpcErr *repository.RPCError
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	if slices.Contains(rateLimitCodes, rpcErr.Code) {
+		return false
+	}
+	message := strings.ToLower(rpcErr.Message)
+	for _, marker := range rateLimitMarkers {
+		if strings.Contains(message, marker) {
+			return false
+		}
+	}
+	for _, marker := range logRangeErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestedLogRange 取出錯誤訊息中建議的範圍大小，沒有建議時返回 0
+func suggestedLogRange(err error) int {
+	var rpcErr *repository.RPCError
+	if !errors.As(err, &rpcErr) {
+		return 0
+	}
+	match := suggestedLogRangePattern.FindStringSubmatch(rpcErr.Message)
+	if match == nil {
+		return 0
+	}
+	from, errFrom := strconv.ParseInt(match[1][2:], 16, 64)
+	to, errTo := strconv.ParseInt(match[2][2:], 16, 64)
+	if errFrom != nil || errTo != nil || to < from {
+		return 0
+	}
+	return int(to-from) + 1
+}
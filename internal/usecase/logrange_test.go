@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain/repository"
+	"strconv"
+	"strings"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+)
+
+// namedETHClient 實作 EndpointNamer 的測試節點
+type namedETHClient struct {
+	*repoMock.MockETHClient
+	endpoint string
+}
+
+func (c namedETHClient) Endpoint() string {
+	return c.endpoint
+}
+
+// logRangeNode 模擬限制區塊範圍的節點，每個區塊返回一筆事件，記錄收到的範圍
+type logRangeNode struct {
+	limit    int
+	errorMsg func(from, to int) string
+	requests [][2]int
+}
+
+func (n *logRangeNode) call(_ string, params []any) ([]byte, error) {
+	filter := params[0].(repository.LogFilter)
+	from, _ := strconv.ParseInt(filter.FromBlock[2:], 16, 64)
+	to, _ := strconv.ParseInt(filter.ToBlock[2:], 16, 64)
+	n.requests = append(n.requests, [2]int{int(from), int(to)})
+
+	if int(to-from)+1 > n.limit {
+		message, _ := json.Marshal(n.errorMsg(int(from), int(to)))
+		return []byte(fmt.Sprintf(`{"error": {"code": -32602, "message": %s}}`, message)), nil
+	}
+	logs := make([]string, 0, to-from+1)
+	for block := from; block <= to; block++ {
+		logs = append(logs, fmt.Sprintf(`{"address": "%s", "topics": [], "data": "0x", "blockNumber": "0x%x", "logIndex": "0x0"}`, eventTestContract, block))
+	}
+	return []byte(fmt.Sprintf(`{"result": [%s]}`, strings.Join(logs, ","))), nil
+}
+
+func tooManyResults(int, int) string {
+	return "query returned more than 10000 results"
+}
+
+func logBlocks(logs []repository.Log) []int {
+	blocks := make([]int, 0, len(logs))
+	for _, log := range logs {
+		blocks = append(blocks, int(log.BlockNumber))
+	}
+	return blocks
+}
+
+func TestLogRangeFetcher_SplitsRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	node := &logRangeNode{limit: 3, errorMsg: tooManyResults}
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).DoAndReturn(node.call).AnyTimes()
+
+	fetcher := NewLogRangeFetcher(LogRangeParam{EthClient: mockClient, MaxRange: 8})
+	logs, err := fetcher.GetLogs(repository.LogFilter{Address: []string{eventTestContract}}, 0, 9)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, logBlocks(logs))
+	// 8 與 4 被拒絕後以 2 查詢，之後沿用記住的範圍
+	assert.Equal(t, [][2]int{{0, 7}, {0, 3}, {0, 1}, {2, 3}, {4, 5}, {6, 7}, {8, 9}}, node.requests)
+	assert.Equal(t, 2, fetcher.RangeSize())
+
+	// 下一次查詢直接使用記住的範圍
+	node.requests = nil
+	_, err = fetcher.GetLogs(repository.LogFilter{}, 10, 13)
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]int{{10, 11}, {12, 13}}, node.requests)
+}
+
+func TestLogRangeFetcher_SuggestedRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	node := &logRangeNode{limit: 3, errorMsg: func(from, _ int) string {
+		return fmt.Sprintf("Log response size exceeded. this block range should work: [0x%x, 0x%x]", from, from+2)
+	}}
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).DoAndReturn(node.call).AnyTimes()
+
+	fetcher := NewLogRangeFetcher(LogRangeParam{EthClient: mockClient, MaxRange: 100})
+	logs, err := fetcher.GetLogs(repository.LogFilter{}, 0, 5)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 6)
+	assert.Equal(t, [][2]int{{0, 5}, {0, 2}, {3, 5}}, node.requests)
+	assert.Equal(t, 3, fetcher.RangeSize())
+}
+
+func TestLogRangeFetcher_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		err      error
+		requests int
+	}{
+		{
+			name:     "node error",
+			response: `{"error": {"code": -32000, "message": "header not found"}}`,
+			requests: 1,
+		},
+		{
+			name:     "rate limit",
+			response: `{"error": {"code": -32005, "message": "daily request count exceeded, request rate limited"}}`,
+			requests: 1,
+		},
+		{
+			name:     "project rate limit",
+			response: `{"error": {"code": -32005, "message": "project ID request rate exceeded"}}`,
+			requests: 1,
+		},
+		{
+			name:     "too many requests",
+			response: `{"error": {"code": 429, "message": "Your app has exceeded its compute units per second capacity"}}`,
+			requests: 1,
+		},
+		{
+			name: "connection error",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// 與範圍無關的錯誤不切分範圍
+			mockClient := repoMock.NewMockETHClient(ctrl)
+			mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return([]byte(tt.response), tt.err)
+
+			fetcher := NewLogRangeFetcher(LogRangeParam{EthClient: mockClient, MaxRange: 8})
+			logs, err := fetcher.GetLogs(repository.LogFilter{}, 0, 9)
+			assert.ErrorContains(t, err, "eth_getLogs blocks 0-7")
+			assert.Nil(t, logs)
+			assert.Equal(t, 8, fetcher.RangeSize())
+		})
+	}
+}
+
+func TestLogRangeFetcher_SingleBlockRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	node := &logRangeNode{limit: 0, errorMsg: tooManyResults}
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).DoAndReturn(node.call).Times(3)
+
+	fetcher := NewLogRangeFetcher(LogRangeParam{EthClient: mockClient, MaxRange: 4})
+	_, err := fetcher.GetLogs(repository.LogFilter{}, 5, 9)
+	var rpcErr *repository.RPCError
+	assert.ErrorAs(t, err, &rpcErr)
+	assert.ErrorContains(t, err, "eth_getLogs blocks 5-5")
+	assert.Equal(t, [][2]int{{5, 8}, {5, 6}, {5, 5}}, node.requests)
+}
+
+func TestLogRangeFetcher_FetchLogsStops(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	node := &logRangeNode{limit: 2}
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).DoAndReturn(node.call).Times(2)
+
+	fetcher := NewLogRangeFetcher(LogRangeParam{EthClient: mockClient, MaxRange: 2})
+	var ends []int
+	err := fetcher.FetchLogs(repository.LogFilter{}, 0, 9, func(end int, logs []repository.Log) bool {
+		ends = append(ends, end)
+		return len(ends) < 2
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, ends)
+}
+
+func TestLogRangeFetcher_GrowsAfterSuccesses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	node := &logRangeNode{limit: 2, errorMsg: tooManyResults}
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).DoAndReturn(node.call).AnyTimes()
+
+	fetcher := NewLogRangeFetcher(LogRangeParam{EthClient: mockClient, MaxRange: 4})
+	_, err := fetcher.GetLogs(repository.LogFilter{}, 0, 17)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fetcher.RangeSize())
+
+	// 節點的限制放寬後，連續成功的次數達到門檻時加倍範圍
+	node.limit = 4
+	_, err = fetcher.GetLogs(repository.LogFilter{}, 18, 21)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, fetcher.RangeSize())
+
+	// 不完整的最後一段不計入
+	node.requests = nil
+	_, err = fetcher.GetLogs(repository.LogFilter{}, 22, 27)
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]int{{22, 25}, {26, 27}}, node.requests)
+}
+
+func TestLogRangeFetcher_PerEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	strict := &logRangeNode{limit: 1, errorMsg: tooManyResults}
+	loose := &logRangeNode{limit: 8, errorMsg: tooManyResults}
+	strictClient := repoMock.NewMockETHClient(ctrl)
+	strictClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).DoAndReturn(strict.call).AnyTimes()
+	looseClient := repoMock.NewMockETHClient(ctrl)
+	looseClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).DoAndReturn(loose.call).AnyTimes()
+
+	fetcher := NewLogRangeFetcher(LogRangeParam{EthClient: namedETHClient{strictClient, "https://strict.example"}, MaxRange: 8})
+	_, err := fetcher.GetLogs(repository.LogFilter{}, 0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fetcher.RangeSize())
+
+	// 共用紀錄時，另一個節點從最大範圍開始
+	fetcher.ethClient = namedETHClient{looseClient, "https://loose.example"}
+	_, err = fetcher.GetLogs(repository.LogFilter{}, 0, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]int{{0, 7}}, loose.requests)
+	assert.Equal(t, 8, fetcher.RangeSize())
+
+	fetcher.ethClient = namedETHClient{strictClient, "https://strict.example"}
+	assert.Equal(t, 1, fetcher.RangeSize())
+}
+
+func TestIsLogRangeError(t *testing.T) {
+	assert.True(t, isLogRangeError(&repository.RPCError{Code: -32005, Message: "query returned more than 10000 results"}))
+	assert.True(t, isLogRangeError(&repository.RPCError{Code: -32602, Message: "Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"}))
+	assert.True(t, isLogRangeError(&repository.RPCError{Code: -32602, Message: "eth_getLogs is limited to a 10,000 range"}))
+	assert.True(t, isLogRangeError(&repository.RPCError{Code: -32000, Message: "query timeout exceeded"}))
+	assert.False(t, isLogRangeError(&repository.RPCError{Code: -32005, Message: "project ID request rate exceeded"}))
+	assert.False(t, isLogRangeError(&repository.RPCError{Code: -32005, Message: "daily request count exceeded, request rate limited"}))
+	assert.False(t, isLogRangeError(&repository.RPCError{Code: 429, Message: "Too Many Requests"}))
+	assert.False(t, isLogRangeError(&repository.RPCError{Code: -32000, Message: "gas limit exceeded"}))
+	assert.False(t, isLogRangeError(errors.New("connection refused")))
+}
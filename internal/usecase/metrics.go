@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"parse_server/internal/domain/usecase"
+	"sync/atomic"
+)
+
+// parserMetrics Parser 的計數器，輪詢與 HTTP handler 會同時存取，因此使用 atomic
+type parserMetrics struct {
+	bloomChecks         atomic.Uint64
+	bloomSkips          atomic.Uint64
+	bloomMatches        atomic.Uint64
+	bloomFalsePositives atomic.Uint64
+}
+
+// GetMetrics 取得 Parser 啟動後累計的計數
+func (p *EthereumParser) GetMetrics() usecase.Metrics {
+	return usecase.Metrics{
+		BloomChecks:         p.metrics.bloomChecks.Load(),
+		BloomSkips:          p.metrics.bloomSkips.Load(),
+		BloomMatches:        p.metrics.bloomMatches.Load(),
+		BloomFalsePositives: p.metrics.bloomFalsePositives.Load(),
+	}
+}
@@ -9,9 +9,69 @@ import (
 type ConsoleNotification struct{}
 
 func (n *ConsoleNotification) Notify(address string, tx usecase.Transaction) {
+	// 能以 ABI 解碼時顯示易讀的呼叫內容，例如 approve(spender: 0x..., amount: 100)
+	if tx.Method != nil {
+		fmt.Printf("Notification - New transaction for address %s: %s %s\n", address, tx.Hash, tx.Method)
+		return
+	}
 	fmt.Printf("Notification - New transaction for address %s: %+v\n", address, tx)
 }
 
+func (n *ConsoleNotification) NotifyTokenTransfer(address string, transfer usecase.TokenTransfer) {
+	fmt.Printf("Notification - New token transfer for address %s: %+v\n", address, transfer)
+}
+
+func (n *ConsoleNotification) NotifyNFTTransfer(address string, transfer usecase.NFTTransfer) {
+	fmt.Printf("Notification - New NFT transfer for address %s: %+v\n", address, transfer)
+}
+
+func (n *ConsoleNotification) NotifyInternalTransaction(address string, tx usecase.InternalTransaction) {
+	fmt.Printf("Notification - New internal transaction for address %s: %+v\n", address, tx)
+}
+
+func (n *ConsoleNotification) NotifyENSChange(address string, change usecase.ENSChange) {
+	fmt.Printf("Notification - ENS name %s now resolves to %s (was %s)\n", change.Name, address, change.OldAddress)
+}
+
+func (n *ConsoleNotification) NotifyContractEvent(subscriptionID string, event usecase.ContractEvent) {
+	if event.Event != nil {
+		fmt.Printf("Notification - New contract event for subscription %s: %s %s\n", subscriptionID, event.TxHash, event.Event)
+		return
+	}
+	fmt.Printf("Notification - New contract event for subscription %s: %+v\n", subscriptionID, event)
+}
+
+func (n *ConsoleNotification) NotifyWithdrawal(address string, withdrawal usecase.Withdrawal) {
+	fmt.Printf("Notification - New validator withdrawal for address %s: %+v\n", address, withdrawal)
+}
+
+func (n *ConsoleNotification) NotifyBlockReward(address string, reward usecase.BlockReward) {
+	fmt.Printf("Notification - New block reward for address %s: %+v\n", address, reward)
+}
+
+func (n *ConsoleNotification) NotifyBalanceDiscrepancy(address string, snapshot usecase.BalanceSnapshot) {
+	fmt.Printf("Notification - Balance discrepancy for address %s at block %s: expected %s, actual %s\n",
+		address, snapshot.BlockNumber, snapshot.Expected, snapshot.Balance)
+}
+
+func (n *ConsoleNotification) NotifyPendingTransaction(address string, tx usecase.PendingTransaction) {
+	fmt.Printf("Notification - Pending transaction %s for address %s: %+v\n", tx.Status, address, tx)
+}
+
 func MustNotification() usecase.Notification {
 	return &ConsoleNotification{}
 }
+
+// silentNotification 不發送任何通知，重新處理歷史區塊且不需要通知時使用
+type silentNotification struct{}
+
+func (silentNotification) Notify(string, usecase.Transaction)                            {}
+func (silentNotification) NotifyTokenTransfer(string, usecase.TokenTransfer)             {}
+func (silentNotification) NotifyNFTTransfer(string, usecase.NFTTransfer)                 {}
+func (silentNotification) NotifyInternalTransaction(string, usecase.InternalTransaction) {}
+func (silentNotification) NotifyENSChange(string, usecase.ENSChange)                     {}
+func (silentNotification) NotifyContractEvent(string, usecase.ContractEvent)             {}
+func (silentNotification) NotifyWithdrawal(string, usecase.Withdrawal)                   {}
+func (silentNotification) NotifyBlockReward(string, usecase.BlockReward)                 {}
+func (silentNotification) NotifyBalanceDiscrepancy(string, usecase.BalanceSnapshot)      {}
+func (silentNotification) NotifyPendingTransaction(string, usecase.PendingTransaction)   {}
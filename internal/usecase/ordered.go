@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"sync"
+)
+
+// fetchOrdered 以 parallelism 個 goroutine 同時執行 from 至 to 每個區塊的 fetch，並依區塊順序在呼叫端的 goroutine 執行 commit
+// 已開始下載但尚未 commit 的區塊最多 window 個，避免 commit 較慢時下載的結果無限累積；
+// commit 返回 false 時停止派發新的區塊，等待進行中的 fetch 結束後返回
+func fetchOrdered[T any](from, to, parallelism, window int, fetch func(blockNumber int) T, commit func(blockNumber int, result T) bool) {
+	parallelism = max(parallelism, 1)
+	window = max(window, parallelism)
+
+	type job struct {
+		blockNumber int
+		result      chan T
+	}
+	jobs := make(chan job)
+	// queue 依區塊順序排列已派發的工作，slots 限制尚未 commit 的工作數
+	queue := make(chan job, window)
+	slots := make(chan struct{}, window)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range parallelism {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				j.result <- fetch(j.blockNumber)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+		defer close(jobs)
+		for blockNumber := from; blockNumber <= to; blockNumber++ {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			j := job{blockNumber: blockNumber, result: make(chan T, 1)}
+			queue <- j
+			select {
+			case jobs <- j:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for j := range queue {
+		if !commit(j.blockNumber, <-j.result) {
+			close(done)
+			break
+		}
+		<-slots
+	}
+	wg.Wait()
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchOrdered(t *testing.T) {
+	var (
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+	)
+	var committed []int
+
+	// 較早的區塊下載較慢，commit 仍依區塊順序執行
+	fetchOrdered(1, 20, 4, 6,
+		func(blockNumber int) int {
+			n := inFlight.Add(1)
+			for {
+				current := maxInFlight.Load()
+				if n <= current || maxInFlight.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(time.Duration(20-blockNumber) * time.Millisecond / 4)
+			return blockNumber * 10
+		},
+		func(blockNumber int, result int) bool {
+			assert.Equal(t, blockNumber*10, result)
+			committed = append(committed, blockNumber)
+			inFlight.Add(-1)
+			return true
+		})
+
+	expected := make([]int, 0, 20)
+	for i := 1; i <= 20; i++ {
+		expected = append(expected, i)
+	}
+	assert.Equal(t, expected, committed)
+	// 已下載但尚未 commit 的區塊不超過 window
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(6))
+}
+
+func TestFetchOrdered_Stop(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		fetched []int
+	)
+	var committed []int
+
+	fetchOrdered(1, 100, 2, 4,
+		func(blockNumber int) int {
+			mu.Lock()
+			defer mu.Unlock()
+			fetched = append(fetched, blockNumber)
+			return blockNumber
+		},
+		func(blockNumber int, _ int) bool {
+			committed = append(committed, blockNumber)
+			return blockNumber < 3
+		})
+
+	// commit 返回 false 後不再派發新的區塊
+	assert.Equal(t, []int{1, 2, 3}, committed)
+	assert.LessOrEqual(t, len(fetched), 3+4)
+}
@@ -3,15 +3,49 @@ package usecase
 import (
 	"encoding/json"
 	"fmt"
+	"parse_server/internal/domain"
 	"parse_server/internal/domain/repository"
 	"parse_server/internal/domain/usecase"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// 落後多個區塊時的處理方式
+const (
+	defaultFetchParallelism = 4
+	// maxPollBatch 每次輪詢最多處理的區塊數，處理完後重新讀取訂閱並檢查最新區塊
+	maxPollBatch = 100
+)
+
 type EthereumParserParam struct {
 	Storage      repository.Storage
 	Notification usecase.Notification
 	EthClient    repository.ETHClient
+	// EnableTracing 啟用內部交易追蹤，節點不支援時會自動停用
+	EnableTracing bool
+	// ABIRegistry 用於解碼合約呼叫與事件，為 nil 時不解碼
+	ABIRegistry repository.ABIRegistry
+	// EnableBalanceTracking 記錄訂閱地址的餘額並與觀察到的活動對帳
+	EnableBalanceTracking bool
+	// EnableBloomFilter 先以區塊的 logs bloom 過濾，只有可能包含訂閱地址轉移事件的區塊才下載完整區塊與事件
+	EnableBloomFilter bool
+	// Leadership 執行多個實例時只有 leader 處理區塊與待處理交易，為 nil 時視為唯一的實例
+	Leadership usecase.Leadership
+	// PollSchedule 輪詢區塊的間隔，未設定時由區塊的 timestamp 學習出塊間隔
+	PollSchedule PollScheduleParam
+	// FetchParallelism 落後多個區塊時同時下載的區塊數，為 0 時使用預設值
+	FetchParallelism int
+	// FetchWindow 已下載但尚未保存的區塊數上限，為 0 時為 FetchParallelism 的兩倍
+	FetchWindow int
+	// Pipeline 保存與訂閱地址相關的交易、代幣轉帳、NFT 轉移與內部交易前依序執行的處理階段，可過濾、補充資料或丟棄紀錄
+	// 需包含 DefaultPipelineStages 的訂閱過濾與 ABI 解碼階段，為 nil 時只執行這兩個階段
+	Pipeline usecase.Pipeline
+	// TokenOverrides 本地設定的代幣資訊，有值的欄位優先於鏈上查詢的結果，合約地址須為標準格式
+	TokenOverrides []usecase.TokenMetadata
+	// MaxLogRange eth_getLogs 每次查詢的最大區塊數，節點拒絕時自動縮小，為 0 時使用預設值
+	MaxLogRange int
 }
 
 // EthereumParser 實現了 Parser interface
@@ -19,145 +53,757 @@ type EthereumParser struct {
 	storage      repository.Storage
 	notification usecase.Notification
 	ethClient    repository.ETHClient
+	abiRegistry  repository.ABIRegistry
 	currentBlock int
+	// leading 上次輪詢時是否為 leader，成為 leader 時由 Storage 記錄的進度繼續處理
+	leading bool
+	// tracer 目前使用的追蹤方法，節點不支援時降級，以 tracerMu 保護
+	tracer   string
+	tracerMu sync.Mutex
+	// trackBalances 啟用時每個有活動的區塊都會查詢餘額並對帳
+	trackBalances bool
+	// bloomFilter 啟用時以區塊的 logs bloom 決定是否處理區塊
+	bloomFilter bool
+	// lastHeader 最近一次取得的區塊 header，同一區塊不重複查詢，同時下載多個區塊時以 headerMu 保護
+	lastHeader *repository.BlockHeader
+	headerMu   sync.Mutex
+	metrics    parserMetrics
+	status     parserStatus
+	leadership usecase.Leadership
+	schedule   *pollSchedule
+	// fetchParallelism 與 fetchWindow 同時下載的區塊數與尚未保存的區塊數上限
+	fetchParallelism int
+	fetchWindow      int
+	pipeline         usecase.Pipeline
+	// tokenOverrides 以合約地址為鍵的本地代幣資訊，建立後不再修改
+	tokenOverrides map[string]usecase.TokenMetadata
+	// logs 查詢事件訂閱的事件，依節點記住可用的區塊範圍
+	logs *LogRangeFetcher
+
+	pendingFilterID string
+	lastDropCheck   time.Time
+	lastENSCheck    time.Time
+	// ensRefreshing 背景重新解析 ENS 名稱時為 true
+	ensRefreshing atomic.Bool
+
+	// blockReceiptsUnsupported 節點不支援 eth_getBlockReceipts 時改為逐筆查詢收據
+	blockReceiptsUnsupported atomic.Bool
 }
 
 func NewEthereumParser(param EthereumParserParam) usecase.Parser {
-	return &EthereumParser{
-		storage:      param.Storage,
-		notification: param.Notification,
-		ethClient:    param.EthClient,
-		currentBlock: 0,
+	tracer := tracerDisabled
+	if param.EnableTracing {
+		tracer = tracerCallTracer
+	}
+
+	p := &EthereumParser{
+		storage:       param.Storage,
+		notification:  param.Notification,
+		ethClient:     param.EthClient,
+		abiRegistry:   param.ABIRegistry,
+		currentBlock:  0,
+		tracer:        tracer,
+		trackBalances: param.EnableBalanceTracking,
+		bloomFilter:   param.EnableBloomFilter,
+		leadership:    param.Leadership,
+		schedule:      newPollSchedule(param.PollSchedule),
+		logs:          NewLogRangeFetcher(LogRangeParam{EthClient: param.EthClient, MaxRange: param.MaxLogRange}),
+	}
+	p.status.blockTime = p.schedule.blockTime
+	p.pipeline = param.Pipeline
+	if p.pipeline == nil {
+		p.pipeline = MustTransactionPipeline(DefaultPipelineStages(param.ABIRegistry)...)
+	}
+	p.tokenOverrides = make(map[string]usecase.TokenMetadata, len(param.TokenOverrides))
+	for _, override := range param.TokenOverrides {
+		override.Source = domain.TokenMetadataSourceOverride
+		p.tokenOverrides[override.Contract] = override
 	}
+	p.fetchParallelism = param.FetchParallelism
+	if p.fetchParallelism <= 0 {
+		p.fetchParallelism = defaultFetchParallelism
+	}
+	p.fetchWindow = param.FetchWindow
+	if p.fetchWindow <= 0 {
+		p.fetchWindow = 2 * p.fetchParallelism
+	}
+	return p
 }
 
-// fetchBlockTransactions 根據區塊號獲取區塊的交易
-func (p *EthereumParser) fetchBlockTransactions(blockNumber string) ([]repository.Transaction, error) {
+// fetchBlock 根據區塊號獲取包含完整交易的區塊
+func (p *EthereumParser) fetchBlock(blockNumber string) (repository.Block, error) {
 	result, err := p.ethClient.CallEthereum("eth_getBlockByNumber", []any{blockNumber, true})
 	if err != nil {
-		return nil, err
+		return repository.Block{}, err
 	}
 
 	var rpcResponse repository.BlockResult
 	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return repository.Block{}, err
+	}
+	if rpcResponse.Error != nil {
+		return repository.Block{}, rpcResponse.Error
+	}
+
+	return rpcResponse.Result, nil
+}
+
+// fetchBlockTransactions 根據區塊號獲取區塊的交易
+func (p *EthereumParser) fetchBlockTransactions(blockNumber string) ([]repository.Transaction, error) {
+	block, err := p.fetchBlock(blockNumber)
 	if err != nil {
 		return nil, err
 	}
-	reply := make([]repository.Transaction, 0, len(rpcResponse.Result.Transactions))
-	for _, item := range rpcResponse.Result.Transactions {
+
+	return blockTransactions(block), nil
+}
+
+// blockTransactions 將區塊中的交易轉為儲存格式
+func blockTransactions(block repository.Block) []repository.Transaction {
+	reply := make([]repository.Transaction, 0, len(block.Transactions))
+	for _, item := range block.Transactions {
 		to := ""
 		if item.To != nil {
-			to = *item.To
+			to = item.To.String()
 		}
-		reply = append(reply, repository.Transaction{
-			BlockHash:   rpcResponse.Result.Hash,
-			BlockNumber: rpcResponse.Result.Number,
-			From:        item.From,
+		tx := repository.Transaction{
+			Hash:        item.Hash.String(),
+			BlockHash:   block.Hash.String(),
+			BlockNumber: block.Number.String(),
+			From:        item.From.String(),
 			To:          to,
-			Value:       item.Value,
-		})
+			Value:       item.Value.BigInt,
+			GasPrice:    item.GasPrice.BigInt,
+			Nonce:       item.Nonce.String(),
+			Input:       item.Input,
+		}
+		applyTransactionType(&tx, item)
+		reply = append(reply, tx)
 	}
 
-	return reply, nil
+	return reply
 }
 
-// UpdateCurrentBlock 更新目前區塊
-func (p *EthereumParser) UpdateCurrentBlock() error {
-	result, err := p.ethClient.CallEthereum("eth_blockNumber", []any{})
+// fetchTransactionReceipt 根據交易哈希值取得交易收據，交易尚未上鏈時返回 nil
+func (p *EthereumParser) fetchTransactionReceipt(hash string) (*repository.Receipt, error) {
+	result, err := p.ethClient.CallEthereum("eth_getTransactionReceipt", []any{hash})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var rpcResponse repository.EthereumRPCResponse
+	var rpcResponse repository.ReceiptResult
 	err = json.Unmarshal(result, &rpcResponse)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if rpcResponse.Error != nil {
+		return nil, rpcResponse.Error
+	}
+
+	return rpcResponse.Result, nil
+}
+
+// applyReceipt 以交易收據補上實際使用的 gas 與手續費
+func (p *EthereumParser) applyReceipt(tx repository.Transaction) (repository.Transaction, error) {
+	receipt, err := p.fetchTransactionReceipt(tx.Hash)
+	if err != nil || receipt == nil {
+		return tx, err
+	}
+	return p.withReceipt(tx, receipt), nil
+}
+
+// withReceipt 以收據補上交易實際使用的 gas、手續費、事件與執行結果
+func (p *EthereumParser) withReceipt(tx repository.Transaction, receipt *repository.Receipt) repository.Transaction {
+	// effectiveGasPrice 為 EIP-1559 後實際支付的價格，舊節點沒有此欄位時沿用交易的 gasPrice
+	if receipt.EffectiveGasPrice != nil {
+		tx.GasPrice = receipt.EffectiveGasPrice.BigInt
+	}
+	tx.GasUsed = domain.BigIntFromUint64(uint64(receipt.GasUsed))
+	tx.Fee = transactionFee(&tx, receipt)
+	tx.Events = p.decodeEvents(receipt.Logs)
+	tx.Failed = receipt.Status != nil && *receipt.Status == 0
+	return tx
+}
+
+// fetchTransferLogs 根據區塊號透過 eth_getLogs 取得區塊內的 ERC-20 代幣轉帳與 ERC-721 / ERC-1155 NFT 轉移事件
+func (p *EthereumParser) fetchTransferLogs(blockNumber string) ([]repository.TokenTransfer, []repository.NFTTransfer, error) {
+	filter := repository.LogFilter{
+		FromBlock: blockNumber,
+		ToBlock:   blockNumber,
+		Topics: []any{[]string{
+			domain.TransferEventTopic,
+			domain.TransferSingleEventTopic,
+			domain.TransferBatchEventTopic,
+		}},
+	}
+	result, err := p.ethClient.CallEthereum("eth_getLogs", []any{filter})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// 將十六進制的區塊號轉為整數
-	var blockNumber int
-	_, err = fmt.Sscanf(rpcResponse.Result, "0x%x", &blockNumber)
+	var rpcResponse repository.LogResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokenTransfers := make([]repository.TokenTransfer, 0, len(rpcResponse.Result))
+	nftTransfers := make([]repository.NFTTransfer, 0)
+	for _, item := range rpcResponse.Result {
+		if transfer, ok := decodeTokenTransfer(item); ok {
+			tokenTransfers = append(tokenTransfers, transfer)
+			continue
+		}
+		nftTransfers = append(nftTransfers, decodeNFTTransfers(item)...)
+	}
+
+	return tokenTransfers, nftTransfers, nil
+}
+
+// UpdateCurrentBlock 更新目前區塊
+func (p *EthereumParser) UpdateCurrentBlock() error {
+	blockNumber, err := p.fetchBlockNumber()
 	if err != nil {
 		return err
 	}
+
 	p.currentBlock = blockNumber
+	p.setChainHead(blockNumber)
 	return nil
 }
 
+// fetchBlockNumber 取得鏈上最新的區塊號
+func (p *EthereumParser) fetchBlockNumber() (int, error) {
+	result, err := p.ethClient.CallEthereum("eth_blockNumber", []any{})
+	if err != nil {
+		return 0, err
+	}
+
+	// 區塊號在解碼時即轉為整數，格式錯誤會在此返回錯誤
+	var rpcResponse repository.BlockNumberResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return 0, err
+	}
+	if rpcResponse.Error != nil {
+		return 0, rpcResponse.Error
+	}
+
+	return int(rpcResponse.Result), nil
+}
+
 // GetCurrentBlock 取得當前區塊號
 func (p *EthereumParser) GetCurrentBlock() int {
 	return p.currentBlock
 }
 
-// Subscribe 訂閱地址
-func (p *EthereumParser) Subscribe(address string) bool {
-	p.storage.SubscribeAddress(address)
-	return true
-}
-
 // GetTransactions 取得指定地址的交易
 func (p *EthereumParser) GetTransactions(address string) []usecase.Transaction {
-	r := p.storage.GetTransactions(address)
+	r := p.storage.GetTransactions(strings.ToLower(address))
 	result := make([]usecase.Transaction, 0, len(r))
 	for _, item := range r {
-		result = append(result, usecase.Transaction{
-			BlockHash:   item.BlockHash,
-			BlockNumber: item.BlockNumber,
-			From:        item.From,
-			To:          item.To,
-			Value:       item.Value,
-		})
+		result = append(result, toUsecaseTransaction(item))
+	}
+
+	return result
+}
+
+// GetTokenTransfers 取得指定地址的代幣轉帳
+func (p *EthereumParser) GetTokenTransfers(address string) []usecase.TokenTransfer {
+	r := p.storage.GetTokenTransfers(strings.ToLower(address))
+	result := make([]usecase.TokenTransfer, 0, len(r))
+	for _, item := range r {
+		result = append(result, toUsecaseTokenTransfer(item))
+	}
+
+	return result
+}
+
+// GetNFTTransfers 取得指定地址的 NFT 轉移
+func (p *EthereumParser) GetNFTTransfers(address string) []usecase.NFTTransfer {
+	r := p.storage.GetNFTTransfers(strings.ToLower(address))
+	result := make([]usecase.NFTTransfer, 0, len(r))
+	for _, item := range r {
+		result = append(result, toUsecaseNFTTransfer(item))
+	}
+
+	return result
+}
+
+// GetInternalTransactions 取得指定地址的內部交易
+func (p *EthereumParser) GetInternalTransactions(address string) []usecase.InternalTransaction {
+	r := p.storage.GetInternalTransactions(strings.ToLower(address))
+	result := make([]usecase.InternalTransaction, 0, len(r))
+	for _, item := range r {
+		result = append(result, toUsecaseInternalTransaction(item))
+	}
+
+	return result
+}
+
+// GetWithdrawals 取得指定地址的驗證者提款
+func (p *EthereumParser) GetWithdrawals(address string) []usecase.Withdrawal {
+	r := p.storage.GetWithdrawals(strings.ToLower(address))
+	result := make([]usecase.Withdrawal, 0, len(r))
+	for _, item := range r {
+		result = append(result, toUsecaseWithdrawal(item))
+	}
+
+	return result
+}
+
+// GetBlockRewards 取得指定地址作為 fee recipient 的手續費收入
+func (p *EthereumParser) GetBlockRewards(address string) []usecase.BlockReward {
+	r := p.storage.GetBlockRewards(strings.ToLower(address))
+	result := make([]usecase.BlockReward, 0, len(r))
+	for _, item := range r {
+		result = append(result, toUsecaseBlockReward(item))
+	}
+
+	return result
+}
+
+// GetBalanceHistory 取得指定地址的餘額快照紀錄
+func (p *EthereumParser) GetBalanceHistory(address string) []usecase.BalanceSnapshot {
+	r := p.storage.GetBalanceSnapshots(strings.ToLower(address))
+	result := make([]usecase.BalanceSnapshot, 0, len(r))
+	for _, item := range r {
+		result = append(result, toUsecaseBalanceSnapshot(item))
 	}
 
 	return result
 }
 
-// FetchTransactionsForAddress 檢查與訂閱地址相關的交易並通知
+// GetPendingTransactions 取得指定地址的待處理交易
+func (p *EthereumParser) GetPendingTransactions(address string) []usecase.PendingTransaction {
+	r := p.storage.GetPendingTransactions(strings.ToLower(address))
+	result := make([]usecase.PendingTransaction, 0, len(r))
+	for _, item := range r {
+		result = append(result, toUsecasePendingTransaction(item))
+	}
+
+	return result
+}
+
+// FetchTransactionsForAddress 檢查目前區塊內與訂閱地址相關的交易並通知
 func (p *EthereumParser) FetchTransactionsForAddress(address string) {
-	blockNumber := fmt.Sprintf("0x%x", p.currentBlock)
-	transactions, err := p.fetchBlockTransactions(blockNumber)
+	// 訂閱可能在處理期間被暫停、移除或過期，以最新狀態為準
+	subscription, ok := p.storage.GetSubscription(address)
+	if !ok || subscriptionStatus(subscription, time.Now()) != domain.SubscriptionStatusActive {
+		return
+	}
+
+	processing := blockProcessing{notification: p.notification, live: true}
+	activity, err := p.fetchBlockForSubscriptions(p.currentBlock, []repository.Subscription{subscription}, processing)
 	if err != nil {
-		fmt.Println("Error fetching block transactions:", err)
+		p.reportError("Error fetching block transactions:", err)
 		return
 	}
+	p.commitBlockActivity(activity, processing)
+}
+
+// blockActivity 處理訂閱所需的區塊資料，下載後依區塊順序交給 commitBlockActivity 保存
+type blockActivity struct {
+	blockNumber string
+	// timestamp 區塊的時間，區塊未下載時為零值
+	timestamp time.Time
+	// skipped 沒有需要處理的地址，區塊未下載
+	skipped        bool
+	block          repository.Block
+	transactions   []repository.Transaction
+	tokenTransfers []repository.TokenTransfer
+	nftTransfers   []repository.NFTTransfer
+	// logsErr 無法取得轉移事件時不為 nil，此時沒有代幣與 NFT 轉移
+	logsErr     error
+	internalTxs []repository.InternalTransaction
+	// traceErr 無法追蹤內部交易時不為 nil，此時內部交易不完整
+	traceErr error
+
+	// subscriptions 需要處理此區塊的訂閱
+	subscriptions []repository.Subscription
+	// receipts 預先取得的收據，以交易哈希值為鍵
+	receipts map[string]receiptResult
+	// reward 預先計算的手續費收入，rewardFetched 為 false 時尚未計算
+	reward        repository.BlockReward
+	rewardErr     error
+	rewardFetched bool
+}
+
+type receiptResult struct {
+	receipt *repository.Receipt
+	err     error
+}
 
-	// 過濾與該地址相關的交易
-	for _, tx := range transactions {
-		if tx.To == address || tx.From == address {
-			p.storage.SaveTransaction(address, tx)
-			p.notification.Notify(address, usecase.Transaction{
-				BlockHash:   tx.BlockHash,
-				BlockNumber: tx.BlockNumber,
-				From:        tx.From,
-				To:          tx.To,
-				Value:       tx.Value,
-			})
+// fetchBlockActivity 取得區塊、轉移事件與內部交易，只有區塊本身無法取得時返回錯誤
+func (p *EthereumParser) fetchBlockActivity(blockNumber string) (*blockActivity, error) {
+	block, err := p.fetchBlock(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	activity := &blockActivity{
+		blockNumber:  blockNumber,
+		timestamp:    time.Unix(int64(block.Timestamp), 0),
+		block:        block,
+		transactions: blockTransactions(block),
+		receipts:     make(map[string]receiptResult),
+	}
+
+	activity.tokenTransfers, activity.nftTransfers, activity.logsErr = p.fetchTransferLogs(blockNumber)
+	if activity.logsErr != nil {
+		p.reportError("Error fetching transfer logs:", activity.logsErr)
+	}
+
+	activity.internalTxs, activity.traceErr = p.fetchInternalTransactions(blockNumber)
+	if activity.traceErr != nil {
+		p.reportError("Error tracing internal transactions:", activity.traceErr)
+	}
+
+	return activity, nil
+}
+
+// blockProcessing 處理區塊的方式
+type blockProcessing struct {
+	// notification 符合條件的紀錄以此發送通知
+	notification usecase.Notification
+	// live 處理最新區塊時套用 bloom 預先過濾、更新待處理交易並對帳餘額，重新處理歷史區塊時不需要
+	live bool
+}
+
+// trackBalancesFor 是否需要計算地址的餘額變化
+func (p *EthereumParser) trackBalancesFor(processing blockProcessing) bool {
+	return processing.live && p.trackBalances
+}
+
+// needsReceipt 與地址相關的交易都會交給 pipeline 判斷是否符合訂閱，需要收據以取得手續費與執行結果
+func needsReceipt(address string, tx repository.Transaction) bool {
+	return tx.From == address || tx.To == address
+}
+
+// needsReward 地址為區塊的 fee recipient 且需要記錄或對帳手續費收入
+func needsReward(subscription repository.Subscription, block repository.Block, trackBalances bool) bool {
+	address := subscription.Address
+	return block.Miner.String() == address && (trackBalances || matchDirection(subscription.Filter, address, "", address))
+}
+
+// fetchBlockForSubscriptions 下載區塊並預先取得各訂閱需要的收據與手續費收入，可與其他區塊同時執行
+// 處理最新區塊且啟用 bloom 預先過濾時，只追蹤代幣的訂閱在區塊不可能與其相關時略過，沒有需要處理的訂閱時不下載區塊
+func (p *EthereumParser) fetchBlockForSubscriptions(blockNumber int, subscriptions []repository.Subscription, processing blockProcessing) (*blockActivity, error) {
+	tag := fmt.Sprintf("0x%x", blockNumber)
+
+	// logs bloom 顯示區塊內不可能有該地址的轉移事件時，不下載完整區塊與事件
+	bloomMatched := make(map[string]bool)
+	if processing.live && p.bloomFilter {
+		candidates := make([]repository.Subscription, 0, len(subscriptions))
+		for _, subscription := range subscriptions {
+			if !p.bloomApplies(subscription) {
+				candidates = append(candidates, subscription)
+				continue
+			}
+			process, matched, err := p.checkBloom(tag, subscription.Address, subscription.Filter)
+			if err != nil {
+				p.reportError("Error fetching block header:", err)
+			}
+			if process {
+				candidates = append(candidates, subscription)
+				bloomMatched[subscription.Address] = matched
+			}
+		}
+		subscriptions = candidates
+	}
+	if len(subscriptions) == 0 {
+		return &blockActivity{blockNumber: tag, skipped: true}, nil
+	}
+
+	activity, err := p.fetchBlockActivity(tag)
+	if err != nil {
+		return nil, err
+	}
+	activity.subscriptions = subscriptions
+
+	trackBalances := p.trackBalancesFor(processing)
+	for _, subscription := range subscriptions {
+		if bloomMatched[subscription.Address] && activity.logsErr == nil &&
+			!transfersInvolve(subscription.Address, subscription.Filter, activity.tokenTransfers, activity.nftTransfers) {
+			p.metrics.bloomFalsePositives.Add(1)
+		}
+
+		for _, tx := range activity.transactions {
+			if _, ok := activity.receipts[tx.Hash]; ok || !needsReceipt(subscription.Address, tx) {
+				continue
+			}
+			receipt, err := p.fetchTransactionReceipt(tx.Hash)
+			activity.receipts[tx.Hash] = receiptResult{receipt: receipt, err: err}
+		}
+
+		if !activity.rewardFetched && needsReward(subscription, activity.block, trackBalances) {
+			activity.reward, activity.rewardErr = p.computeBlockReward(activity.block)
+			activity.rewardFetched = true
 		}
 	}
+
+	return activity, nil
 }
 
-// PollForChanges 定期檢查區塊變化
-func (p *EthereumParser) PollForChanges() {
-	for {
-		previousBlock := p.GetCurrentBlock()
+// commitBlockActivity 依序保存區塊內各訂閱的活動並通知，需依區塊順序呼叫
+func (p *EthereumParser) commitBlockActivity(activity *blockActivity, processing blockProcessing) {
+	if activity.skipped {
+		return
+	}
+	for _, subscription := range activity.subscriptions {
+		p.processBlockActivity(activity, subscription, processing)
+	}
+}
 
-		// 更新區塊，如果區塊有變化才繼續處理
-		err := p.UpdateCurrentBlock()
+// activityReceipt 以預先取得的收據補上交易的手續費，沒有預先取得時查詢節點
+func (p *EthereumParser) activityReceipt(activity *blockActivity, tx repository.Transaction) (repository.Transaction, error) {
+	result, ok := activity.receipts[tx.Hash]
+	if !ok {
+		return p.applyReceipt(tx)
+	}
+	if result.err != nil || result.receipt == nil {
+		return tx, result.err
+	}
+	return p.withReceipt(tx, result.receipt), nil
+}
+
+// activityReward 取得預先計算的手續費收入，沒有預先計算時查詢節點
+func (p *EthereumParser) activityReward(activity *blockActivity) (repository.BlockReward, error) {
+	if !activity.rewardFetched {
+		return p.computeBlockReward(activity.block)
+	}
+	return activity.reward, activity.rewardErr
+}
+
+// processBlockActivity 保存區塊內與訂閱地址相關且符合訂閱條件的活動並通知
+func (p *EthereumParser) processBlockActivity(activity *blockActivity, subscription repository.Subscription, processing blockProcessing) {
+	address, filter := subscription.Address, subscription.Filter
+	block, notification := activity.block, processing.notification
+	trackBalances := p.trackBalancesFor(processing)
+
+	// 區塊內所有與該地址相關的活動都計入餘額變化，不受訂閱的過濾條件影響
+	change := balanceChange{address: address}
+
+	// 與該地址相關的交易取得收據後交給 pipeline，由訂閱過濾階段決定是否符合訂閱條件，不符合的交易仍計入餘額變化
+	for _, tx := range activity.transactions {
+		if !needsReceipt(address, tx) {
+			continue
+		}
+		tx, err := p.activityReceipt(activity, tx)
 		if err != nil {
-			fmt.Println("Error updating current block:", err)
+			p.reportError("Error fetching transaction receipt:", err)
+			change.incomplete = true
+		}
+		change.addTransaction(tx)
+		tx, keep := p.pipelineTransaction(subscription, tx, processing)
+		if !keep {
 			continue
 		}
+		p.storage.SaveTransaction(address, tx)
+		notification.Notify(address, toUsecaseTransaction(tx))
+	}
 
-		if p.currentBlock != previousBlock {
-			fmt.Printf("New block detected: %d\n", p.currentBlock)
-			// 檢查所有訂閱的地址並處理交易
-			addresses := p.storage.GetSubscribedAddresses()
-			for _, address := range addresses {
-				p.FetchTransactionsForAddress(address)
+	// 過濾提款至該地址的驗證者提款，提款只會是轉入
+	for _, withdrawal := range blockWithdrawals(block) {
+		change.transfer("", withdrawal.Address, withdrawal.Amount)
+		if matchDirection(filter, address, "", withdrawal.Address) && matchValue(filter, withdrawal.Amount) {
+			p.storage.SaveWithdrawal(address, withdrawal)
+			notification.NotifyWithdrawal(address, toUsecaseWithdrawal(withdrawal))
+		}
+	}
+
+	// 該地址為區塊的 fee recipient 時記錄手續費收入
+	if needsReward(subscription, block, trackBalances) {
+		reward, err := p.activityReward(activity)
+		if err != nil {
+			p.reportError("Error computing block reward:", err)
+			change.incomplete = true
+		} else {
+			change.transfer("", address, reward.PriorityFees)
+			if matchDirection(filter, address, "", address) && matchValue(filter, reward.PriorityFees) {
+				p.storage.SaveBlockReward(address, reward)
+				notification.NotifyBlockReward(address, toUsecaseBlockReward(reward))
 			}
 		}
+	}
 
-		// 休眠 10 秒後再次檢查
-		time.Sleep(10 * time.Second)
+	// 更新該地址待處理交易的上鏈狀態
+	if processing.live {
+		p.resolvePendingTransactions(address, activity.transactions)
 	}
+
+	// 與該地址相關的代幣轉帳交給 pipeline 判斷是否符合訂閱條件
+	for _, transfer := range activity.tokenTransfers {
+		if transfer.From != address && transfer.To != address {
+			continue
+		}
+		if transfer, keep := p.pipelineTokenTransfer(subscription, transfer, processing); keep {
+			p.storage.SaveTokenTransfer(address, transfer)
+			notification.NotifyTokenTransfer(address, toUsecaseTokenTransfer(transfer))
+		}
+	}
+
+	// 與該地址相關的 NFT 轉移交給 pipeline 判斷是否符合訂閱條件
+	for _, transfer := range activity.nftTransfers {
+		if transfer.From != address && transfer.To != address {
+			continue
+		}
+		if transfer, keep := p.pipelineNFTTransfer(subscription, transfer, processing); keep {
+			p.storage.SaveNFTTransfer(address, transfer)
+			notification.NotifyNFTTransfer(address, toUsecaseNFTTransfer(transfer))
+		}
+	}
+
+	// 與該地址相關的內部交易計入餘額變化後交給 pipeline 判斷是否符合訂閱條件
+	change.incomplete = change.incomplete || activity.traceErr != nil
+	for _, tx := range activity.internalTxs {
+		change.transfer(tx.From, tx.To, tx.Value)
+		if tx.From != address && tx.To != address {
+			continue
+		}
+		if tx, keep := p.pipelineInternalTransaction(subscription, tx, processing); keep {
+			p.storage.SaveInternalTransaction(address, tx)
+			notification.NotifyInternalTransaction(address, toUsecaseInternalTransaction(tx))
+		}
+	}
+
+	if trackBalances && change.active {
+		p.reconcileBalance(address, activity.blockNumber, change)
+	}
+}
+
+// isLeader 目前的實例是否負責處理區塊
+func (p *EthereumParser) isLeader() bool {
+	return p.leadership == nil || p.leadership.IsLeader()
+}
+
+// PollForChanges 依序處理新區塊，輪詢的時間由出塊間隔決定
+func (p *EthereumParser) PollForChanges() {
+	for {
+		time.Sleep(p.pollOnce())
+	}
+}
+
+// pollOnce 處理尚未處理的區塊，返回下次輪詢前等待的時間
+// 啟動時從 Storage 記錄的進度或最新區塊開始，之後逐一處理每個區塊，不會因為輪詢較慢而跳過區塊；
+// 落後多個區塊時同時下載，但依區塊順序保存與通知，每次最多處理 maxPollBatch 個區塊
+func (p *EthereumParser) pollOnce() time.Duration {
+	head, err := p.fetchBlockNumber()
+	if err != nil {
+		p.reportError("Error updating current block:", err)
+		return p.schedule.idle()
+	}
+	p.setChainHead(head)
+
+	// follower 不處理區塊也不發送通知，只跟隨 leader 記錄的進度
+	if !p.isLeader() {
+		p.leading = false
+		p.resumeLastProcessedBlock()
+		return p.schedule.untilNextBlock(time.Now())
+	}
+	// 成為 leader 時由前一個 leader 最後處理完的區塊繼續，避免跳過區塊
+	if !p.leading {
+		p.leading = true
+		p.resumeLastProcessedBlock()
+	}
+
+	next := p.currentBlock + 1
+	if p.currentBlock == 0 {
+		next = head
+	}
+	if next > head {
+		return p.schedule.idle()
+	}
+
+	p.removeExpiredSubscriptions()
+	if time.Since(p.lastENSCheck) >= ensResolveInterval {
+		p.lastENSCheck = time.Now()
+		p.startENSRefresh()
+	}
+
+	// 檢查所有啟用中的訂閱並處理交易
+	subscriptions := p.activeSubscriptions()
+	eventSubscriptions := p.storage.GetEventSubscriptions()
+	processing := blockProcessing{notification: p.notification, live: true}
+	last := min(head, next+maxPollBatch-1)
+	failed := false
+	fetchOrdered(next, last, p.fetchParallelism, p.fetchWindow,
+		func(blockNumber int) blockFetch {
+			activity, err := p.fetchBlockForSubscriptions(blockNumber, subscriptions, processing)
+			events, eventsErr := p.fetchContractEvents(blockNumber, eventSubscriptions)
+			return blockFetch{activity: activity, err: err, events: events, eventsErr: eventsErr}
+		},
+		func(blockNumber int, result blockFetch) bool {
+			// 下載期間失去 leader 身分時不再保存與通知
+			if !p.isLeader() {
+				return false
+			}
+			if !p.commitPolledBlock(blockNumber, result, processing) {
+				failed = true
+				return false
+			}
+			return true
+		})
+
+	// 區塊下載失敗時退避後再由同一個區塊重試
+	if failed {
+		return p.schedule.idle()
+	}
+	if p.currentBlock < head {
+		return p.schedule.catchingUp()
+	}
+	return p.schedule.untilNextBlock(time.Now())
+}
+
+// resumeLastProcessedBlock 以 Storage 記錄的輪詢進度作為目前區塊，尚無紀錄時維持不變
+func (p *EthereumParser) resumeLastProcessedBlock() {
+	if blockNumber, ok := p.storage.GetLastProcessedBlock(); ok {
+		p.currentBlock = blockNumber
+	}
+}
+
+// blockFetch 下載區塊的結果，事件訂閱的事件與地址的活動分開下載，任一下載失敗時整個區塊重試
+type blockFetch struct {
+	activity  *blockActivity
+	err       error
+	events    []contractEvents
+	eventsErr error
+}
+
+// commitPolledBlock 保存輪詢到的區塊並記錄進度，訂閱在下載期間被暫停、移除或過期時略過
+// 區塊或事件訂閱的事件下載失敗時返回 false，不保存、不通知也不記錄進度，避免重試時重複通知
+func (p *EthereumParser) commitPolledBlock(blockNumber int, result blockFetch, processing blockProcessing) bool {
+	if result.err != nil {
+		p.reportError(fmt.Sprintf("Error fetching block %d transactions:", blockNumber), result.err)
+		return false
+	}
+	if result.eventsErr != nil {
+		p.reportError(fmt.Sprintf("Error fetching block %d contract events:", blockNumber), result.eventsErr)
+		return false
+	}
+
+	p.currentBlock = blockNumber
+	fmt.Printf("New block detected: %d\n", blockNumber)
+
+	activity := result.activity
+	subscriptions := make([]repository.Subscription, 0, len(activity.subscriptions))
+	for _, item := range activity.subscriptions {
+		subscription, ok := p.storage.GetSubscription(item.Address)
+		if ok && subscriptionStatus(subscription, time.Now()) == domain.SubscriptionStatusActive {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	activity.subscriptions = subscriptions
+	p.commitBlockActivity(activity, processing)
+	timestamp := activity.timestamp
+
+	p.commitContractEvents(result.events, processing)
+
+	if timestamp.IsZero() {
+		timestamp = p.blockTimestamp(fmt.Sprintf("0x%x", blockNumber))
+	}
+	p.markBlockProcessed(blockNumber, timestamp)
+	p.storage.SaveLastProcessedBlock(blockNumber)
+	p.schedule.observe(blockNumber, timestamp)
+	p.setBlockTime(p.schedule.blockTime)
+	return true
 }
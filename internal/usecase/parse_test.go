@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
 	"parse_server/internal/domain/repository"
 	"parse_server/internal/domain/usecase"
 	"testing"
@@ -40,10 +41,10 @@ func TestFetchBlockTransactions(t *testing.T) {
 			blockNumber: "0x10d4f",
 			mockResult: json.RawMessage(`{
 				"result": {
-					"hash": "0xabc123",
+					"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
 					"number":"0x10d4f",
 					"transactions": [
-						{"from": "0xfrom1", "to": "0xto1", "value": "0x10"}
+						{"from": "0x00000000000000000000000000000000000000f1", "to": "0x00000000000000000000000000000000000000a1", "value": "0x10"}
 					]
 				}
 			}`),
@@ -51,14 +52,53 @@ func TestFetchBlockTransactions(t *testing.T) {
 			expectedErr: false,
 			expectedTx: []repository.Transaction{
 				{
-					BlockHash:   "0xabc123",
+					BlockHash:   "0xabc1230000000000000000000000000000000000000000000000000000000000",
 					BlockNumber: "0x10d4f",
-					From:        "0xfrom1",
-					To:          "0xto1",
-					Value:       "0x10",
+					From:        "0x00000000000000000000000000000000000000f1",
+					To:          "0x00000000000000000000000000000000000000a1",
+					Value:       domain.BigIntFromUint64(0x10),
+					Nonce:       "0x0",
 				},
 			},
 		},
+		{
+			name:        "Mixed case addresses are normalized",
+			blockNumber: "0x10d4f",
+			mockResult: json.RawMessage(`{
+				"result": {
+					"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+					"number":"0x10d4f",
+					"transactions": [
+						{"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "to": "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "value": "0x10", "nonce": "0x1"}
+					]
+				}
+			}`),
+			expectedTx: []repository.Transaction{
+				{
+					Hash:        "0x1111111111111111111111111111111111111111111111111111111111111111",
+					BlockHash:   "0xabc1230000000000000000000000000000000000000000000000000000000000",
+					BlockNumber: "0x10d4f",
+					From:        "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+					To:          "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359",
+					Value:       domain.BigIntFromUint64(0x10),
+					Nonce:       "0x1",
+				},
+			},
+		},
+		{
+			name:        "Malformed quantity is rejected at decode time",
+			blockNumber: "0x10d4f",
+			mockResult: json.RawMessage(`{
+				"result": {
+					"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+					"number":"0x10d4f",
+					"transactions": [
+						{"from": "0x00000000000000000000000000000000000000f1", "value": "0x010"}
+					]
+				}
+			}`),
+			expectedErr: true,
+		},
 		{
 			name:        "Error fetching block",
 			blockNumber: "0x10d4f",
@@ -155,21 +195,200 @@ func TestFetchTransactionsForAddress(t *testing.T) {
 		EthClient:    mockClient,
 	})
 
-	address := "0x123"
+	address := "0x0000000000000000000000000000000000000123"
 
 	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
 		"result": {
-			"hash": "0xabc123",
+			"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
 			"transactions": [
-				{"from": "0x123", "to": "0x456", "value": "0x10"},
-				{"from": "0x789", "to": "0x123", "value": "0x20"}
+				{"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "0x0000000000000000000000000000000000000123", "to": "0x0000000000000000000000000000000000000456", "value": "0x10", "gasPrice": "0x5"},
+				{"hash": "0x2222222222222222222222222222222222222222222222222222222222222222", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000123", "value": "0x20", "gasPrice": "0x5"}
 			]
 		}
 	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", []any{"0x1111111111111111111111111111111111111111111111111111111111111111"}).Return(json.RawMessage(`{
+		"result": {"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111", "gasUsed": "0x5208", "effectiveGasPrice": "0x3b9aca00", "status": "0x1"}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", []any{"0x2222222222222222222222222222222222222222222222222222222222222222"}).Return(json.RawMessage(`{
+		"result": {"transactionHash": "0x2222222222222222222222222222222222222222222222222222222222222222", "gasUsed": "0x5208", "status": "0x1"}
+	}`), nil)
+
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{
+		"result": [
+			{
+				"address": "0x000000000000000000000000000000000000ee20",
+				"topics": [
+					"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+					"0x0000000000000000000000000000000000000000000000000000000000000789",
+					"0x0000000000000000000000000000000000000000000000000000000000000123"
+				],
+				"data": "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+				"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111"
+			}
+		]
+	}`), nil)
 
 	// 模擬 SaveTransaction 和 Notify
-	mockStorage.EXPECT().SaveTransaction(gomock.Any(), gomock.Any()).Times(2)
+	mockStorage.EXPECT().GetPendingTransactions(address).Return(nil)
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{Address: address}, true)
+	var saved []repository.Transaction
+	mockStorage.EXPECT().SaveTransaction(gomock.Any(), gomock.Any()).Do(func(_ string, tx repository.Transaction) {
+		saved = append(saved, tx)
+	}).Times(2)
 	mockNotification.EXPECT().Notify(gomock.Any(), gomock.Any()).Times(2)
+	mockStorage.EXPECT().SaveTokenTransfer(address, gomock.Any()).Times(1)
+	mockNotification.EXPECT().NotifyTokenTransfer(address, gomock.Any()).Times(1)
+	mockStorage.EXPECT().SaveNFTTransfer(gomock.Any(), gomock.Any()).Times(0)
+	mockNotification.EXPECT().NotifyNFTTransfer(gomock.Any(), gomock.Any()).Times(0)
+
+	parser.(*EthereumParser).FetchTransactionsForAddress(address)
+
+	// 手續費以收據的 gasUsed 乘上 effectiveGasPrice 計算，沒有 effectiveGasPrice 時使用交易的 gasPrice
+	assert.Equal(t, "21000000000000", saved[0].Fee.String())
+	assert.Equal(t, "1000000000", saved[0].GasPrice.String())
+	assert.Equal(t, "105000", saved[1].Fee.String())
+}
+
+func TestFetchTransferLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	})
+
+	tests := []struct {
+		name        string
+		mockResult  json.RawMessage
+		mockError   error
+		expectedErr bool
+		expected    []repository.TokenTransfer
+		expectedNFT []repository.NFTTransfer
+	}{
+		{
+			name: "ERC-20 and ERC-721 transfers are decoded separately",
+			mockResult: json.RawMessage(`{
+				"result": [
+					{
+						"address": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+						"topics": [
+							"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+							"0x000000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+							"0x000000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+						],
+						"data": "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000",
+						"blockHash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+						"blockNumber": "0x10d4f",
+						"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111",
+						"logIndex": "0x1"
+					},
+					{
+						"address": "0x000000000000000000000000000000000000dddd",
+						"topics": [
+							"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+							"0x000000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+							"0x000000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+							"0x0000000000000000000000000000000000000000000000000000000000000001"
+						],
+						"data": "0x",
+						"transactionHash": "0x2222222222222222222222222222222222222222222222222222222222222222"
+					}
+				]
+			}`),
+			expectedNFT: []repository.NFTTransfer{
+				{
+					TxHash:      "0x2222222222222222222222222222222222222222222222222222222222222222",
+					BlockNumber: "0x0",
+					LogIndex:    "0x0",
+					Standard:    "ERC-721",
+					Contract:    "0x000000000000000000000000000000000000dddd",
+					From:        "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					To:          "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+					TokenID:     domain.BigIntFromUint64(0x1),
+					Amount:      domain.BigIntFromUint64(0x1),
+				},
+			},
+			expected: []repository.TokenTransfer{
+				{
+					TxHash:      "0x1111111111111111111111111111111111111111111111111111111111111111",
+					BlockHash:   "0xabc1230000000000000000000000000000000000000000000000000000000000",
+					BlockNumber: "0x10d4f",
+					LogIndex:    "0x1",
+					Contract:    "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+					From:        "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					To:          "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+					Amount:      domain.BigIntFromUint64(0xde0b6b3a7640000),
+				},
+			},
+		},
+		{
+			name:        "Error fetching logs",
+			mockError:   errors.New("error calling Ethereum"),
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(tt.mockResult, tt.mockError)
+
+			transfers, nftTransfers, err := parser.(*EthereumParser).fetchTransferLogs("0x10d4f")
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, transfers)
+				assert.Equal(t, tt.expectedNFT, nftTransfers)
+			}
+		})
+	}
+}
+
+func TestFetchTransactionsForAddress_TokenTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	})
+
+	address := "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"result": {"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000", "transactions": []}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{
+		"result": [
+			{
+				"address": "0x000000000000000000000000000000000000ee20",
+				"topics": [
+					"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+					"0x000000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					"0x000000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+				],
+				"data": "0x0000000000000000000000000000000000000000000000000000000000000010",
+				"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111"
+			}
+		]
+	}`), nil)
+
+	// 代幣轉帳應以交易哈希關聯並發送通知
+	mockStorage.EXPECT().GetPendingTransactions(address).Return(nil)
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{Address: address}, true)
+	mockStorage.EXPECT().SaveTokenTransfer(address, gomock.Any()).Times(1)
+	mockNotification.EXPECT().NotifyTokenTransfer(address, gomock.Any()).Times(1)
 
 	parser.(*EthereumParser).FetchTransactionsForAddress(address)
 }
@@ -188,16 +407,87 @@ func TestSubscribe(t *testing.T) {
 		EthClient:    mockClient,
 	})
 
-	address := "0x123"
+	tests := []struct {
+		name           string
+		address        string
+		filter         usecase.SubscriptionFilter
+		expectedErr    error
+		expected       string
+		expectedFilter repository.SubscriptionFilter
+	}{
+		{
+			name:           "Checksum address is stored in lowercase",
+			address:        "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			expected:       "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			expectedFilter: repository.SubscriptionFilter{Direction: domain.DirectionBoth},
+		},
+		{
+			name:    "Filter is normalized",
+			address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			filter: usecase.SubscriptionFilter{
+				Direction:       domain.DirectionIncoming,
+				MinValue:        domain.BigIntFromUint64(1000),
+				TokenContracts:  []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+				MethodSelectors: []string{"0x095EA7B3"},
+				ExcludeFailed:   true,
+			},
+			expected: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			expectedFilter: repository.SubscriptionFilter{
+				Direction:       domain.DirectionIncoming,
+				MinValue:        domain.BigIntFromUint64(1000),
+				TokenContracts:  []string{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+				MethodSelectors: []string{"0x095ea7b3"},
+				ExcludeFailed:   true,
+			},
+		},
+		{
+			name:        "Invalid checksum",
+			address:     "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+			expectedErr: domain.ErrInvalidChecksum,
+		},
+		{
+			name:        "Invalid address",
+			address:     "0x123",
+			expectedErr: domain.ErrInvalidAddress,
+		},
+		{
+			name:        "Invalid direction",
+			address:     "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			filter:      usecase.SubscriptionFilter{Direction: "sideways"},
+			expectedErr: domain.ErrInvalidDirection,
+		},
+		{
+			name:        "Invalid token contract",
+			address:     "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			filter:      usecase.SubscriptionFilter{TokenContracts: []string{"0x123"}},
+			expectedErr: domain.ErrInvalidAddress,
+		},
+		{
+			name:        "Invalid method selector",
+			address:     "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			filter:      usecase.SubscriptionFilter{MethodSelectors: []string{"approve"}},
+			expectedErr: domain.ErrInvalidSelector,
+		},
+	}
 
-	// 檢查 storage 是否調用了 SubscribeAddress
-	mockStorage.EXPECT().SubscribeAddress(address).Times(1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 檢查 storage 是否以標準格式調用了 SubscribeAddress
+			if tt.expectedErr == nil {
+				mockStorage.EXPECT().GetSubscription(tt.expected).Return(repository.Subscription{}, false)
+				mockStorage.EXPECT().SubscribeAddress(gomock.Any()).Do(func(subscription repository.Subscription) {
+					assert.Equal(t, tt.expected, subscription.Address)
+					assert.Equal(t, tt.expectedFilter, subscription.Filter)
+				}).Times(1)
+			}
 
-	// 調用 Subscribe 方法
-	result := parser.Subscribe(address)
+			// 調用 Subscribe 方法
+			err := parser.Subscribe(usecase.Subscription{Address: tt.address, Filter: tt.filter})
 
-	// 檢查返回值
-	assert.True(t, result)
+			// 檢查返回值
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
 }
 
 func TestGetTransactions(t *testing.T) {
@@ -214,23 +504,23 @@ func TestGetTransactions(t *testing.T) {
 		EthClient:    mockClient,
 	})
 
-	address := "0x123"
+	address := "0x0000000000000000000000000000000000000123"
 
 	// 模擬 Storage 返回的交易資料
 	mockTransactions := []repository.Transaction{
 		{
-			BlockHash:   "0xabc123",
+			BlockHash:   "0xabc1230000000000000000000000000000000000000000000000000000000000",
 			BlockNumber: "100",
-			From:        "0xfrom1",
-			To:          "0xto1",
-			Value:       "0x10",
+			From:        "0x00000000000000000000000000000000000000f1",
+			To:          "0x00000000000000000000000000000000000000a1",
+			Value:       domain.BigIntFromUint64(0x10),
 		},
 		{
-			BlockHash:   "0xdef456",
+			BlockHash:   "0xdef4560000000000000000000000000000000000000000000000000000000000",
 			BlockNumber: "101",
 			From:        "0xfrom2",
 			To:          "0xto2",
-			Value:       "0x20",
+			Value:       domain.BigIntFromUint64(0x20),
 		},
 	}
 
@@ -243,18 +533,18 @@ func TestGetTransactions(t *testing.T) {
 	// 檢查返回值
 	expected := []usecase.Transaction{
 		{
-			BlockHash:   "0xabc123",
+			BlockHash:   "0xabc1230000000000000000000000000000000000000000000000000000000000",
 			BlockNumber: "100",
-			From:        "0xfrom1",
-			To:          "0xto1",
-			Value:       "0x10",
+			From:        "0x00000000000000000000000000000000000000f1",
+			To:          "0x00000000000000000000000000000000000000a1",
+			Value:       domain.BigIntFromUint64(0x10),
 		},
 		{
-			BlockHash:   "0xdef456",
+			BlockHash:   "0xdef4560000000000000000000000000000000000000000000000000000000000",
 			BlockNumber: "101",
 			From:        "0xfrom2",
 			To:          "0xto2",
-			Value:       "0x20",
+			Value:       domain.BigIntFromUint64(0x20),
 		},
 	}
 
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"encoding/json"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"time"
+)
+
+const (
+	// pendingPollInterval 輪詢記憶池的間隔
+	pendingPollInterval = 2 * time.Second
+	// pendingDropTimeout 待處理交易超過此時間仍未上鏈時，檢查節點是否已將其丟棄
+	pendingDropTimeout = 30 * time.Minute
+	// pendingDropCheckInterval 檢查丟棄交易的間隔
+	pendingDropCheckInterval = time.Minute
+)
+
+// WatchPendingTransactions 定期檢查記憶池中與訂閱地址相關的待處理交易
+func (p *EthereumParser) WatchPendingTransactions() {
+	for {
+		// 只有 leader 監聽記憶池並發送通知
+		if p.isLeader() {
+			err := p.CheckPendingTransactions()
+			if err != nil {
+				p.reportError("Error checking pending transactions:", err)
+			}
+
+			if time.Since(p.lastDropCheck) >= pendingDropCheckInterval {
+				p.lastDropCheck = time.Now()
+				p.checkDroppedTransactions()
+			}
+		}
+
+		time.Sleep(pendingPollInterval)
+	}
+}
+
+// CheckPendingTransactions 取得新進入記憶池的交易，記錄並通知與訂閱地址相關者
+// filter 不會再次返回已取得的哈希值，單筆交易查詢失敗時記錄錯誤並繼續處理其餘交易
+func (p *EthereumParser) CheckPendingTransactions() error {
+	hashes, err := p.fetchPendingTransactionHashes()
+	if err != nil {
+		return err
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	subscribed := make(map[string]repository.SubscriptionFilter)
+	for _, subscription := range p.activeSubscriptions() {
+		subscribed[subscription.Address] = subscription.Filter
+	}
+
+	for _, hash := range hashes {
+		item, err := p.fetchTransactionByHash(hash.String())
+		if err != nil {
+			p.reportError("Error fetching pending transaction "+hash.String()+":", err)
+			continue
+		}
+		// 交易已被丟棄或已上鏈時交由區塊處理
+		if item == nil || item.BlockNumber != nil {
+			continue
+		}
+
+		tx := repository.PendingTransaction{
+			Hash:   item.Hash.String(),
+			From:   item.From.String(),
+			Value:  item.Value.BigInt,
+			Nonce:  item.Nonce.String(),
+			Status: domain.PendingStatusPending,
+			SeenAt: time.Now(),
+		}
+		if item.To != nil {
+			tx.To = item.To.String()
+		}
+
+		for _, address := range []string{tx.From, tx.To} {
+			filter, ok := subscribed[address]
+			if !ok || filter.TokensOnly {
+				continue
+			}
+			if !matchDirection(filter, address, tx.From, tx.To) || !matchValue(filter, tx.Value) || !matchSelector(filter, item.Input) {
+				continue
+			}
+			p.storage.SavePendingTransaction(address, tx)
+			p.notification.NotifyPendingTransaction(address, toUsecasePendingTransaction(tx))
+			// 自己轉給自己時只記錄一次
+			if tx.From == tx.To {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetchPendingTransactionHashes 透過 pending transaction filter 取得自上次查詢後新增的交易哈希值
+// filter 過期時節點會返回錯誤，此時清除 filter ID 於下次重新建立
+func (p *EthereumParser) fetchPendingTransactionHashes() ([]domain.Hash, error) {
+	if p.pendingFilterID == "" {
+		result, err := p.ethClient.CallEthereum("eth_newPendingTransactionFilter", []any{})
+		if err != nil {
+			return nil, err
+		}
+
+		var rpcResponse repository.EthereumRPCResponse
+		err = json.Unmarshal(result, &rpcResponse)
+		if err != nil {
+			return nil, err
+		}
+		if rpcResponse.Error != nil {
+			return nil, rpcResponse.Error
+		}
+		p.pendingFilterID = rpcResponse.Result
+	}
+
+	result, err := p.ethClient.CallEthereum("eth_getFilterChanges", []any{p.pendingFilterID})
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResponse repository.FilterChangesResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return nil, err
+	}
+	if rpcResponse.Error != nil {
+		p.pendingFilterID = ""
+		return nil, rpcResponse.Error
+	}
+
+	return rpcResponse.Result, nil
+}
+
+// fetchTransactionByHash 根據交易哈希值取得交易，節點找不到時返回 nil
+func (p *EthereumParser) fetchTransactionByHash(hash string) (*repository.TransactionItem, error) {
+	result, err := p.ethClient.CallEthereum("eth_getTransactionByHash", []any{hash})
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResponse repository.TransactionResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return nil, err
+	}
+	if rpcResponse.Error != nil {
+		return nil, rpcResponse.Error
+	}
+
+	return rpcResponse.Result, nil
+}
+
+// resolvePendingTransactions 將已上鏈的待處理交易標記為 mined，被同一發送者同 nonce 交易取代者標記為 replaced
+func (p *EthereumParser) resolvePendingTransactions(address string, transactions []repository.Transaction) {
+	for _, item := range p.storage.GetPendingTransactions(address) {
+		if item.Status != domain.PendingStatusPending {
+			continue
+		}
+
+		for _, tx := range transactions {
+			switch {
+			case tx.Hash == item.Hash:
+				item.Status = domain.PendingStatusMined
+			case item.Nonce != "" && tx.From == item.From && tx.Nonce == item.Nonce:
+				item.Status = domain.PendingStatusReplaced
+				item.ReplacedBy = tx.Hash
+			default:
+				continue
+			}
+			item.BlockHash = tx.BlockHash
+			item.BlockNumber = tx.BlockNumber
+			p.storage.SavePendingTransaction(address, item)
+			p.notification.NotifyPendingTransaction(address, toUsecasePendingTransaction(item))
+			break
+		}
+	}
+}
+
+// checkDroppedTransactions 長時間未上鏈且節點已找不到的待處理交易標記為 dropped
+// 節點顯示已上鏈但未經區塊處理標記的交易（例如區塊處理時訂閱已暫停）直接標記為 mined
+func (p *EthereumParser) checkDroppedTransactions() {
+	for _, subscription := range p.activeSubscriptions() {
+		address := subscription.Address
+		for _, item := range p.storage.GetPendingTransactions(address) {
+			if item.Status != domain.PendingStatusPending || time.Since(item.SeenAt) < pendingDropTimeout {
+				continue
+			}
+
+			tx, err := p.fetchTransactionByHash(item.Hash)
+			if err != nil {
+				p.reportError("Error checking dropped transaction:", err)
+				return
+			}
+			switch {
+			case tx == nil:
+				item.Status = domain.PendingStatusDropped
+			case tx.BlockNumber != nil:
+				item.Status = domain.PendingStatusMined
+				item.BlockNumber = tx.BlockNumber.String()
+				if tx.BlockHash != nil {
+					item.BlockHash = tx.BlockHash.String()
+				}
+			default:
+				continue
+			}
+			p.storage.SavePendingTransaction(address, item)
+			p.notification.NotifyPendingTransaction(address, toUsecasePendingTransaction(item))
+		}
+	}
+}
+
+// toUsecasePendingTransaction 將 repository 的待處理交易轉為 usecase 層的結構
+func toUsecasePendingTransaction(item repository.PendingTransaction) usecase.PendingTransaction {
+	return usecase.PendingTransaction{
+		Hash:        item.Hash,
+		From:        item.From,
+		To:          item.To,
+		Value:       item.Value,
+		Nonce:       item.Nonce,
+		Status:      item.Status,
+		BlockHash:   item.BlockHash,
+		BlockNumber: item.BlockNumber,
+		ReplacedBy:  item.ReplacedBy,
+		SeenAt:      item.SeenAt,
+	}
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+	"time"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func TestCheckPendingTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	})
+
+	address := "0x0000000000000000000000000000000000000123"
+
+	mockClient.EXPECT().CallEthereum("eth_newPendingTransactionFilter", gomock.Any()).Return(json.RawMessage(`{"result": "0xfilter"}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getFilterChanges", []any{"0xfilter"}).Return(json.RawMessage(`{"result": ["0x1111111111111111111111111111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222222222222222222222222222", "0x3333333333333333333333333333333333333333333333333333333333333333"]}`), nil)
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{{Address: address}})
+	mockClient.EXPECT().CallEthereum("eth_getTransactionByHash", []any{"0x1111111111111111111111111111111111111111111111111111111111111111"}).Return(json.RawMessage(`{
+		"result": {"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000123", "value": "0x10", "nonce": "0x5", "blockNumber": null}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionByHash", []any{"0x2222222222222222222222222222222222222222222222222222222222222222"}).Return(json.RawMessage(`{
+		"result": {"hash": "0x2222222222222222222222222222222222222222222222222222222222222222", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000456", "value": "0x10", "nonce": "0x6", "blockNumber": null}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionByHash", []any{"0x3333333333333333333333333333333333333333333333333333333333333333"}).Return(json.RawMessage(`{"result": null}`), nil)
+
+	mockStorage.EXPECT().SavePendingTransaction(address, gomock.Any()).Do(func(_ string, tx repository.PendingTransaction) {
+		assert.Equal(t, "0x1111111111111111111111111111111111111111111111111111111111111111", tx.Hash)
+		assert.Equal(t, "0x5", tx.Nonce)
+		assert.Equal(t, domain.PendingStatusPending, tx.Status)
+	})
+	mockNotification.EXPECT().NotifyPendingTransaction(address, gomock.Any()).Times(1)
+
+	err := parser.(*EthereumParser).CheckPendingTransactions()
+	assert.NoError(t, err)
+}
+
+func TestCheckPendingTransactions_FetchError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	}).(*EthereumParser)
+	parser.pendingFilterID = "0xfilter"
+
+	address := "0x0000000000000000000000000000000000000123"
+
+	// filter 不會再次返回這些哈希值，單筆查詢失敗時仍處理其餘交易
+	mockClient.EXPECT().CallEthereum("eth_getFilterChanges", []any{"0xfilter"}).Return(json.RawMessage(`{"result": ["0x1111111111111111111111111111111111111111111111111111111111111111", "0x2222222222222222222222222222222222222222222222222222222222222222"]}`), nil)
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{{Address: address}})
+	mockClient.EXPECT().CallEthereum("eth_getTransactionByHash", []any{"0x1111111111111111111111111111111111111111111111111111111111111111"}).Return(nil, errors.New("connection reset"))
+	mockClient.EXPECT().CallEthereum("eth_getTransactionByHash", []any{"0x2222222222222222222222222222222222222222222222222222222222222222"}).Return(json.RawMessage(`{
+		"result": {"hash": "0x2222222222222222222222222222222222222222222222222222222222222222", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000123", "value": "0x10", "nonce": "0x6", "blockNumber": null}
+	}`), nil)
+	mockStorage.EXPECT().SavePendingTransaction(address, gomock.Any()).Do(func(_ string, tx repository.PendingTransaction) {
+		assert.Equal(t, "0x2222222222222222222222222222222222222222222222222222222222222222", tx.Hash)
+	})
+	mockNotification.EXPECT().NotifyPendingTransaction(address, gomock.Any())
+
+	err := parser.CheckPendingTransactions()
+	assert.NoError(t, err)
+	assert.NotNil(t, parser.status.lastError)
+}
+
+func TestCheckPendingTransactions_FilterExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      repoMock.NewMockStorage(ctrl),
+		Notification: ucMock.NewMockNotification(ctrl),
+		EthClient:    mockClient,
+	})
+	parser.(*EthereumParser).pendingFilterID = "0xold"
+
+	mockClient.EXPECT().CallEthereum("eth_getFilterChanges", []any{"0xold"}).Return(json.RawMessage(`{
+		"error": {"code": -32000, "message": "filter not found"}
+	}`), nil)
+
+	err := parser.(*EthereumParser).CheckPendingTransactions()
+	assert.Error(t, err)
+	// filter 失效後應於下次重新建立
+	assert.Empty(t, parser.(*EthereumParser).pendingFilterID)
+}
+
+func TestResolvePendingTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    repoMock.NewMockETHClient(ctrl),
+	})
+
+	address := "0x0000000000000000000000000000000000000123"
+
+	mockStorage.EXPECT().GetPendingTransactions(address).Return([]repository.PendingTransaction{
+		{Hash: "0x4444444444444444444444444444444444444444444444444444444444444444", From: "0x0000000000000000000000000000000000000123", Nonce: "0x1", Status: domain.PendingStatusPending},
+		{Hash: "0x5555555555555555555555555555555555555555555555555555555555555555", From: "0x0000000000000000000000000000000000000123", Nonce: "0x2", Status: domain.PendingStatusPending},
+		{Hash: "0x6666666666666666666666666666666666666666666666666666666666666666", From: "0x0000000000000000000000000000000000000123", Nonce: "0x3", Status: domain.PendingStatusPending},
+		{Hash: "0x7777777777777777777777777777777777777777777777777777777777777777", From: "0x0000000000000000000000000000000000000123", Nonce: "0x0", Status: domain.PendingStatusMined},
+	})
+
+	var notified []usecase.PendingTransaction
+	mockStorage.EXPECT().SavePendingTransaction(address, gomock.Any()).Times(2)
+	mockNotification.EXPECT().NotifyPendingTransaction(address, gomock.Any()).Do(func(_ string, tx usecase.PendingTransaction) {
+		notified = append(notified, tx)
+	}).Times(2)
+
+	parser.(*EthereumParser).resolvePendingTransactions(address, []repository.Transaction{
+		{Hash: "0x4444444444444444444444444444444444444444444444444444444444444444", BlockHash: "0xb10c000000000000000000000000000000000000000000000000000000000000", BlockNumber: "0x10", From: "0x0000000000000000000000000000000000000123", Nonce: "0x1"},
+		{Hash: "0x8888888888888888888888888888888888888888888888888888888888888888", BlockHash: "0xb10c000000000000000000000000000000000000000000000000000000000000", BlockNumber: "0x10", From: "0x0000000000000000000000000000000000000123", Nonce: "0x2"},
+	})
+
+	assert.Equal(t, []usecase.PendingTransaction{
+		{Hash: "0x4444444444444444444444444444444444444444444444444444444444444444", From: "0x0000000000000000000000000000000000000123", Nonce: "0x1", Status: domain.PendingStatusMined, BlockHash: "0xb10c000000000000000000000000000000000000000000000000000000000000", BlockNumber: "0x10"},
+		{Hash: "0x5555555555555555555555555555555555555555555555555555555555555555", From: "0x0000000000000000000000000000000000000123", Nonce: "0x2", Status: domain.PendingStatusReplaced, BlockHash: "0xb10c000000000000000000000000000000000000000000000000000000000000", BlockNumber: "0x10", ReplacedBy: "0x8888888888888888888888888888888888888888888888888888888888888888"},
+	}, notified)
+}
+
+func TestCheckDroppedTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	})
+
+	address := "0x0000000000000000000000000000000000000123"
+
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{{Address: address}})
+	mockStorage.EXPECT().GetPendingTransactions(address).Return([]repository.PendingTransaction{
+		{Hash: "0x9999999999999999999999999999999999999999999999999999999999999999", Status: domain.PendingStatusPending, SeenAt: time.Now().Add(-time.Hour)},
+		{Hash: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Status: domain.PendingStatusPending, SeenAt: time.Now().Add(-time.Hour)},
+		{Hash: "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Status: domain.PendingStatusPending, SeenAt: time.Now()},
+		{Hash: "0xcccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc", Status: domain.PendingStatusPending, SeenAt: time.Now().Add(-time.Hour)},
+	})
+	mockClient.EXPECT().CallEthereum("eth_getTransactionByHash", []any{"0x9999999999999999999999999999999999999999999999999999999999999999"}).Return(json.RawMessage(`{"result": null}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionByHash", []any{"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}).Return(json.RawMessage(`{
+		"result": {"hash": "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "blockNumber": null}
+	}`), nil)
+
+	// 已上鏈的交易標記為 mined，不會一直停留在 pending
+	mockClient.EXPECT().CallEthereum("eth_getTransactionByHash", []any{"0xcccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"}).Return(json.RawMessage(`{
+		"result": {"hash": "0xcccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc", "blockHash": "0xdddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd", "blockNumber": "0x20"}
+	}`), nil)
+
+	var saved []repository.PendingTransaction
+	mockStorage.EXPECT().SavePendingTransaction(address, gomock.Any()).Do(func(_ string, tx repository.PendingTransaction) {
+		saved = append(saved, tx)
+	}).Times(2)
+	mockNotification.EXPECT().NotifyPendingTransaction(address, gomock.Any()).Times(2)
+
+	parser.(*EthereumParser).checkDroppedTransactions()
+	assert.Equal(t, "0x9999999999999999999999999999999999999999999999999999999999999999", saved[0].Hash)
+	assert.Equal(t, domain.PendingStatusDropped, saved[0].Status)
+	assert.Equal(t, "0xcccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc", saved[1].Hash)
+	assert.Equal(t, domain.PendingStatusMined, saved[1].Status)
+	assert.Equal(t, "0x20", saved[1].BlockNumber)
+	assert.Equal(t, "0xdddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd", saved[1].BlockHash)
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"slices"
+)
+
+// 內建處理階段的 Order，自訂的階段以此決定在過濾或解碼之前或之後執行
+const (
+	PipelineOrderSubscriptionFilter = 0
+	PipelineOrderABIDecode          = 10
+)
+
+// DefaultPipelineStages 內建的訂閱過濾與 ABI 解碼階段，註冊 pipeline 時需包含，否則紀錄不會依訂閱條件過濾
+// 未設定 Pipeline 時 Parser 只執行這兩個階段
+func DefaultPipelineStages(registry repository.ABIRegistry) []usecase.PipelineStage {
+	return []usecase.PipelineStage{
+		{
+			Name:      "subscription-filter",
+			Processor: SubscriptionFilterProcessor{},
+			Order:     PipelineOrderSubscriptionFilter,
+			OnError:   domain.PipelineOnErrorDrop,
+		},
+		{
+			Name:      "abi-decode",
+			Processor: NewABIDecodeProcessor(ABIDecodeParam{Registry: registry}),
+			Order:     PipelineOrderABIDecode,
+			OnError:   domain.PipelineOnErrorContinue,
+		},
+	}
+}
+
+// TransactionPipeline 依 Order 依序執行已註冊的處理階段，實現了 Pipeline interface
+// 註冊後不再修改，可由多個 goroutine 同時執行
+type TransactionPipeline struct {
+	stages []usecase.PipelineStage
+}
+
+// NewTransactionPipeline 驗證並依 Order 排序處理階段，Order 相同時保留註冊的順序
+func NewTransactionPipeline(stages ...usecase.PipelineStage) (*TransactionPipeline, error) {
+	sorted := make([]usecase.PipelineStage, 0, len(stages))
+	for _, stage := range stages {
+		if stage.OnError == "" {
+			stage.OnError = domain.PipelineOnErrorContinue
+		}
+		if stage.Name == "" || stage.Processor == nil || stage.Retries < 0 ||
+			(stage.OnError != domain.PipelineOnErrorContinue && stage.OnError != domain.PipelineOnErrorDrop) {
+			return nil, fmt.Errorf("%w: %q", domain.ErrInvalidPipelineStage, stage.Name)
+		}
+		sorted = append(sorted, stage)
+	}
+	slices.SortStableFunc(sorted, func(a, b usecase.PipelineStage) int {
+		return a.Order - b.Order
+	})
+	return &TransactionPipeline{stages: sorted}, nil
+}
+
+func MustTransactionPipeline(stages ...usecase.PipelineStage) *TransactionPipeline {
+	t, err := NewTransactionPipeline(stages...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Stages 返回排序後的處理階段名稱
+func (t *TransactionPipeline) Stages() []string {
+	names := make([]string, 0, len(t.stages))
+	for _, stage := range t.stages {
+		names = append(names, stage.Name)
+	}
+	return names
+}
+
+// Run 依序執行各階段，任一階段返回 false 或以 drop 方式處理失敗時丟棄紀錄並停止執行
+// 以 continue 方式處理失敗時捨棄該階段對紀錄的修改並繼續執行下一個階段，所有失敗合併為一個錯誤返回
+func (t *TransactionPipeline) Run(item *usecase.PipelineItem) (bool, error) {
+	var errs []error
+	for _, stage := range t.stages {
+		keep, err := runStage(stage, item)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pipeline stage %s: %w", stage.Name, err))
+			if stage.OnError == domain.PipelineOnErrorDrop {
+				return false, errors.Join(errs...)
+			}
+			continue
+		}
+		if !keep {
+			return false, errors.Join(errs...)
+		}
+	}
+	return true, errors.Join(errs...)
+}
+
+// runStage 執行處理階段，失敗時最多重試 Retries 次，panic 視為失敗以免中斷區塊的處理
+// 每次都以紀錄的副本執行，成功時才寫回，失敗的嘗試對紀錄的修改不會影響重試與後續的階段
+func runStage(stage usecase.PipelineStage, item *usecase.PipelineItem) (keep bool, err error) {
+	for attempt := 0; attempt <= stage.Retries; attempt++ {
+		working := cloneItem(*item)
+		keep, err = processSafely(stage.Processor, &working)
+		if err == nil {
+			*item = working
+			return keep, nil
+		}
+	}
+	return false, err
+}
+
+func processSafely(processor usecase.Processor, item *usecase.PipelineItem) (keep bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return processor.Process(item)
+}
+
+// ProcessorFunc 讓一般函式可以作為處理階段
+type ProcessorFunc func(item *usecase.PipelineItem) (bool, error)
+
+func (f ProcessorFunc) Process(item *usecase.PipelineItem) (bool, error) {
+	return f(item)
+}
+
+// cloneItem 複製紀錄以及其中的 slice 與 map，處理階段修改副本不會影響原本的紀錄
+func cloneItem(item usecase.PipelineItem) usecase.PipelineItem {
+	item.Filter.TokenContracts = slices.Clone(item.Filter.TokenContracts)
+	item.Filter.MethodSelectors = slices.Clone(item.Filter.MethodSelectors)
+	if item.Transaction != nil {
+		tx := *item.Transaction
+		tx.Input = slices.Clone(tx.Input)
+		tx.BlobVersionedHashes = slices.Clone(tx.BlobVersionedHashes)
+		tx.AccessList = slices.Clone(tx.AccessList)
+		for i := range tx.AccessList {
+			tx.AccessList[i].StorageKeys = slices.Clone(tx.AccessList[i].StorageKeys)
+		}
+		tx.AuthorizationList = slices.Clone(tx.AuthorizationList)
+		if tx.Method != nil {
+			method := cloneCall(*tx.Method)
+			tx.Method = &method
+		}
+		if tx.Events != nil {
+			events := make([]abi.Call, 0, len(tx.Events))
+			for _, event := range tx.Events {
+				events = append(events, cloneCall(event))
+			}
+			tx.Events = events
+		}
+		tx.Labels = maps.Clone(tx.Labels)
+		item.Transaction = &tx
+	}
+	if item.TokenTransfer != nil {
+		transfer := *item.TokenTransfer
+		item.TokenTransfer = &transfer
+	}
+	if item.NFTTransfer != nil {
+		transfer := *item.NFTTransfer
+		item.NFTTransfer = &transfer
+	}
+	if item.InternalTransaction != nil {
+		tx := *item.InternalTransaction
+		item.InternalTransaction = &tx
+	}
+	return item
+}
+
+func cloneCall(call abi.Call) abi.Call {
+	call.Params = slices.Clone(call.Params)
+	return call
+}
+
+// pipelineItem 建立訂閱地址相關紀錄的 pipeline 項目，紀錄由呼叫端填入
+func pipelineItem(subscription repository.Subscription, kind string, processing blockProcessing) *usecase.PipelineItem {
+	return &usecase.PipelineItem{
+		Address: subscription.Address,
+		Filter:  toUsecaseFilter(subscription.Filter),
+		Kind:    kind,
+		Replay:  !processing.live,
+	}
+}
+
+// runPipeline 以 pipeline 處理與訂閱地址相關的紀錄，返回是否保留，保留時紀錄為處理階段修改後的結果
+func (p *EthereumParser) runPipeline(item *usecase.PipelineItem) bool {
+	keep, err := p.pipeline.Run(item)
+	if err != nil {
+		p.reportError(fmt.Sprintf("Error processing %s:", item.Kind), err)
+	}
+	return keep
+}
+
+// pipelineTransaction 以 pipeline 處理交易
+func (p *EthereumParser) pipelineTransaction(subscription repository.Subscription, tx repository.Transaction, processing blockProcessing) (repository.Transaction, bool) {
+	item := pipelineItem(subscription, domain.PipelineKindTransaction, processing)
+	value := toUsecaseTransaction(tx)
+	item.Transaction = &value
+	if !p.runPipeline(item) || item.Transaction == nil {
+		return tx, false
+	}
+	return toRepositoryTransaction(*item.Transaction), true
+}
+
+// pipelineTokenTransfer 以 pipeline 處理代幣轉帳
+func (p *EthereumParser) pipelineTokenTransfer(subscription repository.Subscription, transfer repository.TokenTransfer, processing blockProcessing) (repository.TokenTransfer, bool) {
+	item := pipelineItem(subscription, domain.PipelineKindTokenTransfer, processing)
+	value := toUsecaseTokenTransfer(transfer)
+	item.TokenTransfer = &value
+	if !p.runPipeline(item) || item.TokenTransfer == nil {
+		return transfer, false
+	}
+	return toRepositoryTokenTransfer(*item.TokenTransfer), true
+}
+
+// pipelineNFTTransfer 以 pipeline 處理 NFT 轉移
+func (p *EthereumParser) pipelineNFTTransfer(subscription repository.Subscription, transfer repository.NFTTransfer, processing blockProcessing) (repository.NFTTransfer, bool) {
+	item := pipelineItem(subscription, domain.PipelineKindNFTTransfer, processing)
+	value := toUsecaseNFTTransfer(transfer)
+	item.NFTTransfer = &value
+	if !p.runPipeline(item) || item.NFTTransfer == nil {
+		return transfer, false
+	}
+	return toRepositoryNFTTransfer(*item.NFTTransfer), true
+}
+
+// pipelineInternalTransaction 以 pipeline 處理內部交易
+func (p *EthereumParser) pipelineInternalTransaction(subscription repository.Subscription, tx repository.InternalTransaction, processing blockProcessing) (repository.InternalTransaction, bool) {
+	item := pipelineItem(subscription, domain.PipelineKindInternalTransaction, processing)
+	value := toUsecaseInternalTransaction(tx)
+	item.InternalTransaction = &value
+	if !p.runPipeline(item) || item.InternalTransaction == nil {
+		return tx, false
+	}
+	return toRepositoryInternalTransaction(*item.InternalTransaction), true
+}
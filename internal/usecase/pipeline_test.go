@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+// recordStage 記錄執行順序並返回固定結果的處理階段
+func recordStage(name string, order int, calls *[]string, keep bool, err error) usecase.PipelineStage {
+	return usecase.PipelineStage{
+		Name:  name,
+		Order: order,
+		Processor: ProcessorFunc(func(item *usecase.PipelineItem) (bool, error) {
+			*calls = append(*calls, name)
+			return keep, err
+		}),
+	}
+}
+
+func TestNewTransactionPipeline_Validation(t *testing.T) {
+	keep := ProcessorFunc(func(*usecase.PipelineItem) (bool, error) { return true, nil })
+
+	_, err := NewTransactionPipeline(usecase.PipelineStage{Processor: keep})
+	assert.ErrorIs(t, err, domain.ErrInvalidPipelineStage)
+	_, err = NewTransactionPipeline(usecase.PipelineStage{Name: "nil"})
+	assert.ErrorIs(t, err, domain.ErrInvalidPipelineStage)
+	_, err = NewTransactionPipeline(usecase.PipelineStage{Name: "policy", Processor: keep, OnError: "retry"})
+	assert.ErrorIs(t, err, domain.ErrInvalidPipelineStage)
+	_, err = NewTransactionPipeline(usecase.PipelineStage{Name: "retries", Processor: keep, Retries: -1})
+	assert.ErrorIs(t, err, domain.ErrInvalidPipelineStage)
+
+	pipeline, err := NewTransactionPipeline()
+	assert.NoError(t, err)
+	kept, err := pipeline.Run(&usecase.PipelineItem{})
+	assert.True(t, kept)
+	assert.NoError(t, err)
+}
+
+func TestTransactionPipeline_Order(t *testing.T) {
+	var calls []string
+	pipeline := MustTransactionPipeline(
+		recordStage("sink", 300, &calls, true, nil),
+		recordStage("decode", 100, &calls, true, nil),
+		recordStage("label", 200, &calls, true, nil),
+		recordStage("tag", 100, &calls, true, nil),
+	)
+
+	// Order 相同時依註冊順序執行
+	assert.Equal(t, []string{"decode", "tag", "label", "sink"}, pipeline.Stages())
+	kept, err := pipeline.Run(&usecase.PipelineItem{})
+	assert.True(t, kept)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"decode", "tag", "label", "sink"}, calls)
+}
+
+func TestTransactionPipeline_Filter(t *testing.T) {
+	var calls []string
+	pipeline := MustTransactionPipeline(
+		recordStage("filter", 1, &calls, false, nil),
+		recordStage("sink", 2, &calls, true, nil),
+	)
+
+	// filter 丟棄交易後不再執行後續的階段
+	kept, err := pipeline.Run(&usecase.PipelineItem{})
+	assert.False(t, kept)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"filter"}, calls)
+}
+
+func TestTransactionPipeline_ErrorPolicies(t *testing.T) {
+	var calls []string
+	lookup := recordStage("lookup", 1, &calls, true, errors.New("timeout"))
+	label := recordStage("label", 2, &calls, true, nil)
+	validate := recordStage("validate", 3, &calls, true, errors.New("invalid"))
+	validate.OnError = domain.PipelineOnErrorDrop
+	sink := recordStage("sink", 4, &calls, true, nil)
+	pipeline := MustTransactionPipeline(lookup, label, validate, sink)
+
+	// continue 略過失敗的階段，drop 丟棄交易，返回所有階段的錯誤
+	kept, err := pipeline.Run(&usecase.PipelineItem{})
+	assert.False(t, kept)
+	assert.EqualError(t, err, "pipeline stage lookup: timeout\npipeline stage validate: invalid")
+	assert.Equal(t, []string{"lookup", "label", "validate"}, calls)
+}
+
+func TestTransactionPipeline_Retries(t *testing.T) {
+	attempts := 0
+	flaky := usecase.PipelineStage{
+		Name:    "flaky",
+		OnError: domain.PipelineOnErrorDrop,
+		Retries: 2,
+		Processor: ProcessorFunc(func(item *usecase.PipelineItem) (bool, error) {
+			attempts++
+			if attempts < 3 {
+				return false, errors.New("unavailable")
+			}
+			return true, nil
+		}),
+	}
+	pipeline := MustTransactionPipeline(flaky)
+
+	kept, err := pipeline.Run(&usecase.PipelineItem{})
+	assert.True(t, kept)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// 重試次數用完仍失敗時依 OnError 處理
+	attempts = -10
+	kept, err = pipeline.Run(&usecase.PipelineItem{})
+	assert.False(t, kept)
+	assert.EqualError(t, err, "pipeline stage flaky: unavailable")
+	assert.Equal(t, -7, attempts)
+}
+
+func TestTransactionPipeline_RetryFreshCopy(t *testing.T) {
+	attempts := 0
+	var seen []map[string]string
+	flaky := usecase.PipelineStage{
+		Name:    "flaky",
+		Retries: 1,
+		Processor: ProcessorFunc(func(item *usecase.PipelineItem) (bool, error) {
+			attempts++
+			seen = append(seen, item.Transaction.Labels)
+			item.Transaction.Labels["attempt"] = "dirty"
+			item.Transaction.Hash = "0x2"
+			if attempts < 2 {
+				return false, errors.New("unavailable")
+			}
+			item.Transaction.Labels["attempt"] = "clean"
+			return true, nil
+		}),
+	}
+	pipeline := MustTransactionPipeline(flaky)
+
+	// 重試時使用未被失敗嘗試修改的副本，成功後保留所有修改
+	item := &usecase.PipelineItem{Transaction: &usecase.Transaction{Hash: "0x1", Labels: map[string]string{"to": "exchange"}}}
+	kept, err := pipeline.Run(item)
+	assert.True(t, kept)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"to": "exchange", "attempt": "dirty"}, {"to": "exchange", "attempt": "clean"}}, seen)
+	assert.Equal(t, "0x2", item.Transaction.Hash)
+	assert.Equal(t, map[string]string{"to": "exchange", "attempt": "clean"}, item.Transaction.Labels)
+
+	// 重試次數用完仍失敗時捨棄此階段的修改
+	attempts = -10
+	item = &usecase.PipelineItem{Transaction: &usecase.Transaction{Hash: "0x1", Labels: map[string]string{"to": "exchange"}}}
+	kept, err = pipeline.Run(item)
+	assert.True(t, kept)
+	assert.EqualError(t, err, "pipeline stage flaky: unavailable")
+	assert.Equal(t, "0x1", item.Transaction.Hash)
+	assert.Equal(t, map[string]string{"to": "exchange"}, item.Transaction.Labels)
+}
+
+func TestTransactionPipeline_Panic(t *testing.T) {
+	var calls []string
+	broken := usecase.PipelineStage{
+		Name: "broken",
+		Processor: ProcessorFunc(func(item *usecase.PipelineItem) (bool, error) {
+			panic("nil map")
+		}),
+	}
+	pipeline := MustTransactionPipeline(broken, recordStage("sink", 1, &calls, true, nil))
+
+	kept, err := pipeline.Run(&usecase.PipelineItem{})
+	assert.True(t, kept)
+	assert.EqualError(t, err, "pipeline stage broken: panic: nil map")
+	assert.Equal(t, []string{"sink"}, calls)
+}
+
+func TestFetchTransactionsForAddress_Pipeline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+	address := "0x0000000000000000000000000000000000000123"
+	spam := "0x2222222222222222222222222222222222222222222222222222222222222222"
+
+	// 丟棄 spam 交易，其餘的交易加上標籤並嘗試修改鏈上資料
+	var items []usecase.PipelineItem
+	pipeline := MustTransactionPipeline(
+		usecase.PipelineStage{Name: "spam", Order: 1, Processor: ProcessorFunc(func(item *usecase.PipelineItem) (bool, error) {
+			items = append(items, *item)
+			return item.Transaction.Hash != spam, nil
+		})},
+		usecase.PipelineStage{Name: "label", Order: 2, Processor: ProcessorFunc(func(item *usecase.PipelineItem) (bool, error) {
+			item.Transaction.Labels = map[string]string{"to": "exchange"}
+			item.Transaction.Method = &abi.Call{Name: "deposit"}
+			item.Transaction.Value = domain.BigInt{}
+			return true, nil
+		})},
+	)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+		Pipeline:     pipeline,
+	})
+
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"result": {
+			"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+			"transactions": [
+				{"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "0x0000000000000000000000000000000000000123", "to": "0x0000000000000000000000000000000000000456", "value": "0x10"},
+				{"hash": "0x2222222222222222222222222222222222222222222222222222222222222222", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000123", "value": "0x20"}
+			]
+		}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", gomock.Any()).Return(json.RawMessage(`{"result": null}`), nil).Times(2)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil)
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{Address: address}, true)
+	mockStorage.EXPECT().GetPendingTransactions(address).Return(nil)
+
+	var saved []repository.Transaction
+	mockStorage.EXPECT().SaveTransaction(address, gomock.Any()).Do(func(_ string, tx repository.Transaction) {
+		saved = append(saved, tx)
+	})
+	var notified []usecase.Transaction
+	mockNotification.EXPECT().Notify(address, gomock.Any()).Do(func(_ string, tx usecase.Transaction) {
+		notified = append(notified, tx)
+	})
+
+	parser.(*EthereumParser).FetchTransactionsForAddress(address)
+
+	assert.Len(t, items, 2)
+	assert.Equal(t, address, items[0].Address)
+	assert.False(t, items[0].Replay)
+
+	assert.Equal(t, domain.PipelineKindTransaction, items[0].Kind)
+
+	// 處理階段對紀錄的修改都會被保存與通知
+	assert.Len(t, saved, 1)
+	assert.Equal(t, "0x1111111111111111111111111111111111111111111111111111111111111111", saved[0].Hash)
+	assert.Equal(t, map[string]string{"to": "exchange"}, saved[0].Labels)
+	assert.Equal(t, "deposit", saved[0].Method.Name)
+	assert.Equal(t, "0", saved[0].Value.String())
+	assert.Equal(t, saved[0].Labels, notified[0].Labels)
+	assert.Equal(t, "0", notified[0].Value.String())
+}
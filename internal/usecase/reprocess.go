@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"time"
+)
+
+// 重新處理時同時處理的區塊數
+const (
+	defaultReprocessConcurrency = 4
+	maxReprocessConcurrency     = 16
+)
+
+// maxBackfillJobs 保留的工作數，超過時移除最早完成的工作
+const maxBackfillJobs = 20
+
+// StartReprocess 在背景重新處理歷史區塊，返回剛建立的工作，進度可由 Status 查詢
+func (p *EthereumParser) StartReprocess(request usecase.ReprocessRequest) (usecase.BackfillJob, error) {
+	job, subscriptions, err := p.prepareReprocess(request)
+	if err != nil {
+		return usecase.BackfillJob{}, err
+	}
+
+	go p.runReprocess(job, request, subscriptions)
+	return job, nil
+}
+
+// Reprocess 重新處理歷史區塊並等待完成
+func (p *EthereumParser) Reprocess(request usecase.ReprocessRequest) (usecase.BackfillJob, error) {
+	job, subscriptions, err := p.prepareReprocess(request)
+	if err != nil {
+		return usecase.BackfillJob{}, err
+	}
+
+	return p.runReprocess(job, request, subscriptions), nil
+}
+
+// prepareReprocess 驗證區塊範圍並決定要處理的地址，成功時建立工作
+func (p *EthereumParser) prepareReprocess(request usecase.ReprocessRequest) (usecase.BackfillJob, []repository.Subscription, error) {
+	// 只有 leader 保存與通知區塊活動，follower 重新處理會與 leader 重複寫入
+	if !p.isLeader() {
+		return usecase.BackfillJob{}, nil, domain.ErrNotLeader
+	}
+	if err := p.validateBlockRange(request.FromBlock, request.ToBlock); err != nil {
+		return usecase.BackfillJob{}, nil, err
+	}
+
+	subscriptions, err := p.reprocessSubscriptions(request.Addresses)
+	if err != nil {
+		return usecase.BackfillJob{}, nil, err
+	}
+	if len(subscriptions) == 0 {
+		return usecase.BackfillJob{}, nil, domain.ErrNothingToReprocess
+	}
+
+	addresses := make([]string, 0, len(request.Addresses))
+	if len(request.Addresses) > 0 {
+		for _, subscription := range subscriptions {
+			addresses = append(addresses, subscription.Address)
+		}
+	}
+	job := p.addBackfillJob("reprocess", usecase.BackfillJob{
+		FromBlock:    request.FromBlock,
+		ToBlock:      request.ToBlock,
+		Addresses:    addresses,
+		Notify:       request.Notify,
+		CurrentBlock: request.FromBlock - 1,
+		Status:       domain.BackfillStatusRunning,
+		StartedAt:    time.Now(),
+	})
+	return job, subscriptions, nil
+}
+
+// validateBlockRange 確認區塊範圍有效且不超過鏈上最新的區塊
+func (p *EthereumParser) validateBlockRange(fromBlock, toBlock int) error {
+	if fromBlock < 0 || fromBlock > toBlock {
+		return domain.ErrInvalidBlockRange
+	}
+
+	head, err := p.fetchBlockNumber()
+	if err != nil {
+		return err
+	}
+	if toBlock > head {
+		return fmt.Errorf("%w: %d > %d", domain.ErrBlockBeyondHead, toBlock, head)
+	}
+	return nil
+}
+
+// reprocessSubscriptions 未指定地址時處理所有訂閱，包含已暫停與已過期者
+// 指定的地址以其訂閱的過濾條件處理，未訂閱的地址則記錄所有相關的活動
+func (p *EthereumParser) reprocessSubscriptions(addresses []string) ([]repository.Subscription, error) {
+	if len(addresses) == 0 {
+		return p.storage.GetSubscriptions(), nil
+	}
+
+	seen := make(map[string]bool, len(addresses))
+	subscriptions := make([]repository.Subscription, 0, len(addresses))
+	for _, item := range addresses {
+		address, err := domain.NormalizeAddress(item)
+		if err != nil {
+			return nil, err
+		}
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		subscription, ok := p.storage.GetSubscription(address)
+		if !ok {
+			subscription = repository.Subscription{Address: address}
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+// runReprocess 同時下載多個區塊，並依區塊順序保存與通知，每個區塊只下載一次並由所有地址共用
+// 保存以唯一鍵 upsert，重複執行相同的範圍不會產生重複的紀錄；不更新待處理交易也不對帳餘額
+func (p *EthereumParser) runReprocess(job usecase.BackfillJob, request usecase.ReprocessRequest, subscriptions []repository.Subscription) usecase.BackfillJob {
+	fmt.Printf("Reprocessing blocks %d-%d for %d addresses (job %s)\n", request.FromBlock, request.ToBlock, len(subscriptions), job.ID)
+
+	processing := blockProcessing{notification: silentNotification{}}
+	if request.Notify {
+		processing.notification = p.notification
+	}
+
+	concurrency := request.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultReprocessConcurrency
+	}
+	concurrency = min(concurrency, maxReprocessConcurrency)
+
+	fetchOrdered(request.FromBlock, request.ToBlock, concurrency, 2*concurrency,
+		func(blockNumber int) blockFetch {
+			activity, err := p.fetchBlockForSubscriptions(blockNumber, subscriptions, processing)
+			return blockFetch{activity: activity, err: err}
+		},
+		func(blockNumber int, result blockFetch) bool {
+			err := result.err
+			if err != nil {
+				p.reportError("Error fetching block transactions:", err)
+			} else {
+				p.commitBlockActivity(result.activity, processing)
+				// 轉移事件或內部交易無法取得時仍保存其餘活動，並記錄為失敗以便再次執行
+				err = errors.Join(result.activity.logsErr, result.activity.traceErr)
+			}
+
+			p.updateBackfillJob(job.ID, func(job *usecase.BackfillJob) {
+				job.CurrentBlock = blockNumber
+				job.ProcessedBlocks++
+				if err != nil {
+					job.FailedBlocks++
+					if job.Error == "" {
+						job.Error = fmt.Sprintf("block %d: %v", blockNumber, err)
+					}
+				}
+			})
+			return true
+		})
+
+	job = p.updateBackfillJob(job.ID, func(job *usecase.BackfillJob) {
+		now := time.Now()
+		job.FinishedAt = &now
+		job.Status = domain.BackfillStatusCompleted
+		if job.FailedBlocks > 0 {
+			job.Status = domain.BackfillStatusFailed
+		}
+	})
+	fmt.Printf("Reprocessing job %s %s: %d blocks, %d failed\n", job.ID, job.Status, job.ProcessedBlocks, job.FailedBlocks)
+	return job
+}
+
+// addBackfillJob 以 kind 為前綴編號並記錄新的工作，工作數超過上限時移除最早完成的工作
+func (p *EthereumParser) addBackfillJob(kind string, job usecase.BackfillJob) usecase.BackfillJob {
+	p.status.mu.Lock()
+	defer p.status.mu.Unlock()
+
+	p.status.backfillSeq++
+	job.ID = fmt.Sprintf("%s-%d", kind, p.status.backfillSeq)
+	if len(p.status.backfillJobs) >= maxBackfillJobs {
+		for i, item := range p.status.backfillJobs {
+			if item.Status != domain.BackfillStatusRunning {
+				p.status.backfillJobs = append(p.status.backfillJobs[:i], p.status.backfillJobs[i+1:]...)
+				break
+			}
+		}
+	}
+	p.status.backfillJobs = append(p.status.backfillJobs, job)
+	return job
+}
+
+// updateBackfillJob 以 update 修改工作並返回修改後的副本
+func (p *EthereumParser) updateBackfillJob(id string, update func(job *usecase.BackfillJob)) usecase.BackfillJob {
+	p.status.mu.Lock()
+	defer p.status.mu.Unlock()
+
+	for i := range p.status.backfillJobs {
+		if p.status.backfillJobs[i].ID == id {
+			update(&p.status.backfillJobs[i])
+			return p.status.backfillJobs[i]
+		}
+	}
+	return usecase.BackfillJob{}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func TestReprocess_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+
+	_, err := parser.Reprocess(usecase.ReprocessRequest{FromBlock: 0x11, ToBlock: 0x10})
+	assert.ErrorIs(t, err, domain.ErrInvalidBlockRange)
+	_, err = parser.Reprocess(usecase.ReprocessRequest{FromBlock: -1, ToBlock: 0x10})
+	assert.ErrorIs(t, err, domain.ErrInvalidBlockRange)
+
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x14"}`), nil).Times(3)
+	_, err = parser.Reprocess(usecase.ReprocessRequest{FromBlock: 0x10, ToBlock: 0x15})
+	assert.ErrorIs(t, err, domain.ErrBlockBeyondHead)
+
+	_, err = parser.Reprocess(usecase.ReprocessRequest{FromBlock: 0x10, ToBlock: 0x14, Addresses: []string{"0x123"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+
+	mockStorage.EXPECT().GetSubscriptions().Return(nil)
+	_, err = parser.Reprocess(usecase.ReprocessRequest{FromBlock: 0x10, ToBlock: 0x14})
+	assert.ErrorIs(t, err, domain.ErrNothingToReprocess)
+	assert.Empty(t, parser.status.backfillJobs)
+
+	// follower 不接受重新處理的工作
+	mockLeadership := ucMock.NewMockLeadership(ctrl)
+	parser.leadership = mockLeadership
+	mockLeadership.EXPECT().IsLeader().Return(false)
+	_, err = parser.StartReprocess(usecase.ReprocessRequest{FromBlock: 0x10, ToBlock: 0x14})
+	assert.ErrorIs(t, err, domain.ErrNotLeader)
+	assert.Empty(t, parser.status.backfillJobs)
+}
+
+func TestReprocess_SelectedAddressesWithoutNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// 通知的 mock 沒有任何預期的呼叫，確認重新處理時不發送通知
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	subscribed := "0x0000000000000000000000000000000000000123"
+	unsubscribed := "0x0000000000000000000000000000000000000456"
+
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x20"}`), nil)
+	mockStorage.EXPECT().GetSubscription(subscribed).Return(repository.Subscription{
+		Address: subscribed,
+		Filter:  repository.SubscriptionFilter{Direction: domain.DirectionOutgoing},
+	}, true)
+	mockStorage.EXPECT().GetSubscription(unsubscribed).Return(repository.Subscription{}, false)
+
+	// 每個區塊只下載一次，由兩個地址共用
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).DoAndReturn(func(_ string, params []any) ([]byte, error) {
+		blockNumber := params[0].(string)
+		return []byte(fmt.Sprintf(`{"result": {
+			"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+			"number": "%s",
+			"transactions": [
+				{"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "%s", "to": "%s", "value": "0x64"}
+			]
+		}}`, blockNumber, subscribed, unsubscribed)), nil
+	}).Times(3)
+	// 兩個地址相關的同一筆交易只查詢一次收據
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", gomock.Any()).Return(json.RawMessage(`{"result": null}`), nil).Times(3)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil).Times(3)
+
+	// 依區塊順序保存
+	saved := make(map[string][]string)
+	mockStorage.EXPECT().SaveTransaction(gomock.Any(), gomock.Any()).Do(func(address string, tx repository.Transaction) {
+		saved[address] = append(saved[address], tx.BlockNumber)
+	}).Times(6)
+
+	job, err := parser.Reprocess(usecase.ReprocessRequest{
+		FromBlock:   0x10,
+		ToBlock:     0x12,
+		Addresses:   []string{subscribed, unsubscribed, subscribed},
+		Concurrency: 2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BackfillStatusCompleted, job.Status)
+	assert.Equal(t, []string{subscribed, unsubscribed}, job.Addresses)
+	assert.Equal(t, 0x12, job.CurrentBlock)
+	assert.Equal(t, 3, job.ProcessedBlocks)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Equal(t, []string{"0x10", "0x11", "0x12"}, saved[subscribed])
+	assert.Equal(t, []string{"0x10", "0x11", "0x12"}, saved[unsubscribed])
+
+	mockStorage.EXPECT().GetSubscriptions().Return(nil)
+	assert.Equal(t, []usecase.BackfillJob{job}, parser.Status().BackfillJobs)
+}
+
+func TestReprocess_FailedBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	address := "0x0000000000000000000000000000000000000123"
+
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x20"}`), nil)
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{{Address: address}})
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).DoAndReturn(func(_ string, params []any) ([]byte, error) {
+		if params[0] == "0x11" {
+			return nil, errors.New("timeout")
+		}
+		return []byte(`{"result": {"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000", "number": "0x10", "transactions": []}}`), nil
+	}).Times(2)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil)
+
+	job, err := parser.Reprocess(usecase.ReprocessRequest{FromBlock: 0x10, ToBlock: 0x11})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.BackfillStatusFailed, job.Status)
+	assert.Empty(t, job.Addresses)
+	assert.Equal(t, 0x11, job.CurrentBlock)
+	assert.Equal(t, 2, job.ProcessedBlocks)
+	assert.Equal(t, 1, job.FailedBlocks)
+	assert.Equal(t, "block 17: timeout", job.Error)
+}
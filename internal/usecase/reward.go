@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+)
+
+// computeBlockReward 以區塊的收據計算 fee recipient 的優先費收入與被銷毀的基本費用
+func (p *EthereumParser) computeBlockReward(block repository.Block) (repository.BlockReward, error) {
+	reward := repository.BlockReward{
+		BlockHash:        block.Hash.String(),
+		BlockNumber:      block.Number.String(),
+		FeeRecipient:     block.Miner.String(),
+		TransactionCount: len(block.Transactions),
+	}
+	if block.BaseFeePerGas != nil {
+		reward.BaseFeePerGas = block.BaseFeePerGas.BigInt
+	}
+	if len(block.Transactions) == 0 {
+		return reward, nil
+	}
+
+	receipts, err := p.fetchBlockReceipts(block)
+	if err != nil {
+		return repository.BlockReward{}, err
+	}
+	// 節點尚未索引區塊時 eth_getBlockReceipts 返回 null，缺少收據會算出錯誤的收入
+	if len(receipts) != len(block.Transactions) {
+		return repository.BlockReward{}, fmt.Errorf("block %s has %d transactions but %d receipts", block.Number, len(block.Transactions), len(receipts))
+	}
+
+	gasPrices := make(map[domain.Hash]domain.BigInt, len(block.Transactions))
+	for _, item := range block.Transactions {
+		gasPrices[item.Hash] = item.GasPrice.BigInt
+	}
+	for _, receipt := range receipts {
+		// deposit 交易不支付 L2 gas，不計入手續費收入
+		if domain.TxType(receipt.Type) == domain.TxTypeDeposit {
+			continue
+		}
+		gasUsed := domain.BigIntFromUint64(uint64(receipt.GasUsed))
+		// 舊節點的收據沒有 effectiveGasPrice，此時交易的 gasPrice 即為實際價格
+		price := gasPrices[receipt.TransactionHash]
+		if receipt.EffectiveGasPrice != nil {
+			price = receipt.EffectiveGasPrice.BigInt
+		}
+		reward.PriorityFees = reward.PriorityFees.Add(gasUsed.Mul(price.Sub(reward.BaseFeePerGas)))
+		reward.BurntFees = reward.BurntFees.Add(gasUsed.Mul(reward.BaseFeePerGas))
+	}
+
+	return reward, nil
+}
+
+// fetchBlockReceipts 以 eth_getBlockReceipts 一次取得區塊的所有收據，節點不支援時改為逐筆查詢
+func (p *EthereumParser) fetchBlockReceipts(block repository.Block) ([]repository.Receipt, error) {
+	if !p.blockReceiptsUnsupported.Load() {
+		receipts, err := p.fetchBlockReceiptsBatch(block.Number.String())
+		var rpcErr *repository.RPCError
+		if err == nil {
+			return receipts, nil
+		}
+		if !errors.As(err, &rpcErr) || !isMethodUnsupported(rpcErr, "eth_getBlockReceipts") {
+			return nil, err
+		}
+		fmt.Println("eth_getBlockReceipts is not supported by provider, falling back to eth_getTransactionReceipt:", err)
+		p.blockReceiptsUnsupported.Store(true)
+	}
+
+	receipts := make([]repository.Receipt, 0, len(block.Transactions))
+	for _, item := range block.Transactions {
+		receipt, err := p.fetchTransactionReceipt(item.Hash.String())
+		if err != nil {
+			return nil, err
+		}
+		if receipt == nil {
+			return nil, fmt.Errorf("receipt not found for transaction %s", item.Hash)
+		}
+		receipts = append(receipts, *receipt)
+	}
+	return receipts, nil
+}
+
+func (p *EthereumParser) fetchBlockReceiptsBatch(blockNumber string) ([]repository.Receipt, error) {
+	result, err := p.ethClient.CallEthereum("eth_getBlockReceipts", []any{blockNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResponse repository.BlockReceiptsResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return nil, err
+	}
+	if rpcResponse.Error != nil {
+		return nil, rpcResponse.Error
+	}
+
+	return rpcResponse.Result, nil
+}
+
+func toUsecaseBlockReward(reward repository.BlockReward) usecase.BlockReward {
+	return usecase.BlockReward{
+		BlockHash:        reward.BlockHash,
+		BlockNumber:      reward.BlockNumber,
+		FeeRecipient:     reward.FeeRecipient,
+		BaseFeePerGas:    reward.BaseFeePerGas,
+		PriorityFees:     reward.PriorityFees,
+		BurntFees:        reward.BurntFees,
+		TransactionCount: reward.TransactionCount,
+	}
+}
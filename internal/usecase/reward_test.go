@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain/repository"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+const rewardTestBlock = `{
+	"result": {
+		"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+		"number": "0x10",
+		"miner": "0x0000000000000000000000000000000000000123",
+		"baseFeePerGas": "0x64",
+		"transactions": [
+			{"hash": "0x1111111111111111111111111111111111111111111111111111111111111111", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000456", "value": "0x0", "gasPrice": "0x96"},
+			{"hash": "0x2222222222222222222222222222222222222222222222222222222222222222", "from": "0x0000000000000000000000000000000000000789", "to": "0x0000000000000000000000000000000000000456", "value": "0x0", "gasPrice": "0xc8"}
+		]
+	}
+}`
+
+func TestFetchTransactionsForAddress_BlockReward(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	})
+
+	address := "0x0000000000000000000000000000000000000123"
+
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(json.RawMessage(rewardTestBlock), nil)
+	// 第二筆收據沒有 effectiveGasPrice，以交易的 gasPrice 計算
+	mockClient.EXPECT().CallEthereum("eth_getBlockReceipts", []any{"0x10"}).Return(json.RawMessage(`{
+		"result": [
+			{"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111", "gasUsed": "0x5208", "effectiveGasPrice": "0x78"},
+			{"transactionHash": "0x2222222222222222222222222222222222222222222222222222222222222222", "gasUsed": "0xa"}
+		]
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil)
+
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{Address: address}, true)
+	mockStorage.EXPECT().GetPendingTransactions(address).Return(nil)
+
+	var saved repository.BlockReward
+	mockStorage.EXPECT().SaveBlockReward(address, gomock.Any()).Do(func(_ string, reward repository.BlockReward) {
+		saved = reward
+	})
+	mockNotification.EXPECT().NotifyBlockReward(address, gomock.Any())
+
+	parser.(*EthereumParser).FetchTransactionsForAddress(address)
+
+	// 21000 × (120 - 100) + 10 × (200 - 100)
+	assert.Equal(t, "421000", saved.PriorityFees.String())
+	// (21000 + 10) × 100
+	assert.Equal(t, "2101000", saved.BurntFees.String())
+	assert.Equal(t, "100", saved.BaseFeePerGas.String())
+	assert.Equal(t, "0x10", saved.BlockNumber)
+	assert.Equal(t, 2, saved.TransactionCount)
+}
+
+func TestComputeBlockReward_FallbackToTransactionReceipts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{EthClient: mockClient}).(*EthereumParser)
+
+	var blockResult repository.BlockResult
+	assert.NoError(t, json.Unmarshal([]byte(rewardTestBlock), &blockResult))
+	block := blockResult.Result
+
+	mockClient.EXPECT().CallEthereum("eth_getBlockReceipts", gomock.Any()).Return(json.RawMessage(`{
+		"error": {"code": -32601, "message": "the method eth_getBlockReceipts does not exist/is not available"}
+	}`), nil).Times(1)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", []any{"0x1111111111111111111111111111111111111111111111111111111111111111"}).Return(json.RawMessage(`{
+		"result": {"transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111", "gasUsed": "0x5208", "effectiveGasPrice": "0x78"}
+	}`), nil).Times(2)
+	mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", []any{"0x2222222222222222222222222222222222222222222222222222222222222222"}).Return(json.RawMessage(`{
+		"result": {"transactionHash": "0x2222222222222222222222222222222222222222222222222222222222222222", "gasUsed": "0xa"}
+	}`), nil).Times(2)
+
+	// 第二次計算時不再嘗試 eth_getBlockReceipts
+	for i := 0; i < 2; i++ {
+		reward, err := parser.computeBlockReward(block)
+		assert.NoError(t, err)
+		assert.Equal(t, "421000", reward.PriorityFees.String())
+	}
+}
+
+func TestComputeBlockReward_MissingReceipts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{EthClient: mockClient}).(*EthereumParser)
+
+	var blockResult repository.BlockResult
+	assert.NoError(t, json.Unmarshal([]byte(rewardTestBlock), &blockResult))
+	block := blockResult.Result
+
+	// 節點尚未索引區塊時返回錯誤，且不停用 eth_getBlockReceipts
+	gomock.InOrder(
+		mockClient.EXPECT().CallEthereum("eth_getBlockReceipts", gomock.Any()).Return(json.RawMessage(`{"result": null}`), nil),
+		mockClient.EXPECT().CallEthereum("eth_getBlockReceipts", gomock.Any()).Return(json.RawMessage(`{
+			"error": {"code": -32000, "message": "block not found"}
+		}`), nil),
+	)
+	for i := 0; i < 2; i++ {
+		_, err := parser.computeBlockReward(block)
+		assert.Error(t, err)
+	}
+	assert.False(t, parser.blockReceiptsUnsupported.Load())
+}
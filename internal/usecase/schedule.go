@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"time"
+)
+
+// 輪詢間隔的預設值，出塊間隔在學習到之前以以太坊主網的 12 秒估計
+const (
+	defaultMinPollInterval = 200 * time.Millisecond
+	defaultMaxPollInterval = 30 * time.Second
+	defaultBlockTime       = 12 * time.Second
+)
+
+// pollSlack 預期出塊時間之後再等待的時間，讓節點有時間收到新區塊
+const pollSlack = 500 * time.Millisecond
+
+// blockTimeSmoothing 更新出塊間隔估計時新樣本的權重（1/n）
+const blockTimeSmoothing = 4
+
+type PollScheduleParam struct {
+	// MinInterval 最短的輪詢間隔，追趕落後的區塊時使用，為 0 時使用預設值
+	MinInterval time.Duration
+	// MaxInterval 最長的輪詢間隔，沒有新區塊時逐步退避至此，為 0 時使用預設值
+	MaxInterval time.Duration
+	// BlockTime 固定的出塊間隔，為 0 時由區塊的 timestamp 學習
+	BlockTime time.Duration
+}
+
+// pollSchedule 決定下次輪詢的時間：在預期的下一個區塊產生後輪詢，落後時以最短間隔追趕，沒有新區塊時指數退避
+// 只由 PollForChanges 的 goroutine 使用
+type pollSchedule struct {
+	minInterval time.Duration
+	maxInterval time.Duration
+	// blockTime 出塊間隔的估計值，fixed 時不再學習
+	blockTime time.Duration
+	fixed     bool
+	learned   bool
+
+	lastBlock     int
+	lastTimestamp time.Time
+	// idlePolls 連續沒有新區塊或失敗的輪詢次數
+	idlePolls int
+}
+
+func newPollSchedule(param PollScheduleParam) *pollSchedule {
+	s := &pollSchedule{
+		minInterval: param.MinInterval,
+		maxInterval: param.MaxInterval,
+		blockTime:   param.BlockTime,
+		fixed:       param.BlockTime > 0,
+	}
+	if s.minInterval <= 0 {
+		s.minInterval = defaultMinPollInterval
+	}
+	if s.maxInterval <= 0 {
+		s.maxInterval = defaultMaxPollInterval
+	}
+	s.maxInterval = max(s.maxInterval, s.minInterval)
+	if !s.fixed {
+		s.blockTime = defaultBlockTime
+	}
+	return s
+}
+
+// observe 記錄處理完的區塊，以相鄰區塊 timestamp 的差距平滑地更新出塊間隔
+func (s *pollSchedule) observe(blockNumber int, timestamp time.Time) {
+	s.idlePolls = 0
+	if timestamp.IsZero() || blockNumber <= s.lastBlock {
+		return
+	}
+
+	if !s.fixed && s.lastBlock > 0 && timestamp.After(s.lastTimestamp) {
+		sample := timestamp.Sub(s.lastTimestamp) / time.Duration(blockNumber-s.lastBlock)
+		if s.learned {
+			s.blockTime += (sample - s.blockTime) / blockTimeSmoothing
+		} else {
+			s.blockTime = sample
+			s.learned = true
+		}
+	}
+	s.lastBlock = blockNumber
+	s.lastTimestamp = timestamp
+}
+
+// catchingUp 仍落後最新區塊時以最短間隔繼續處理
+func (s *pollSchedule) catchingUp() time.Duration {
+	return s.minInterval
+}
+
+// untilNextBlock 處理完最新區塊後，等到預期的下一個區塊產生後再輪詢
+func (s *pollSchedule) untilNextBlock(now time.Time) time.Duration {
+	if s.lastTimestamp.IsZero() {
+		return s.clamp(s.blockTime)
+	}
+	return s.clamp(s.lastTimestamp.Add(s.blockTime + pollSlack).Sub(now))
+}
+
+// idle 預期的區塊尚未產生或輪詢失敗時，從出塊間隔的 1/4 開始每次加倍等待時間
+func (s *pollSchedule) idle() time.Duration {
+	s.idlePolls++
+	delay := s.blockTime / 4
+	for i := 1; i < s.idlePolls && delay < s.maxInterval; i++ {
+		delay *= 2
+	}
+	return s.clamp(delay)
+}
+
+func (s *pollSchedule) clamp(delay time.Duration) time.Duration {
+	return min(max(delay, s.minInterval), s.maxInterval)
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain/repository"
+	"testing"
+	"time"
+)
+
+func TestPollSchedule_LearnsBlockTime(t *testing.T) {
+	s := newPollSchedule(PollScheduleParam{})
+	assert.Equal(t, defaultBlockTime, s.blockTime)
+
+	start := time.Unix(1700000000, 0)
+	s.observe(100, start)
+	assert.Equal(t, defaultBlockTime, s.blockTime)
+
+	// 第一個樣本直接取代預設值，之後平滑地更新，跳過的區塊以平均間隔計算
+	s.observe(101, start.Add(2*time.Second))
+	assert.Equal(t, 2*time.Second, s.blockTime)
+	s.observe(103, start.Add(8*time.Second))
+	assert.Equal(t, 2*time.Second+250*time.Millisecond, s.blockTime)
+
+	// 舊的區塊與相同的 timestamp 不影響估計
+	s.observe(102, start.Add(20*time.Second))
+	s.observe(104, start.Add(8*time.Second))
+	assert.Equal(t, 2*time.Second+250*time.Millisecond, s.blockTime)
+	assert.Equal(t, 104, s.lastBlock)
+}
+
+func TestPollSchedule_FixedBlockTime(t *testing.T) {
+	s := newPollSchedule(PollScheduleParam{BlockTime: 12 * time.Second})
+
+	start := time.Unix(1700000000, 0)
+	s.observe(100, start)
+	s.observe(101, start.Add(2*time.Second))
+	assert.Equal(t, 12*time.Second, s.blockTime)
+}
+
+func TestPollSchedule_Delays(t *testing.T) {
+	s := newPollSchedule(PollScheduleParam{MinInterval: time.Second, MaxInterval: 20 * time.Second, BlockTime: 12 * time.Second})
+	start := time.Unix(1700000000, 0)
+	s.observe(100, start)
+
+	// 預期的下一個區塊產生後再稍等一下
+	assert.Equal(t, 12*time.Second+pollSlack, s.untilNextBlock(start))
+	assert.Equal(t, 2*time.Second+pollSlack, s.untilNextBlock(start.Add(10*time.Second)))
+	// 已經超過預期時間時以最短間隔輪詢
+	assert.Equal(t, time.Second, s.untilNextBlock(start.Add(time.Minute)))
+	assert.Equal(t, time.Second, s.catchingUp())
+
+	// 沒有新區塊時從出塊間隔的 1/4 開始加倍，最多等待 MaxInterval
+	assert.Equal(t, 3*time.Second, s.idle())
+	assert.Equal(t, 6*time.Second, s.idle())
+	assert.Equal(t, 12*time.Second, s.idle())
+	assert.Equal(t, 20*time.Second, s.idle())
+	assert.Equal(t, 20*time.Second, s.idle())
+
+	// 收到新區塊後重新開始退避
+	s.observe(101, start.Add(12*time.Second))
+	assert.Equal(t, 3*time.Second, s.idle())
+}
+
+func TestPollOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	parser.schedule = newPollSchedule(PollScheduleParam{MinInterval: time.Second, MaxInterval: time.Minute, BlockTime: 12 * time.Second})
+	parser.currentBlock = 0x10
+	parser.lastENSCheck = time.Now()
+	mockStorage.EXPECT().GetSubscriptions().Return(nil).AnyTimes()
+	mockStorage.EXPECT().GetEventSubscriptions().Return(nil).AnyTimes()
+	mockStorage.EXPECT().GetLastProcessedBlock().Return(0, false)
+	mockStorage.EXPECT().SaveLastProcessedBlock(0x11)
+	mockStorage.EXPECT().SaveLastProcessedBlock(0x12)
+
+	// 落後兩個區塊時依序處理，追上最新區塊後等到預期的下一個區塊，出塊時間早已過去時以最短間隔輪詢
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x12"}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", []any{"0x11", false}).Return(json.RawMessage(`{"result": {"number": "0x11", "timestamp": "0x6553f100"}}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", []any{"0x12", false}).Return(json.RawMessage(`{"result": {"number": "0x12", "timestamp": "0x6553f10c"}}`), nil)
+	assert.Equal(t, time.Second, parser.pollOnce())
+	assert.Equal(t, 0x12, parser.currentBlock)
+	assert.Equal(t, 0x12, parser.Status().LastProcessedBlock)
+	assert.Equal(t, 2, parser.Status().BlocksPerMinute)
+
+	// 沒有新區塊與節點錯誤時退避，不會立即重試
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x12"}`), nil)
+	assert.Equal(t, 3*time.Second, parser.pollOnce())
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(nil, errors.New("timeout"))
+	assert.Equal(t, 6*time.Second, parser.pollOnce())
+	assert.Equal(t, 0x12, parser.currentBlock)
+}
+
+func TestPollOnce_FetchError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+	parser.schedule = newPollSchedule(PollScheduleParam{MinInterval: time.Second, MaxInterval: time.Minute, BlockTime: 12 * time.Second})
+	parser.currentBlock = 0x10
+	parser.lastENSCheck = time.Now()
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{{Address: "0x0000000000000000000000000000000000000001"}}).AnyTimes()
+	mockStorage.EXPECT().GetEventSubscriptions().Return(nil).AnyTimes()
+	mockStorage.EXPECT().GetLastProcessedBlock().Return(0, false)
+
+	// 區塊下載失敗時不保存也不推進目前區塊，退避後由同一個區塊重試
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x12"}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(nil, errors.New("timeout")).AnyTimes()
+	assert.Equal(t, 3*time.Second, parser.pollOnce())
+	assert.Equal(t, 0x10, parser.currentBlock)
+	assert.Equal(t, 0, parser.status.lastProcessedBlock)
+	assert.Equal(t, "Error fetching block 17 transactions: timeout", parser.status.lastError.Message)
+}
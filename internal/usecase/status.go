@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"fmt"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/usecase"
+	"sync"
+	"time"
+)
+
+// throughputWindow 計算每分鐘處理區塊數的時間窗口
+const throughputWindow = time.Minute
+
+// parserStatus 輪詢的狀態，由 PollForChanges 更新、HTTP handler 讀取，因此以互斥鎖保護
+type parserStatus struct {
+	mu                 sync.Mutex
+	chainHead          int
+	lastProcessedBlock int
+	// lastProcessedTime 最後處理區塊的 timestamp，用於計算落後的秒數
+	lastProcessedTime time.Time
+	lastError         *usecase.ErrorStatus
+	// blockTime 輪詢使用的出塊間隔估計值
+	blockTime time.Duration
+	// processedAt 最近一分鐘內處理完區塊的時間
+	processedAt  []time.Time
+	backfillJobs []usecase.BackfillJob
+	// backfillSeq 已建立的工作數，用於產生工作編號
+	backfillSeq int
+}
+
+// reportError 輸出錯誤並記錄為最後一次錯誤
+func (p *EthereumParser) reportError(message string, err error) {
+	fmt.Println(message, err)
+
+	p.status.mu.Lock()
+	defer p.status.mu.Unlock()
+	p.status.lastError = &usecase.ErrorStatus{
+		Message:    fmt.Sprint(message, " ", err),
+		OccurredAt: time.Now(),
+	}
+}
+
+// setChainHead 記錄節點返回的最新區塊號
+func (p *EthereumParser) setChainHead(blockNumber int) {
+	p.status.mu.Lock()
+	defer p.status.mu.Unlock()
+	p.status.chainHead = blockNumber
+}
+
+// setBlockTime 記錄輪詢使用的出塊間隔
+func (p *EthereumParser) setBlockTime(blockTime time.Duration) {
+	p.status.mu.Lock()
+	defer p.status.mu.Unlock()
+	p.status.blockTime = blockTime
+}
+
+// markBlockProcessed 記錄所有訂閱皆已處理完的區塊
+func (p *EthereumParser) markBlockProcessed(blockNumber int, timestamp time.Time) {
+	now := time.Now()
+
+	p.status.mu.Lock()
+	defer p.status.mu.Unlock()
+	p.status.lastProcessedBlock = blockNumber
+	if !timestamp.IsZero() {
+		p.status.lastProcessedTime = timestamp
+	}
+	p.status.processedAt = append(trimBefore(p.status.processedAt, now.Add(-throughputWindow)), now)
+}
+
+// trimBefore 移除早於 since 的時間，times 依時間排序
+func trimBefore(times []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(since) {
+		i++
+	}
+	return times[i:]
+}
+
+// blockTimestamp 取得區塊的時間，無法取得時返回零值
+func (p *EthereumParser) blockTimestamp(blockNumber string) time.Time {
+	header, err := p.fetchBlockHeader(blockNumber)
+	if err != nil {
+		p.reportError("Error fetching block header:", err)
+		return time.Time{}
+	}
+	return time.Unix(int64(header.Timestamp), 0)
+}
+
+// Status 取得 Parser 的處理進度，落後秒數以最後處理區塊的 timestamp 計算
+func (p *EthereumParser) Status() usecase.Status {
+	now := time.Now()
+	status := usecase.Status{Leader: p.isLeader(), CheckedAt: now}
+	for _, subscription := range p.storage.GetSubscriptions() {
+		status.Subscriptions++
+		if subscriptionStatus(subscription, now) == domain.SubscriptionStatusActive {
+			status.ActiveSubscriptions++
+		}
+	}
+
+	p.status.mu.Lock()
+	defer p.status.mu.Unlock()
+	status.ChainHead = p.status.chainHead
+	status.LastProcessedBlock = p.status.lastProcessedBlock
+	if p.status.lastProcessedBlock > 0 && p.status.chainHead > p.status.lastProcessedBlock {
+		status.LagBlocks = p.status.chainHead - p.status.lastProcessedBlock
+	}
+	if !p.status.lastProcessedTime.IsZero() {
+		status.LagSeconds = int64(now.Sub(p.status.lastProcessedTime).Seconds())
+	}
+	status.LastError = p.status.lastError
+	status.BlockTimeSeconds = p.status.blockTime.Seconds()
+	status.BlocksPerMinute = len(trimBefore(p.status.processedAt, now.Add(-throughputWindow)))
+	status.BackfillJobs = append([]usecase.BackfillJob{}, p.status.backfillJobs...)
+	return status
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain/repository"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{
+		{Address: "0x0000000000000000000000000000000000000001"},
+		{Address: "0x0000000000000000000000000000000000000002", Paused: true},
+	}).AnyTimes()
+
+	// 尚未處理任何區塊
+	status := parser.Status()
+	assert.Equal(t, 0, status.ChainHead)
+	assert.Equal(t, 0, status.LagBlocks)
+	assert.Nil(t, status.LastError)
+	assert.Equal(t, 2, status.Subscriptions)
+	assert.Equal(t, 1, status.ActiveSubscriptions)
+	assert.Empty(t, status.BackfillJobs)
+
+	mockClient.EXPECT().CallEthereum("eth_blockNumber", gomock.Any()).Return(json.RawMessage(`{"result": "0x14"}`), nil)
+	assert.NoError(t, parser.UpdateCurrentBlock())
+	parser.markBlockProcessed(0x10, time.Now().Add(-30*time.Second))
+	parser.markBlockProcessed(0x11, time.Now().Add(-24*time.Second))
+	parser.reportError("Error fetching block transactions:", errors.New("timeout"))
+
+	status = parser.Status()
+	assert.Equal(t, 0x14, status.ChainHead)
+	assert.Equal(t, 0x11, status.LastProcessedBlock)
+	assert.Equal(t, 3, status.LagBlocks)
+	assert.InDelta(t, 24, status.LagSeconds, 1)
+	assert.Equal(t, 2, status.BlocksPerMinute)
+	assert.Equal(t, "Error fetching block transactions: timeout", status.LastError.Message)
+}
+
+func TestTrimBefore(t *testing.T) {
+	now := time.Now()
+	times := []time.Time{now.Add(-2 * time.Minute), now.Add(-time.Minute - time.Second), now.Add(-time.Second), now}
+
+	assert.Equal(t, times[2:], trimBefore(times, now.Add(-time.Minute)))
+	assert.Empty(t, trimBefore(times, now.Add(time.Second)))
+	assert.Empty(t, trimBefore(nil, now))
+}
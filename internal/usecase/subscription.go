@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"fmt"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"time"
+)
+
+// Subscribe 訂閱地址或 ENS 名稱，地址與過濾條件會先驗證並轉為標準格式後才寫入 Storage
+// 重複訂閱時更新過濾條件與描述資料，保留原本的建立時間與暫停狀態；同一地址以名稱與地址訂閱時兩者皆記錄
+func (p *EthereumParser) Subscribe(subscription usecase.Subscription) error {
+	address, name, err := p.ResolveAddress(subscription.Address)
+	if err != nil {
+		return err
+	}
+	filter, err := toRepositoryFilter(subscription.Filter)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if subscription.ExpiresAt != nil && !subscription.ExpiresAt.After(now) {
+		return domain.ErrExpiresInPast
+	}
+
+	reply := repository.Subscription{
+		Address:   address,
+		ENSName:   name,
+		Direct:    name == "",
+		Filter:    filter,
+		Label:     subscription.Label,
+		Owner:     subscription.Owner,
+		CreatedAt: now,
+		ExpiresAt: subscription.ExpiresAt,
+	}
+	existing, exists := p.storage.GetSubscription(address)
+	if exists {
+		reply.CreatedAt = existing.CreatedAt
+		reply.Paused = existing.Paused
+		if name == "" {
+			reply.ENSName = existing.ENSName
+		} else {
+			reply.Direct = isDirectSubscription(existing)
+		}
+	}
+
+	p.storage.SubscribeAddress(reply)
+	if p.trackBalances && !exists {
+		p.recordBalanceBaseline(address)
+	}
+	return nil
+}
+
+// Unsubscribe 取消訂閱，已記錄的交易仍可查詢
+func (p *EthereumParser) Unsubscribe(address string) error {
+	address, err := p.subscriptionAddress(address)
+	if err != nil {
+		return err
+	}
+	if !p.storage.UnsubscribeAddress(address) {
+		return domain.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// PauseSubscription 暫停訂閱，暫停期間的區塊不會被補處理
+func (p *EthereumParser) PauseSubscription(address string) error {
+	_, err := p.setSubscriptionPaused(address, true)
+	return err
+}
+
+// ResumeSubscription 恢復已暫停的訂閱，暫停期間的餘額變化不會被對帳，因此重新記錄餘額基準
+func (p *EthereumParser) ResumeSubscription(address string) error {
+	address, err := p.setSubscriptionPaused(address, false)
+	if err != nil {
+		return err
+	}
+	if p.trackBalances {
+		p.recordBalanceBaseline(address)
+	}
+	return nil
+}
+
+// setSubscriptionPaused 暫停或恢復訂閱，返回訂閱的地址
+func (p *EthereumParser) setSubscriptionPaused(address string, paused bool) (string, error) {
+	address, err := p.subscriptionAddress(address)
+	if err != nil {
+		return "", err
+	}
+	if !p.storage.SetSubscriptionPaused(address, paused) {
+		return "", domain.ErrSubscriptionNotFound
+	}
+	return address, nil
+}
+
+// GetSubscription 取得指定地址的訂閱
+func (p *EthereumParser) GetSubscription(address string) (usecase.Subscription, error) {
+	address, err := p.subscriptionAddress(address)
+	if err != nil {
+		return usecase.Subscription{}, err
+	}
+	subscription, ok := p.storage.GetSubscription(address)
+	if !ok {
+		return usecase.Subscription{}, domain.ErrSubscriptionNotFound
+	}
+	return toUsecaseSubscription(subscription, time.Now()), nil
+}
+
+// GetSubscriptions 取得所有訂閱
+func (p *EthereumParser) GetSubscriptions() []usecase.Subscription {
+	r := p.storage.GetSubscriptions()
+	now := time.Now()
+	result := make([]usecase.Subscription, 0, len(r))
+	for _, item := range r {
+		result = append(result, toUsecaseSubscription(item, now))
+	}
+
+	return result
+}
+
+// activeSubscriptions 取得未暫停且未過期的訂閱
+func (p *EthereumParser) activeSubscriptions() []repository.Subscription {
+	now := time.Now()
+	var result []repository.Subscription
+	for _, subscription := range p.storage.GetSubscriptions() {
+		if subscriptionStatus(subscription, now) == domain.SubscriptionStatusActive {
+			result = append(result, subscription)
+		}
+	}
+	return result
+}
+
+// removeExpiredSubscriptions 移除已過期的訂閱，避免訂閱數量無限增長
+func (p *EthereumParser) removeExpiredSubscriptions() {
+	now := time.Now()
+	for _, subscription := range p.storage.GetSubscriptions() {
+		if subscriptionStatus(subscription, now) == domain.SubscriptionStatusExpired {
+			p.storage.UnsubscribeAddress(subscription.Address)
+			fmt.Printf("Subscription expired: %s\n", subscription.Address)
+		}
+	}
+}
+
+// isDirectSubscription 判斷訂閱是否以地址直接建立，沒有 ENSName 的訂閱一定是以地址建立
+func isDirectSubscription(subscription repository.Subscription) bool {
+	return subscription.Direct || subscription.ENSName == ""
+}
+
+// subscriptionStatus 依過期時間與暫停狀態計算訂閱狀態，過期優先於暫停
+func subscriptionStatus(subscription repository.Subscription, now time.Time) string {
+	switch {
+	case subscription.ExpiresAt != nil && !now.Before(*subscription.ExpiresAt):
+		return domain.SubscriptionStatusExpired
+	case subscription.Paused:
+		return domain.SubscriptionStatusPaused
+	}
+	return domain.SubscriptionStatusActive
+}
+
+func toUsecaseSubscription(subscription repository.Subscription, now time.Time) usecase.Subscription {
+	return usecase.Subscription{
+		Address:   subscription.Address,
+		ENSName:   subscription.ENSName,
+		Filter:    toUsecaseFilter(subscription.Filter),
+		Label:     subscription.Label,
+		Owner:     subscription.Owner,
+		CreatedAt: subscription.CreatedAt,
+		ExpiresAt: subscription.ExpiresAt,
+		Paused:    subscription.Paused,
+		Status:    subscriptionStatus(subscription, now),
+	}
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+	"time"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func newSubscriptionTestParser(ctrl *gomock.Controller) (*EthereumParser, *repoMock.MockStorage, *repoMock.MockETHClient) {
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: ucMock.NewMockNotification(ctrl),
+		EthClient:    mockClient,
+	})
+	return parser.(*EthereumParser), mockStorage, mockClient
+}
+
+func TestSubscribe_Metadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, _ := newSubscriptionTestParser(ctrl)
+	address := "0x0000000000000000000000000000000000000123"
+	createdAt := time.Now().Add(-time.Hour)
+	expiresAt := time.Now().Add(time.Hour)
+
+	// 重複訂閱時保留建立時間與暫停狀態
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{Address: address, CreatedAt: createdAt, Paused: true}, true)
+	mockStorage.EXPECT().SubscribeAddress(repository.Subscription{
+		Address:   address,
+		Direct:    true,
+		Filter:    repository.SubscriptionFilter{Direction: domain.DirectionBoth},
+		Label:     "treasury",
+		Owner:     "ops",
+		CreatedAt: createdAt,
+		ExpiresAt: &expiresAt,
+		Paused:    true,
+	})
+
+	err := parser.Subscribe(usecase.Subscription{Address: address, Label: "treasury", Owner: "ops", ExpiresAt: &expiresAt})
+	assert.NoError(t, err)
+
+	past := time.Now().Add(-time.Minute)
+	err = parser.Subscribe(usecase.Subscription{Address: address, ExpiresAt: &past})
+	assert.Equal(t, domain.ErrExpiresInPast, err)
+}
+
+func TestSubscriptionLifecycle(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000123"
+
+	tests := []struct {
+		name        string
+		address     string
+		call        func(p *EthereumParser, address string) error
+		mock        func(m *repoMock.MockStorage)
+		expectedErr error
+	}{
+		{
+			name:    "Unsubscribe",
+			address: address,
+			call:    (*EthereumParser).Unsubscribe,
+			mock: func(m *repoMock.MockStorage) {
+				m.EXPECT().UnsubscribeAddress(address).Return(true)
+			},
+		},
+		{
+			name:    "Unsubscribe unknown address",
+			address: address,
+			call:    (*EthereumParser).Unsubscribe,
+			mock: func(m *repoMock.MockStorage) {
+				m.EXPECT().UnsubscribeAddress(address).Return(false)
+			},
+			expectedErr: domain.ErrSubscriptionNotFound,
+		},
+		{
+			name:    "Pause checksum address",
+			address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			call:    (*EthereumParser).PauseSubscription,
+			mock: func(m *repoMock.MockStorage) {
+				m.EXPECT().SetSubscriptionPaused("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true).Return(true)
+			},
+		},
+		{
+			name:    "Resume",
+			address: address,
+			call:    (*EthereumParser).ResumeSubscription,
+			mock: func(m *repoMock.MockStorage) {
+				m.EXPECT().SetSubscriptionPaused(address, false).Return(true)
+			},
+		},
+		{
+			name:    "Resume unknown address",
+			address: address,
+			call:    (*EthereumParser).ResumeSubscription,
+			mock: func(m *repoMock.MockStorage) {
+				m.EXPECT().SetSubscriptionPaused(address, false).Return(false)
+			},
+			expectedErr: domain.ErrSubscriptionNotFound,
+		},
+		{
+			name:        "Invalid address",
+			address:     "0x123",
+			call:        (*EthereumParser).PauseSubscription,
+			mock:        func(m *repoMock.MockStorage) {},
+			expectedErr: domain.ErrInvalidAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			parser, mockStorage, _ := newSubscriptionTestParser(ctrl)
+			tt.mock(mockStorage)
+
+			err := tt.call(parser, tt.address)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestGetSubscriptions_Status(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, _ := newSubscriptionTestParser(ctrl)
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{
+		{Address: "0x0000000000000000000000000000000000000001"},
+		{Address: "0x0000000000000000000000000000000000000002", Paused: true},
+		{Address: "0x0000000000000000000000000000000000000003", ExpiresAt: &past, Paused: true},
+		{Address: "0x0000000000000000000000000000000000000004", ExpiresAt: &future},
+	})
+
+	var statuses []string
+	for _, subscription := range parser.GetSubscriptions() {
+		statuses = append(statuses, subscription.Status)
+	}
+	assert.Equal(t, []string{
+		domain.SubscriptionStatusActive,
+		domain.SubscriptionStatusPaused,
+		domain.SubscriptionStatusExpired,
+		domain.SubscriptionStatusActive,
+	}, statuses)
+}
+
+func TestFetchTransactionsForAddress_InactiveSubscription(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000123"
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		subscription repository.Subscription
+		ok           bool
+	}{
+		{name: "Unsubscribed", ok: false},
+		{name: "Paused", subscription: repository.Subscription{Address: address, Paused: true}, ok: true},
+		{name: "Expired", subscription: repository.Subscription{Address: address, ExpiresAt: &past}, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// 不會發出任何 RPC 請求
+			parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+			mockClient.EXPECT().CallEthereum(gomock.Any(), gomock.Any()).Times(0)
+			mockStorage.EXPECT().GetSubscription(address).Return(tt.subscription, tt.ok)
+
+			parser.FetchTransactionsForAddress(address)
+		})
+	}
+}
+
+func TestRemoveExpiredSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, _ := newSubscriptionTestParser(ctrl)
+	past := time.Now().Add(-time.Minute)
+
+	mockStorage.EXPECT().GetSubscriptions().Return([]repository.Subscription{
+		{Address: "0x0000000000000000000000000000000000000001"},
+		{Address: "0x0000000000000000000000000000000000000002", ExpiresAt: &past},
+	})
+	mockStorage.EXPECT().UnsubscribeAddress("0x0000000000000000000000000000000000000002").Return(true)
+
+	parser.removeExpiredSubscriptions()
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// tokenMetadataRetryInterval 查不到任何代幣資訊的合約再次查詢的間隔，合約可能尚未部署或為尚未升級的 proxy
+const tokenMetadataRetryInterval = time.Hour
+
+var (
+	// tokenNameSelector name()
+	tokenNameSelector = []byte{0x06, 0xfd, 0xde, 0x03}
+	// tokenSymbolSelector symbol()
+	tokenSymbolSelector = []byte{0x95, 0xd8, 0x9b, 0x41}
+	// tokenDecimalsSelector decimals()
+	tokenDecimalsSelector = []byte{0x31, 0x3c, 0xe5, 0x67}
+)
+
+// GetTokenMetadata 取得代幣的名稱、代號與小數位數，本地設定的欄位優先於鏈上查詢的結果
+// 本地設定包含所有欄位時不查詢節點；查詢結果保存於 storage，之後不再查詢
+func (p *EthereumParser) GetTokenMetadata(contract string) (usecase.TokenMetadata, error) {
+	contract, err := domain.NormalizeAddress(contract)
+	if err != nil {
+		return usecase.TokenMetadata{}, err
+	}
+
+	override, hasOverride := p.tokenOverrides[contract]
+	if hasOverride && override.Name != "" && override.Symbol != "" && override.Decimals != nil {
+		return override, nil
+	}
+
+	cached, err := p.cachedTokenMetadata(contract)
+	if err != nil {
+		if hasOverride {
+			p.reportError("Error fetching token metadata:", err)
+			return override, nil
+		}
+		return usecase.TokenMetadata{}, err
+	}
+
+	metadata := toUsecaseTokenMetadata(cached)
+	if hasOverride {
+		metadata = applyTokenOverride(metadata, override)
+	}
+	if metadata.Name == "" && metadata.Symbol == "" && metadata.Decimals == nil {
+		return metadata, fmt.Errorf("%w: %s", domain.ErrNotTokenContract, contract)
+	}
+	return metadata, nil
+}
+
+// cachedTokenMetadata 優先使用 storage 的快取，沒有快取或先前查不到任何資訊且已超過重試間隔時查詢節點並保存
+func (p *EthereumParser) cachedTokenMetadata(contract string) (repository.TokenMetadata, error) {
+	cached, ok := p.storage.GetTokenMetadata(contract)
+	empty := cached.Name == "" && cached.Symbol == "" && cached.Decimals == nil
+	if ok && (!empty || time.Since(cached.FetchedAt) < tokenMetadataRetryInterval) {
+		return cached, nil
+	}
+
+	metadata, err := p.fetchTokenMetadata(contract)
+	if err != nil {
+		return repository.TokenMetadata{}, err
+	}
+	p.storage.SaveTokenMetadata(metadata)
+	return metadata, nil
+}
+
+// fetchTokenMetadata 以 eth_call 呼叫 name()、symbol() 與 decimals()
+// 合約 revert 或返回無法解碼的結果時該欄位為空值，限流、無法連線等其他錯誤時返回錯誤，以免快取不完整的結果
+func (p *EthereumParser) fetchTokenMetadata(contract string) (repository.TokenMetadata, error) {
+	metadata := repository.TokenMetadata{Contract: contract, FetchedAt: time.Now()}
+
+	name, err := p.tokenCall(contract, tokenNameSelector)
+	if err != nil {
+		return repository.TokenMetadata{}, err
+	}
+	metadata.Name = decodeTokenString(name)
+
+	symbol, err := p.tokenCall(contract, tokenSymbolSelector)
+	if err != nil {
+		return repository.TokenMetadata{}, err
+	}
+	metadata.Symbol = decodeTokenString(symbol)
+
+	decimals, err := p.tokenCall(contract, tokenDecimalsSelector)
+	if err != nil {
+		return repository.TokenMetadata{}, err
+	}
+	metadata.Decimals = decodeTokenDecimals(decimals)
+
+	return metadata, nil
+}
+
+// tokenCall 呼叫代幣的唯讀函式，合約執行失敗（例如 execution reverted）視為未實作，返回空結果
+// 限流、逾時等其他錯誤照常返回，以免將暫時的錯誤快取為合約未實作
+func (p *EthereumParser) tokenCall(contract string, selector []byte) ([]byte, error) {
+	result, err := p.ethCall(contract, bytes.Clone(selector))
+	var rpcErr *repository.RPCError
+	if errors.As(err, &rpcErr) && isExecutionReverted(rpcErr) {
+		return nil, nil
+	}
+	return result, err
+}
+
+// isExecutionReverted 判斷 RPC 錯誤是否為合約執行失敗，geth 以 3 表示帶有 revert 資料的錯誤，其餘節點只在訊息中說明
+func isExecutionReverted(err *repository.RPCError) bool {
+	if err.Code == 3 {
+		return true
+	}
+	message := strings.ToLower(err.Message)
+	return strings.Contains(message, "revert") || strings.Contains(message, "invalid opcode")
+}
+
+// decodeTokenString 解碼 ABI string，舊的代幣（例如 MKR）以 bytes32 返回，去除結尾的 0 後使用
+// 無法解碼或不是 UTF-8 時返回空字串
+func decodeTokenString(data []byte) string {
+	var value []byte
+	switch {
+	case len(data) == 32:
+		value = bytes.TrimRight(data, "\x00")
+	case len(data) >= 64:
+		offset, ok := readWord(data, 0)
+		if !ok || offset > uint64(len(data)-32) {
+			return ""
+		}
+		length, ok := readWord(data, int(offset))
+		start := int(offset) + 32
+		if !ok || length > uint64(len(data)-start) {
+			return ""
+		}
+		value = data[start : start+int(length)]
+	}
+
+	if !utf8.Valid(value) {
+		return ""
+	}
+	return strings.TrimSpace(string(value))
+}
+
+// decodeTokenDecimals 解碼 decimals() 的 uint8，超過上限或無法解碼時返回 nil
+func decodeTokenDecimals(data []byte) *int {
+	value, ok := readWord(data, 0)
+	if !ok || value > domain.MaxTokenDecimals {
+		return nil
+	}
+	decimals := int(value)
+	return &decimals
+}
+
+// readWord 讀取 offset 開始的 32 bytes 為無號整數，超出 uint64 或資料不足時返回 false
+func readWord(data []byte, offset int) (uint64, bool) {
+	if offset < 0 || len(data) < offset+32 {
+		return 0, false
+	}
+	word := new(big.Int).SetBytes(data[offset : offset+32])
+	if !word.IsUint64() {
+		return 0, false
+	}
+	return word.Uint64(), true
+}
+
+// applyTokenOverride 以本地設定中有值的欄位覆蓋鏈上查詢的結果
+func applyTokenOverride(metadata, override usecase.TokenMetadata) usecase.TokenMetadata {
+	if override.Name != "" {
+		metadata.Name = override.Name
+	}
+	if override.Symbol != "" {
+		metadata.Symbol = override.Symbol
+	}
+	if override.Decimals != nil {
+		metadata.Decimals = override.Decimals
+	}
+	metadata.Source = domain.TokenMetadataSourceOverride
+	return metadata
+}
+
+func toUsecaseTokenMetadata(metadata repository.TokenMetadata) usecase.TokenMetadata {
+	return usecase.TokenMetadata{
+		Contract: metadata.Contract,
+		Name:     metadata.Name,
+		Symbol:   metadata.Symbol,
+		Decimals: metadata.Decimals,
+		Source:   domain.TokenMetadataSourceChain,
+	}
+}
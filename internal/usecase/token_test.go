@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/abi"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"strings"
+	"testing"
+	"time"
+
+	repoMock "parse_server/internal/mock/repository"
+)
+
+const tokenTestContract = "0x000000000000000000000000000000000000ee20"
+
+// abiString 以 ABI string 編碼 value
+func abiString(value string) []byte {
+	data := make([]byte, 64, 96)
+	data[31] = 0x20
+	data[63] = byte(len(value))
+	padded := make([]byte, (len(value)+31)/32*32)
+	copy(padded, value)
+	return append(data, padded...)
+}
+
+// abiBytes32 以 bytes32 編碼 value，舊的代幣以此格式返回名稱與代號
+func abiBytes32(value string) []byte {
+	data := make([]byte, 32)
+	copy(data, value)
+	return data
+}
+
+func abiUint(value byte) []byte {
+	data := make([]byte, 32)
+	data[31] = value
+	return data
+}
+
+// expectTokenCall 模擬代幣唯讀函式的 eth_call，result 為 nil 時模擬合約 revert
+func expectTokenCall(mockClient *repoMock.MockETHClient, selector []byte, result []byte) {
+	response := json.RawMessage(`{"error": {"code": 3, "message": "execution reverted"}}`)
+	if result != nil {
+		response = json.RawMessage(fmt.Sprintf(`{"result": "0x%s"}`, hex.EncodeToString(result)))
+	}
+	mockClient.EXPECT().CallEthereum("eth_call", []any{repository.CallMsg{To: tokenTestContract, Data: selector}, "latest"}).Return(response, nil)
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestTokenSelectors(t *testing.T) {
+	name := abi.Entry{Name: "name"}.Selector()
+	symbol := abi.Entry{Name: "symbol"}.Selector()
+	decimals := abi.Entry{Name: "decimals"}.Selector()
+	assert.Equal(t, tokenNameSelector, name[:])
+	assert.Equal(t, tokenSymbolSelector, symbol[:])
+	assert.Equal(t, tokenDecimalsSelector, decimals[:])
+}
+
+func TestDecodeTokenString(t *testing.T) {
+	assert.Equal(t, "USD Coin", decodeTokenString(abiString("USD Coin")))
+	assert.Equal(t, "", decodeTokenString(abiString("")))
+	assert.Equal(t, strings.Repeat("a", 40), decodeTokenString(abiString(strings.Repeat("a", 40))))
+	assert.Equal(t, "MKR", decodeTokenString(abiBytes32("MKR")))
+	assert.Equal(t, "Maker", decodeTokenString(abiBytes32("Maker")))
+
+	// 長度或 offset 超出資料範圍、不是 UTF-8 或沒有資料時返回空字串
+	truncated := abiString("USD Coin")
+	truncated[63] = 0x40
+	assert.Equal(t, "", decodeTokenString(truncated))
+	badOffset := abiString("USD Coin")
+	badOffset[31] = 0xff
+	assert.Equal(t, "", decodeTokenString(badOffset))
+	assert.Equal(t, "", decodeTokenString(abiBytes32("\xff\xfe")))
+	assert.Equal(t, "", decodeTokenString(nil))
+	assert.Equal(t, "", decodeTokenString(make([]byte, 16)))
+}
+
+func TestDecodeTokenDecimals(t *testing.T) {
+	assert.Equal(t, intPtr(6), decodeTokenDecimals(abiUint(6)))
+	assert.Equal(t, intPtr(0), decodeTokenDecimals(abiUint(0)))
+	assert.Nil(t, decodeTokenDecimals(abiUint(78)))
+	assert.Nil(t, decodeTokenDecimals(nil))
+}
+
+func TestGetTokenMetadata_FetchesAndCaches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+
+	_, err := parser.GetTokenMetadata("0x123")
+	assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+
+	// 沒有快取時查詢節點並保存，bytes32 的代號也能解碼
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(repository.TokenMetadata{}, false)
+	expectTokenCall(mockClient, tokenNameSelector, abiString("Maker"))
+	expectTokenCall(mockClient, tokenSymbolSelector, abiBytes32("MKR"))
+	expectTokenCall(mockClient, tokenDecimalsSelector, abiUint(18))
+	var saved repository.TokenMetadata
+	mockStorage.EXPECT().SaveTokenMetadata(gomock.Any()).Do(func(metadata repository.TokenMetadata) {
+		saved = metadata
+	})
+
+	metadata, err := parser.GetTokenMetadata(strings.ToUpper(tokenTestContract[:2]) + tokenTestContract[2:])
+	assert.NoError(t, err)
+	expected := usecase.TokenMetadata{
+		Contract: tokenTestContract,
+		Name:     "Maker",
+		Symbol:   "MKR",
+		Decimals: intPtr(18),
+		Source:   domain.TokenMetadataSourceChain,
+	}
+	assert.Equal(t, expected, metadata)
+	assert.Equal(t, tokenTestContract, saved.Contract)
+	assert.False(t, saved.FetchedAt.IsZero())
+
+	// 之後使用快取，不再查詢節點
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(saved, true)
+	metadata, err = parser.GetTokenMetadata(tokenTestContract)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, metadata)
+}
+
+func TestGetTokenMetadata_PartialAndMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+
+	// 未實作 name() 的合約仍返回其餘欄位
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(repository.TokenMetadata{}, false)
+	expectTokenCall(mockClient, tokenNameSelector, nil)
+	expectTokenCall(mockClient, tokenSymbolSelector, abiString("ABC"))
+	expectTokenCall(mockClient, tokenDecimalsSelector, abiUint(8))
+	mockStorage.EXPECT().SaveTokenMetadata(gomock.Any())
+	metadata, err := parser.GetTokenMetadata(tokenTestContract)
+	assert.NoError(t, err)
+	assert.Equal(t, "", metadata.Name)
+	assert.Equal(t, "ABC", metadata.Symbol)
+	assert.Equal(t, intPtr(8), metadata.Decimals)
+
+	// 不是代幣的合約，查詢結果在重試間隔內使用快取
+	empty := repository.TokenMetadata{Contract: tokenTestContract, FetchedAt: time.Now()}
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(empty, true)
+	_, err = parser.GetTokenMetadata(tokenTestContract)
+	assert.ErrorIs(t, err, domain.ErrNotTokenContract)
+
+	// 超過重試間隔後再次查詢
+	empty.FetchedAt = time.Now().Add(-2 * tokenMetadataRetryInterval)
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(empty, true)
+	expectTokenCall(mockClient, tokenNameSelector, nil)
+	expectTokenCall(mockClient, tokenSymbolSelector, nil)
+	expectTokenCall(mockClient, tokenDecimalsSelector, nil)
+	mockStorage.EXPECT().SaveTokenMetadata(gomock.Any())
+	_, err = parser.GetTokenMetadata(tokenTestContract)
+	assert.ErrorIs(t, err, domain.ErrNotTokenContract)
+}
+
+func TestGetTokenMetadata_NodeError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	parser, mockStorage, mockClient := newSubscriptionTestParser(ctrl)
+
+	// 節點錯誤時不保存不完整的結果
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(repository.TokenMetadata{}, false)
+	expectTokenCall(mockClient, tokenNameSelector, abiString("Maker"))
+	mockClient.EXPECT().CallEthereum("eth_call", gomock.Any()).Return(nil, errors.New("timeout"))
+	_, err := parser.GetTokenMetadata(tokenTestContract)
+	assert.EqualError(t, err, "timeout")
+
+	// 限流等不是合約執行失敗的 RPC 錯誤也不視為未實作
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(repository.TokenMetadata{}, false)
+	mockClient.EXPECT().CallEthereum("eth_call", gomock.Any()).Return(json.RawMessage(`{"error": {"code": -32005, "message": "project ID request rate exceeded"}}`), nil)
+	_, err = parser.GetTokenMetadata(tokenTestContract)
+	assert.EqualError(t, err, "rpc error -32005: project ID request rate exceeded")
+}
+
+func TestIsExecutionReverted(t *testing.T) {
+	assert.True(t, isExecutionReverted(&repository.RPCError{Code: 3, Message: "execution reverted"}))
+	assert.True(t, isExecutionReverted(&repository.RPCError{Code: -32000, Message: "execution reverted"}))
+	assert.True(t, isExecutionReverted(&repository.RPCError{Code: -32015, Message: "VM execution error: Reverted 0x"}))
+	assert.True(t, isExecutionReverted(&repository.RPCError{Code: -32000, Message: "invalid opcode: INVALID"}))
+	assert.False(t, isExecutionReverted(&repository.RPCError{Code: 429, Message: "Too Many Requests"}))
+	assert.False(t, isExecutionReverted(&repository.RPCError{Code: -32000, Message: "request timed out"}))
+	assert.False(t, isExecutionReverted(&repository.RPCError{Code: -32000, Message: "header not found"}))
+}
+
+func TestGetTokenMetadata_Overrides(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	complete := "0x0000000000000000000000000000000000000c01"
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:   mockStorage,
+		EthClient: mockClient,
+		TokenOverrides: []usecase.TokenMetadata{
+			{Contract: complete, Name: "Local Token", Symbol: "LOC", Decimals: intPtr(2)},
+			{Contract: tokenTestContract, Symbol: "USDC.e"},
+		},
+	})
+
+	// 設定所有欄位時不查詢節點也不使用快取
+	metadata, err := parser.GetTokenMetadata(complete)
+	assert.NoError(t, err)
+	assert.Equal(t, usecase.TokenMetadata{
+		Contract: complete,
+		Name:     "Local Token",
+		Symbol:   "LOC",
+		Decimals: intPtr(2),
+		Source:   domain.TokenMetadataSourceOverride,
+	}, metadata)
+
+	// 只設定部分欄位時與鏈上的結果合併
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(repository.TokenMetadata{
+		Contract:  tokenTestContract,
+		Name:      "USD Coin",
+		Symbol:    "USDC",
+		Decimals:  intPtr(6),
+		FetchedAt: time.Now(),
+	}, true)
+	metadata, err = parser.GetTokenMetadata(tokenTestContract)
+	assert.NoError(t, err)
+	assert.Equal(t, usecase.TokenMetadata{
+		Contract: tokenTestContract,
+		Name:     "USD Coin",
+		Symbol:   "USDC.e",
+		Decimals: intPtr(6),
+		Source:   domain.TokenMetadataSourceOverride,
+	}, metadata)
+
+	// 節點錯誤時仍返回本地設定
+	mockStorage.EXPECT().GetTokenMetadata(tokenTestContract).Return(repository.TokenMetadata{}, false)
+	mockClient.EXPECT().CallEthereum("eth_call", gomock.Any()).Return(nil, errors.New("timeout"))
+	metadata, err = parser.GetTokenMetadata(tokenTestContract)
+	assert.NoError(t, err)
+	assert.Equal(t, "USDC.e", metadata.Symbol)
+	assert.Nil(t, metadata.Decimals)
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"strings"
+)
+
+// 追蹤內部交易使用的 RPC 方法，依序降級
+const (
+	tracerDisabled   = ""
+	tracerCallTracer = "debug_traceBlockByNumber"
+	tracerParity     = "trace_block"
+)
+
+// fetchInternalTransactions 根據區塊號追蹤合約內部的 ETH 轉移
+// 節點不支援 callTracer 時改用 trace_block，兩者皆不支援則停用追蹤並返回空結果
+func (p *EthereumParser) fetchInternalTransactions(blockNumber string) ([]repository.InternalTransaction, error) {
+	for tracer := p.currentTracer(); tracer != tracerDisabled; tracer = p.currentTracer() {
+		var (
+			reply []repository.InternalTransaction
+			err   error
+		)
+		switch tracer {
+		case tracerCallTracer:
+			reply, err = p.traceBlockByNumber(blockNumber)
+		case tracerParity:
+			reply, err = p.traceBlock(blockNumber)
+		}
+
+		var rpcErr *repository.RPCError
+		if !errors.As(err, &rpcErr) || !isMethodUnsupported(rpcErr, tracer) {
+			numberInternalTransactions(reply)
+			return reply, err
+		}
+
+		fmt.Printf("Tracing method %s is not supported by provider: %s\n", tracer, rpcErr.Message)
+		p.downgradeTracer(tracer)
+	}
+
+	return nil, nil
+}
+
+// currentTracer 取得目前使用的追蹤方法，重新處理區塊時會有多個 goroutine 同時追蹤
+func (p *EthereumParser) currentTracer() string {
+	p.tracerMu.Lock()
+	defer p.tracerMu.Unlock()
+	return p.tracer
+}
+
+// downgradeTracer 將不支援的追蹤方法降級，其他 goroutine 已經降級時不重複處理
+func (p *EthereumParser) downgradeTracer(tracer string) {
+	p.tracerMu.Lock()
+	defer p.tracerMu.Unlock()
+	if p.tracer != tracer {
+		return
+	}
+	if tracer == tracerCallTracer {
+		p.tracer = tracerParity
+	} else {
+		p.tracer = tracerDisabled
+		fmt.Println("Internal transaction tracing disabled")
+	}
+}
+
+// numberInternalTransactions 依呼叫順序為同一筆外層交易的轉移編號，作為保存時的唯一鍵
+func numberInternalTransactions(txs []repository.InternalTransaction) {
+	counts := make(map[string]int)
+	for i := range txs {
+		txs[i].Index = counts[txs[i].ParentTxHash]
+		counts[txs[i].ParentTxHash]++
+	}
+}
+
+// traceBlockByNumber 使用 debug_traceBlockByNumber 的 callTracer 追蹤區塊
+func (p *EthereumParser) traceBlockByNumber(blockNumber string) ([]repository.InternalTransaction, error) {
+	result, err := p.ethClient.CallEthereum("debug_traceBlockByNumber", []any{blockNumber, map[string]string{"tracer": "callTracer"}})
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResponse repository.TraceBlockResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return nil, err
+	}
+	if rpcResponse.Error != nil {
+		return nil, rpcResponse.Error
+	}
+
+	reply := make([]repository.InternalTransaction, 0)
+	for _, item := range rpcResponse.Result {
+		reply = appendCallFrame(reply, item.TxHash.String(), blockNumber, item.Result, 0)
+	}
+
+	return reply, nil
+}
+
+// appendCallFrame 深度優先展開 callTracer 的呼叫樹，失敗的呼叫連同其子呼叫都不會被記錄
+func appendCallFrame(reply []repository.InternalTransaction, txHash, blockNumber string, frame repository.CallFrame, depth int) []repository.InternalTransaction {
+	if frame.Error != "" {
+		return reply
+	}
+
+	callType := strings.ToUpper(frame.Type)
+	if depth > 0 && transfersValue(callType) && frame.Value.Sign() > 0 {
+		reply = append(reply, repository.InternalTransaction{
+			ParentTxHash: txHash,
+			BlockNumber:  blockNumber,
+			Type:         callType,
+			From:         frame.From.String(),
+			To:           frame.To.String(),
+			Value:        frame.Value.BigInt,
+			Depth:        depth,
+		})
+	}
+	for _, call := range frame.Calls {
+		reply = appendCallFrame(reply, txHash, blockNumber, call, depth+1)
+	}
+
+	return reply
+}
+
+// traceBlock 使用 trace_block 追蹤區塊
+func (p *EthereumParser) traceBlock(blockNumber string) ([]repository.InternalTransaction, error) {
+	result, err := p.ethClient.CallEthereum("trace_block", []any{blockNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	var rpcResponse repository.ParityTraceResult
+	err = json.Unmarshal(result, &rpcResponse)
+	if err != nil {
+		return nil, err
+	}
+	if rpcResponse.Error != nil {
+		return nil, rpcResponse.Error
+	}
+
+	// 記錄失敗的呼叫路徑，其子呼叫的轉移同樣會被回滾
+	failed := make(map[string]bool)
+	reply := make([]repository.InternalTransaction, 0)
+	for _, item := range rpcResponse.Result {
+		if item.TransactionHash == nil || len(item.TraceAddress) == 0 {
+			continue
+		}
+		txHash := item.TransactionHash.String()
+		if hasFailedAncestor(failed, txHash, item.TraceAddress) {
+			continue
+		}
+		if item.Error != "" {
+			failed[traceKey(txHash, item.TraceAddress)] = true
+			continue
+		}
+
+		tx := repository.InternalTransaction{
+			ParentTxHash: txHash,
+			BlockNumber:  blockNumber,
+			Depth:        len(item.TraceAddress),
+		}
+		switch item.Type {
+		case "call":
+			tx.Type = strings.ToUpper(item.Action.CallType)
+			tx.From = item.Action.From.String()
+			tx.To = item.Action.To.String()
+			tx.Value = item.Action.Value.BigInt
+		case "create":
+			tx.Type = "CREATE"
+			tx.From = item.Action.From.String()
+			tx.To = item.Result.Address.String()
+			tx.Value = item.Action.Value.BigInt
+		case "suicide":
+			tx.Type = "SELFDESTRUCT"
+			tx.From = item.Action.Address.String()
+			tx.To = item.Action.RefundAddress.String()
+			tx.Value = item.Action.Balance.BigInt
+		default:
+			continue
+		}
+		if !transfersValue(tx.Type) || tx.Value.Sign() <= 0 {
+			continue
+		}
+		reply = append(reply, tx)
+	}
+
+	return reply, nil
+}
+
+func traceKey(txHash string, traceAddress []int) string {
+	return fmt.Sprint(txHash, traceAddress)
+}
+
+func hasFailedAncestor(failed map[string]bool, txHash string, traceAddress []int) bool {
+	for i := 1; i < len(traceAddress); i++ {
+		if failed[traceKey(txHash, traceAddress[:i])] {
+			return true
+		}
+	}
+	return false
+}
+
+// transfersValue DELEGATECALL 與 STATICCALL 不會實際轉移 ETH
+func transfersValue(callType string) bool {
+	switch callType {
+	case "CALL", "CALLCODE", "CREATE", "CREATE2", "SELFDESTRUCT":
+		return true
+	}
+	return false
+}
+
+// isMethodUnsupported 判斷 RPC 錯誤是否代表節點不支援 method，判斷錯誤會永久停用該方法
+// 優先以 JSON-RPC 的 -32601（method not found）判斷，不論訊息的內容；
+// 其他錯誤碼只在訊息提到方法本身並說明不支援時才算，"header not found" 等暫時性的錯誤不算
+func isMethodUnsupported(err *repository.RPCError, method string) bool {
+	if err.Code == -32601 {
+		return true
+	}
+	message := strings.ToLower(err.Message)
+	if !strings.Contains(message, "method") && !strings.Contains(message, strings.ToLower(method)) {
+		return false
+	}
+	for _, keyword := range []string{"not supported", "unsupported", "not available", "does not exist", "not found", "not allowed"} {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// toUsecaseInternalTransaction 將 repository 的內部交易轉為 usecase 層的結構
+func toUsecaseInternalTransaction(item repository.InternalTransaction) usecase.InternalTransaction {
+	return usecase.InternalTransaction{
+		ParentTxHash: item.ParentTxHash,
+		Index:        item.Index,
+		BlockNumber:  item.BlockNumber,
+		Type:         item.Type,
+		From:         item.From,
+		To:           item.To,
+		Value:        item.Value,
+		Depth:        item.Depth,
+	}
+}
+
+// toRepositoryInternalTransaction 將 pipeline 處理後的內部交易轉回 repository 的結構
+func toRepositoryInternalTransaction(item usecase.InternalTransaction) repository.InternalTransaction {
+	return repository.InternalTransaction{
+		ParentTxHash: item.ParentTxHash,
+		Index:        item.Index,
+		BlockNumber:  item.BlockNumber,
+		Type:         item.Type,
+		From:         item.From,
+		To:           item.To,
+		Value:        item.Value,
+		Depth:        item.Depth,
+	}
+}
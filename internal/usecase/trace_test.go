@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func TestFetchInternalTransactions_CallTracer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:       repoMock.NewMockStorage(ctrl),
+		Notification:  ucMock.NewMockNotification(ctrl),
+		EthClient:     mockClient,
+		EnableTracing: true,
+	})
+
+	mockClient.EXPECT().CallEthereum("debug_traceBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"result": [
+			{
+				"txHash": "0x1111111111111111111111111111111111111111111111111111111111111111",
+				"result": {
+					"type": "CALL", "from": "0x0000000000000000000000000000000000000e0a", "to": "0x0000000000000000000000000000000000005a5e", "value": "0x0",
+					"calls": [
+						{"type": "CALL", "from": "0x0000000000000000000000000000000000005a5e", "to": "0x00000000000000000000000000000000000000e1", "value": "0x10"},
+						{"type": "DELEGATECALL", "from": "0x0000000000000000000000000000000000005a5e", "to": "0x000000000000000000000000000000000000011b", "value": "0x10"},
+						{"type": "CALL", "from": "0x0000000000000000000000000000000000005a5e", "to": "0x000000000000000000000000000000000000fa11", "value": "0x1", "error": "execution reverted",
+							"calls": [{"type": "CALL", "from": "0x000000000000000000000000000000000000fa11", "to": "0x0000000000000000000000000000000000000e57", "value": "0x1"}]},
+						{"type": "CALL", "from": "0x0000000000000000000000000000000000005a5e", "to": "0x00000000000000000000000000000000000009e0", "value": "0x0",
+							"calls": [{"type": "CALL", "from": "0x00000000000000000000000000000000000009e0", "to": "0x000000000000000000000000000000000000dee9", "value": "0x5"}]}
+					]
+				}
+			}
+		]
+	}`), nil)
+
+	result, err := parser.(*EthereumParser).fetchInternalTransactions("0x10d4f")
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.InternalTransaction{
+		{ParentTxHash: "0x1111111111111111111111111111111111111111111111111111111111111111", BlockNumber: "0x10d4f", Type: "CALL", From: "0x0000000000000000000000000000000000005a5e", To: "0x00000000000000000000000000000000000000e1", Value: domain.BigIntFromUint64(0x10), Depth: 1},
+		{ParentTxHash: "0x1111111111111111111111111111111111111111111111111111111111111111", BlockNumber: "0x10d4f", Type: "CALL", From: "0x00000000000000000000000000000000000009e0", To: "0x000000000000000000000000000000000000dee9", Value: domain.BigIntFromUint64(0x5), Depth: 2, Index: 1},
+	}, result)
+}
+
+func TestFetchInternalTransactions_FallbackToTraceBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:       repoMock.NewMockStorage(ctrl),
+		Notification:  ucMock.NewMockNotification(ctrl),
+		EthClient:     mockClient,
+		EnableTracing: true,
+	})
+
+	mockClient.EXPECT().CallEthereum("debug_traceBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"error": {"code": -32601, "message": "endpoint disabled for this plan"}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("trace_block", gomock.Any()).Return(json.RawMessage(`{
+		"result": [
+			{"type": "call", "action": {"callType": "call", "from": "0x0000000000000000000000000000000000000e0a", "to": "0x0000000000000000000000000000000000005a5e", "value": "0x0"}, "traceAddress": [], "transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111"},
+			{"type": "call", "action": {"callType": "call", "from": "0x0000000000000000000000000000000000005a5e", "to": "0x00000000000000000000000000000000000000e1", "value": "0x10"}, "traceAddress": [0], "transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111"},
+			{"type": "call", "action": {"callType": "call", "from": "0x0000000000000000000000000000000000005a5e", "to": "0x000000000000000000000000000000000000fa11", "value": "0x1"}, "traceAddress": [1], "transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111", "error": "Reverted"},
+			{"type": "call", "action": {"callType": "call", "from": "0x000000000000000000000000000000000000fa11", "to": "0x0000000000000000000000000000000000000e57", "value": "0x1"}, "traceAddress": [1, 0], "transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111"},
+			{"type": "suicide", "action": {"address": "0x000000000000000000000000000000000000dead", "refundAddress": "0x000000000000000000000000000000000000be1e", "balance": "0x7"}, "traceAddress": [2], "transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111"},
+			{"type": "create", "action": {"from": "0x0000000000000000000000000000000000005a5e", "value": "0x3", "init": "0x60"}, "result": {"address": "0x000000000000000000000000000000000000c0de", "code": "0x", "gasUsed": "0x0"}, "traceAddress": [3], "transactionHash": "0x1111111111111111111111111111111111111111111111111111111111111111"},
+			{"type": "reward", "action": {"author": "0x000000000000000000000000000000000000a1e5", "value": "0x1"}, "traceAddress": [], "transactionHash": null}
+		]
+	}`), nil)
+
+	result, err := parser.(*EthereumParser).fetchInternalTransactions("0x10d4f")
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.InternalTransaction{
+		{ParentTxHash: "0x1111111111111111111111111111111111111111111111111111111111111111", BlockNumber: "0x10d4f", Type: "CALL", From: "0x0000000000000000000000000000000000005a5e", To: "0x00000000000000000000000000000000000000e1", Value: domain.BigIntFromUint64(0x10), Depth: 1},
+		{ParentTxHash: "0x1111111111111111111111111111111111111111111111111111111111111111", BlockNumber: "0x10d4f", Type: "SELFDESTRUCT", From: "0x000000000000000000000000000000000000dead", To: "0x000000000000000000000000000000000000be1e", Value: domain.BigIntFromUint64(0x7), Depth: 1, Index: 1},
+		{ParentTxHash: "0x1111111111111111111111111111111111111111111111111111111111111111", BlockNumber: "0x10d4f", Type: "CREATE", From: "0x0000000000000000000000000000000000005a5e", To: "0x000000000000000000000000000000000000c0de", Value: domain.BigIntFromUint64(0x3), Depth: 1, Index: 2},
+	}, result)
+	assert.Equal(t, tracerParity, parser.(*EthereumParser).tracer)
+}
+
+func TestFetchInternalTransactions_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:       repoMock.NewMockStorage(ctrl),
+		Notification:  ucMock.NewMockNotification(ctrl),
+		EthClient:     mockClient,
+		EnableTracing: true,
+	})
+
+	// 兩種追蹤方法都不支援時只嘗試一次，之後不再呼叫節點
+	mockClient.EXPECT().CallEthereum("debug_traceBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"error": {"code": -32601, "message": "Method not found"}
+	}`), nil).Times(1)
+	mockClient.EXPECT().CallEthereum("trace_block", gomock.Any()).Return(json.RawMessage(`{
+		"error": {"code": -32000, "message": "method trace_block not supported"}
+	}`), nil).Times(1)
+
+	for i := 0; i < 2; i++ {
+		result, err := parser.(*EthereumParser).fetchInternalTransactions("0x10d4f")
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	}
+	assert.Equal(t, tracerDisabled, parser.(*EthereumParser).tracer)
+}
+
+func TestFetchInternalTransactions_OtherRPCError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:       repoMock.NewMockStorage(ctrl),
+		Notification:  ucMock.NewMockNotification(ctrl),
+		EthClient:     mockClient,
+		EnableTracing: true,
+	})
+
+	// 非「不支援」的錯誤不應降級
+	mockClient.EXPECT().CallEthereum("debug_traceBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"error": {"code": -32005, "message": "rate limit exceeded"}
+	}`), nil)
+
+	_, err := parser.(*EthereumParser).fetchInternalTransactions("0x10d4f")
+	assert.Error(t, err)
+	assert.Equal(t, tracerCallTracer, parser.(*EthereumParser).tracer)
+}
+
+func TestIsMethodUnsupported(t *testing.T) {
+	tests := []struct {
+		err      repository.RPCError
+		expected bool
+	}{
+		{err: repository.RPCError{Code: -32601, Message: "Method not found"}, expected: true},
+		{err: repository.RPCError{Code: -32601, Message: "the requested feature is not enabled for this endpoint"}, expected: true},
+		{err: repository.RPCError{Code: -32601}, expected: true},
+		{err: repository.RPCError{Code: -32000, Message: "the method trace_block does not exist/is not available"}, expected: true},
+		{err: repository.RPCError{Code: -32600, Message: "trace_block is not available on the Free tier"}, expected: true},
+		{err: repository.RPCError{Code: -32000, Message: "header not found"}},
+		{err: repository.RPCError{Code: -32000, Message: "block #123 not found"}},
+		{err: repository.RPCError{Code: -32005, Message: "request rate exceeded, not allowed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Message, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isMethodUnsupported(&tt.err, "trace_block"))
+		})
+	}
+}
+
+func TestFetchInternalTransactions_BlockNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:       repoMock.NewMockStorage(ctrl),
+		Notification:  ucMock.NewMockNotification(ctrl),
+		EthClient:     mockClient,
+		EnableTracing: true,
+	}).(*EthereumParser)
+
+	// 節點尚未取得區塊時返回錯誤，不降級追蹤方法
+	mockClient.EXPECT().CallEthereum("debug_traceBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"error": {"code": -32000, "message": "header not found"}
+	}`), nil)
+
+	_, err := parser.fetchInternalTransactions("0x10d4f")
+	assert.Error(t, err)
+	assert.Equal(t, tracerCallTracer, parser.tracer)
+}
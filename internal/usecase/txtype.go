@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+)
+
+// applyTransactionType 補上各交易類型特有的欄位
+func applyTransactionType(tx *repository.Transaction, item repository.TransactionItem) {
+	tx.Type = domain.TxType(item.Type)
+	for _, tuple := range item.AccessList {
+		entry := repository.AccessListEntry{Address: tuple.Address.String(), StorageKeys: make([]string, 0, len(tuple.StorageKeys))}
+		for _, key := range tuple.StorageKeys {
+			entry.StorageKeys = append(entry.StorageKeys, key.String())
+		}
+		tx.AccessList = append(tx.AccessList, entry)
+	}
+	if item.YParity != nil {
+		tx.YParity = item.YParity.String()
+	}
+	if item.MaxFeePerGas != nil {
+		tx.MaxFeePerGas = item.MaxFeePerGas.BigInt
+	}
+	if item.MaxPriorityFeePerGas != nil {
+		tx.MaxPriorityFeePerGas = item.MaxPriorityFeePerGas.BigInt
+	}
+
+	if item.MaxFeePerBlobGas != nil {
+		tx.MaxFeePerBlobGas = item.MaxFeePerBlobGas.BigInt
+	}
+	for _, hash := range item.BlobVersionedHashes {
+		tx.BlobVersionedHashes = append(tx.BlobVersionedHashes, hash.String())
+	}
+
+	for _, authorization := range item.AuthorizationList {
+		tx.AuthorizationList = append(tx.AuthorizationList, repository.Authorization{
+			ChainID: authorization.ChainId.String(),
+			Address: authorization.Address.String(),
+			Nonce:   authorization.Nonce.String(),
+			YParity: authorization.YParity.String(),
+			R:       authorization.R.String(),
+			S:       authorization.S.String(),
+		})
+	}
+
+	if item.SourceHash != nil {
+		tx.SourceHash = item.SourceHash.String()
+	}
+	if item.Mint != nil {
+		tx.Mint = item.Mint.BigInt
+	}
+}
+
+// transactionFee 依交易類型計算發送者實際支付的總手續費
+// deposit 交易由 L1 支付，不收取 L2 手續費；blob 交易另計 blob gas 費用；OP Stack 的 L2 交易另計 L1 資料費用
+func transactionFee(tx *repository.Transaction, receipt *repository.Receipt) domain.BigInt {
+	if tx.Type == domain.TxTypeDeposit {
+		return domain.BigInt{}
+	}
+
+	fee := tx.GasUsed.Mul(tx.GasPrice)
+	if receipt.BlobGasUsed != nil && receipt.BlobGasPrice != nil {
+		tx.BlobGasUsed = domain.BigIntFromUint64(uint64(*receipt.BlobGasUsed))
+		tx.BlobGasPrice = receipt.BlobGasPrice.BigInt
+		tx.BlobFee = tx.BlobGasUsed.Mul(tx.BlobGasPrice)
+		fee = fee.Add(tx.BlobFee)
+	}
+	if receipt.L1Fee != nil {
+		tx.L1Fee = receipt.L1Fee.BigInt
+		fee = fee.Add(tx.L1Fee)
+	}
+	return fee
+}
+
+func toUsecaseAuthorizations(authorizations []repository.Authorization) []usecase.Authorization {
+	if authorizations == nil {
+		return nil
+	}
+	result := make([]usecase.Authorization, 0, len(authorizations))
+	for _, item := range authorizations {
+		result = append(result, usecase.Authorization{
+			ChainID: item.ChainID,
+			Address: item.Address,
+			Nonce:   item.Nonce,
+			YParity: item.YParity,
+			R:       item.R,
+			S:       item.S,
+		})
+	}
+	return result
+}
+
+func toUsecaseAccessList(accessList []repository.AccessListEntry) []usecase.AccessListEntry {
+	if accessList == nil {
+		return nil
+	}
+	result := make([]usecase.AccessListEntry, 0, len(accessList))
+	for _, item := range accessList {
+		result = append(result, usecase.AccessListEntry{Address: item.Address, StorageKeys: item.StorageKeys})
+	}
+	return result
+}
+
+func toRepositoryAuthorizations(authorizations []usecase.Authorization) []repository.Authorization {
+	if authorizations == nil {
+		return nil
+	}
+	result := make([]repository.Authorization, 0, len(authorizations))
+	for _, item := range authorizations {
+		result = append(result, repository.Authorization{
+			ChainID: item.ChainID,
+			Address: item.Address,
+			Nonce:   item.Nonce,
+			YParity: item.YParity,
+			R:       item.R,
+			S:       item.S,
+		})
+	}
+	return result
+}
+
+func toRepositoryAccessList(accessList []usecase.AccessListEntry) []repository.AccessListEntry {
+	if accessList == nil {
+		return nil
+	}
+	result := make([]repository.AccessListEntry, 0, len(accessList))
+	for _, item := range accessList {
+		result = append(result, repository.AccessListEntry{Address: item.Address, StorageKeys: item.StorageKeys})
+	}
+	return result
+}
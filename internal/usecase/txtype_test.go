@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"os"
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"path/filepath"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+)
+
+// txTypeFixture 測試資料，包含節點返回的交易與收據
+type txTypeFixture struct {
+	Transaction json.RawMessage `json:"transaction"`
+	Receipt     json.RawMessage `json:"receipt"`
+}
+
+func loadTxTypeFixture(t *testing.T, name string) txTypeFixture {
+	data, err := os.ReadFile(filepath.Join("testdata", "txtypes", name))
+	assert.NoError(t, err)
+
+	var fixture txTypeFixture
+	assert.NoError(t, json.Unmarshal(data, &fixture))
+	return fixture
+}
+
+func TestTransactionTypes(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		txType   domain.TxType
+		gasPrice string
+		fee      string
+		check    func(t *testing.T, tx repository.Transaction)
+	}{
+		{
+			fixture:  "legacy.json",
+			txType:   domain.TxTypeLegacy,
+			gasPrice: "1000000000",
+			fee:      "21000000000000",
+			check: func(t *testing.T, tx repository.Transaction) {
+				assert.True(t, tx.MaxFeePerGas.IsZero())
+				assert.Equal(t, "1000000000000000000", tx.Value.String())
+				assert.Empty(t, tx.YParity)
+			},
+		},
+		{
+			fixture:  "access_list.json",
+			txType:   domain.TxTypeAccessList,
+			gasPrice: "2000000000",
+			fee:      "52000000000000",
+			check: func(t *testing.T, tx repository.Transaction) {
+				assert.Equal(t, []repository.AccessListEntry{{
+					Address:     "0x00000000000000000000000000000000000000bb",
+					StorageKeys: []string{"0x0000000000000000000000000000000000000000000000000000000000000001"},
+				}}, tx.AccessList)
+				assert.Equal(t, "0x0", tx.YParity)
+			},
+		},
+		{
+			fixture:  "dynamic_fee.json",
+			txType:   domain.TxTypeDynamicFee,
+			gasPrice: "1500000000",
+			fee:      "31500000000000",
+			check: func(t *testing.T, tx repository.Transaction) {
+				assert.Equal(t, "2000000000", tx.MaxFeePerGas.String())
+				assert.Equal(t, "1000000000", tx.MaxPriorityFeePerGas.String())
+				assert.True(t, tx.L1Fee.IsZero())
+			},
+		},
+		{
+			fixture:  "dynamic_fee_l1.json",
+			txType:   domain.TxTypeDynamicFee,
+			gasPrice: "1000000000",
+			fee:      "21010000000000",
+			check: func(t *testing.T, tx repository.Transaction) {
+				assert.Equal(t, "10000000000", tx.L1Fee.String())
+			},
+		},
+		{
+			fixture:  "blob.json",
+			txType:   domain.TxTypeBlob,
+			gasPrice: "1000000000",
+			fee:      "21000000524288",
+			check: func(t *testing.T, tx repository.Transaction) {
+				assert.Equal(t, "1000000000", tx.MaxFeePerBlobGas.String())
+				assert.Equal(t, []string{
+					"0x01a9150ab1d3dbdb2cde4b3b3c5b8e0b53b3f7b5b2fc1b7ad03d3b9f1e8c4d21",
+					"0x0100000000000000000000000000000000000000000000000000000000000002",
+				}, tx.BlobVersionedHashes)
+				assert.Equal(t, "262144", tx.BlobGasUsed.String())
+				assert.Equal(t, "2", tx.BlobGasPrice.String())
+				assert.Equal(t, "524288", tx.BlobFee.String())
+			},
+		},
+		{
+			fixture:  "set_code.json",
+			txType:   domain.TxTypeSetCode,
+			gasPrice: "1000000000",
+			fee:      "46000000000000",
+			check: func(t *testing.T, tx repository.Transaction) {
+				assert.Equal(t, []repository.Authorization{
+					{ChainID: "0x1", Address: "0x00000000000000000000000000000000000000cc", Nonce: "0xd", YParity: "0x1", R: "0x3", S: "0x4"},
+				}, tx.AuthorizationList)
+			},
+		},
+		{
+			fixture:  "deposit.json",
+			txType:   domain.TxTypeDeposit,
+			gasPrice: "0",
+			fee:      "0",
+			check: func(t *testing.T, tx repository.Transaction) {
+				assert.Equal(t, "0x5ca1ab1e00000000000000000000000000000000000000000000000000000001", tx.SourceHash)
+				assert.Equal(t, "1000000000000000000", tx.Mint.String())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fixture := loadTxTypeFixture(t, tt.fixture)
+			var item repository.TransactionItem
+			assert.NoError(t, json.Unmarshal(fixture.Transaction, &item))
+
+			mockClient := repoMock.NewMockETHClient(ctrl)
+			parser := NewEthereumParser(EthereumParserParam{EthClient: mockClient}).(*EthereumParser)
+
+			block := repository.Block{
+				Hash:         "0xb10c000000000000000000000000000000000000000000000000000000000001",
+				Number:       0x10,
+				Transactions: []repository.TransactionItem{item},
+			}
+			transactions := blockTransactions(block)
+			assert.Len(t, transactions, 1)
+
+			mockClient.EXPECT().CallEthereum("eth_getTransactionReceipt", []any{transactions[0].Hash}).
+				Return(json.RawMessage(`{"result": `+string(fixture.Receipt)+`}`), nil)
+			tx, err := parser.applyReceipt(transactions[0])
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.txType, tx.Type)
+			assert.Equal(t, tt.gasPrice, tx.GasPrice.String())
+			assert.Equal(t, tt.fee, tx.Fee.String())
+			assert.False(t, tx.Failed)
+			if tt.check != nil {
+				tt.check(t, tx)
+			}
+		})
+	}
+}
+
+func TestApplyTransactionType_UnknownType(t *testing.T) {
+	// 超過一個 byte 的未知類型保留原始值
+	var tx repository.Transaction
+	applyTransactionType(&tx, repository.TransactionItem{Type: 0x1ff})
+	assert.Equal(t, domain.TxType(0x1ff), tx.Type)
+	assert.False(t, tx.Type.Known())
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"parse_server/internal/domain"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+)
+
+// gweiToWei 1 gwei = 10^9 wei
+var gweiToWei = domain.BigIntFromUint64(1_000_000_000)
+
+// blockWithdrawals 將區塊中的驗證者提款轉為儲存格式，金額由 gwei 換算為 wei
+func blockWithdrawals(block repository.Block) []repository.Withdrawal {
+	reply := make([]repository.Withdrawal, 0, len(block.Withdrawals))
+	for _, item := range block.Withdrawals {
+		reply = append(reply, repository.Withdrawal{
+			BlockHash:      block.Hash.String(),
+			BlockNumber:    block.Number.String(),
+			Index:          item.Index.String(),
+			ValidatorIndex: item.ValidatorIndex.String(),
+			Address:        item.Address.String(),
+			Amount:         domain.BigIntFromUint64(uint64(item.Amount)).Mul(gweiToWei),
+		})
+	}
+
+	return reply
+}
+
+func toUsecaseWithdrawal(withdrawal repository.Withdrawal) usecase.Withdrawal {
+	return usecase.Withdrawal{
+		BlockHash:      withdrawal.BlockHash,
+		BlockNumber:    withdrawal.BlockNumber,
+		Index:          withdrawal.Index,
+		ValidatorIndex: withdrawal.ValidatorIndex,
+		Address:        withdrawal.Address,
+		Amount:         withdrawal.Amount,
+	}
+}
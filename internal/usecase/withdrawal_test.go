@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"parse_server/internal/domain/repository"
+	"parse_server/internal/domain/usecase"
+	"testing"
+
+	repoMock "parse_server/internal/mock/repository"
+	ucMock "parse_server/internal/mock/usecase"
+)
+
+func TestFetchTransactionsForAddress_Withdrawals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := repoMock.NewMockETHClient(ctrl)
+	mockStorage := repoMock.NewMockStorage(ctrl)
+	mockNotification := ucMock.NewMockNotification(ctrl)
+
+	parser := NewEthereumParser(EthereumParserParam{
+		Storage:      mockStorage,
+		Notification: mockNotification,
+		EthClient:    mockClient,
+	})
+
+	address := "0x0000000000000000000000000000000000000123"
+
+	mockClient.EXPECT().CallEthereum("eth_getBlockByNumber", gomock.Any()).Return(json.RawMessage(`{
+		"result": {
+			"hash": "0xabc1230000000000000000000000000000000000000000000000000000000000",
+			"number": "0x10",
+			"transactions": [],
+			"withdrawals": [
+				{"index": "0x1", "validatorIndex": "0x64", "address": "0x0000000000000000000000000000000000000123", "amount": "0xbebc200"},
+				{"index": "0x2", "validatorIndex": "0x65", "address": "0x0000000000000000000000000000000000000456", "amount": "0x1"}
+			],
+			"withdrawalsRoot": "0x1111111111111111111111111111111111111111111111111111111111111111"
+		}
+	}`), nil)
+	mockClient.EXPECT().CallEthereum("eth_getLogs", gomock.Any()).Return(json.RawMessage(`{"result": []}`), nil)
+
+	mockStorage.EXPECT().GetSubscription(address).Return(repository.Subscription{Address: address}, true)
+	mockStorage.EXPECT().GetPendingTransactions(address).Return(nil)
+
+	expected := repository.Withdrawal{
+		BlockHash:      "0xabc1230000000000000000000000000000000000000000000000000000000000",
+		BlockNumber:    "0x10",
+		Index:          "0x1",
+		ValidatorIndex: "0x64",
+		Address:        address,
+	}
+	mockStorage.EXPECT().SaveWithdrawal(address, gomock.Any()).Do(func(_ string, withdrawal repository.Withdrawal) {
+		// 0.2 ether = 200000000 gwei
+		assert.Equal(t, "200000000000000000", withdrawal.Amount.String())
+		withdrawal.Amount = expected.Amount
+		assert.Equal(t, expected, withdrawal)
+	})
+	mockNotification.EXPECT().NotifyWithdrawal(address, gomock.Any()).Do(func(_ string, withdrawal usecase.Withdrawal) {
+		assert.Equal(t, "0x64", withdrawal.ValidatorIndex)
+	})
+
+	parser.(*EthereumParser).FetchTransactionsForAddress(address)
+}